@@ -0,0 +1,236 @@
+// Website users can link the players they play as to their account. They get
+// a one-time code on the website and type it in-game ("link <code>"), which
+// binds that player's cookie to their account. Linked players are verified
+// (whois, their profile page), can use names reserved for their account and
+// show up on the user's dashboard.
+//
+// A cookie can only be linked to one account, linking it again moves it.
+package backend
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/packetflinger/q2admind/crypto"
+	"github.com/packetflinger/q2admind/frontend"
+)
+
+const (
+	linkCodeLength   = 8
+	linkCodeLifetime = 600                                // seconds
+	linkCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // nothing easily confused
+	linkMaxAttempts  = 5                                  // codes a player can try per connection
+)
+
+// AccountLink is a player's cookie bound to a website account
+type AccountLink struct {
+	ID      int64
+	Account string // user's email
+	Cookie  string
+	Profile int64  // player's profile when linked
+	Server  string // where they linked from
+	Created int64
+}
+
+// newLinkCode makes a random code from the link alphabet
+func newLinkCode() (string, error) {
+	b := crypto.RandomBytes(linkCodeLength)
+	if len(b) != linkCodeLength {
+		return "", fmt.Errorf("error generating link code")
+	}
+	for i := range b {
+		b[i] = linkCodeAlphabet[int(b[i])%len(linkCodeAlphabet)]
+	}
+	return string(b), nil
+}
+
+// CreateLinkCode makes a new one-time code for a website user to type
+// in-game. Any unused codes they already had stop working.
+func CreateLinkCode(account string) (string, int64, error) {
+	if account == "" {
+		return "", 0, fmt.Errorf("error creating link code: no account")
+	}
+	code, err := newLinkCode()
+	if err != nil {
+		return "", 0, err
+	}
+	now := time.Now().Unix()
+	expires := now + linkCodeLifetime
+	tx, err := db.Begin()
+	if err != nil {
+		return "", 0, fmt.Errorf("error creating link code: %v", err)
+	}
+	defer tx.Rollback()
+	_, err = tx.Exec("DELETE FROM link_code WHERE account = ? AND used = 0", account)
+	if err != nil {
+		return "", 0, fmt.Errorf("error creating link code: %v", err)
+	}
+	qry := "INSERT INTO link_code (code, account, created, expires) VALUES (?,?,?,?)"
+	if _, err = tx.Exec(qry, code, account, now, expires); err != nil {
+		return "", 0, fmt.Errorf("error creating link code: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return "", 0, fmt.Errorf("error creating link code: %v", err)
+	}
+	return code, expires, nil
+}
+
+// RedeemLinkCode uses up a code, returning the account it belongs to. Codes
+// are case insensitive.
+func RedeemLinkCode(code string, now int64) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	var id int64
+	var account string
+	qry := "SELECT id, account FROM link_code WHERE code = ? AND used = 0 AND expires >= ?"
+	if err := db.Handle.QueryRow(qry, code, now).Scan(&id, &account); err != nil {
+		return "", fmt.Errorf("invalid or expired code")
+	}
+	// someone else might have just used it
+	res, err := db.Handle.Exec("UPDATE link_code SET used = ? WHERE id = ? AND used = 0", now, id)
+	if err != nil {
+		return "", fmt.Errorf("error redeeming link code: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return "", fmt.Errorf("invalid or expired code")
+	}
+	return account, nil
+}
+
+// LinkAccount binds a player's cookie to a website account
+func LinkAccount(p *frontend.Player, account string) error {
+	if p == nil || p.Cookie == "" {
+		return fmt.Errorf("error linking account: player has no cookie")
+	}
+	server := ""
+	if p.Frontend != nil {
+		server = p.Frontend.Name
+	}
+	qry := `
+		INSERT INTO account_link (account, cookie, profile, server, created)
+		VALUES (?,?,?,?,?)
+		ON CONFLICT (cookie) DO UPDATE SET
+			account = excluded.account,
+			profile = excluded.profile,
+			server = excluded.server,
+			created = excluded.created`
+	_, err := db.Handle.Exec(qry, account, p.Cookie, p.ProfileID, server, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("error linking %s to %s: %v", p.Name, account, err)
+	}
+	p.Account = account
+	return nil
+}
+
+// LoadPlayerAccount looks up the website account a player's cookie is
+// linked to, if any.
+//
+// Called once a player's cookie is known
+func LoadPlayerAccount(p *frontend.Player) error {
+	if p == nil || p.Cookie == "" {
+		return nil
+	}
+	var account string
+	err := db.Handle.QueryRow("SELECT account FROM account_link WHERE cookie = ?", p.Cookie).Scan(&account)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("error loading account for %s: %v", p.Name, err)
+	}
+	p.Account = account
+	return nil
+}
+
+// LoadAccountLinks fetches the players linked to a website account, newest
+// first
+func LoadAccountLinks(account string) ([]AccountLink, error) {
+	var links []AccountLink
+	qry := `
+		SELECT id, account, cookie, profile, server, created
+		FROM account_link WHERE account = ? ORDER BY created DESC`
+	rows, err := db.Handle.Query(qry, account)
+	if err != nil {
+		return links, fmt.Errorf("error loading account links: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var l AccountLink
+		if err := rows.Scan(&l.ID, &l.Account, &l.Cookie, &l.Profile, &l.Server, &l.Created); err != nil {
+			return links, fmt.Errorf("error scanning account links: %v", err)
+		}
+		links = append(links, l)
+	}
+	return links, nil
+}
+
+// UnlinkAccount removes one of an account's links
+func UnlinkAccount(account string, id int64) error {
+	var cookie string
+	err := db.Handle.QueryRow("SELECT cookie FROM account_link WHERE id = ? AND account = ?", id, account).Scan(&cookie)
+	if err != nil {
+		return fmt.Errorf("no link %d on %s", id, account)
+	}
+	if _, err := db.Handle.Exec("DELETE FROM account_link WHERE id = ?", id); err != nil {
+		return fmt.Errorf("error removing link %d: %v", id, err)
+	}
+	for i := range be.frontends {
+		for j := range be.frontends[i].Players {
+			if p := &be.frontends[i].Players[j]; p.Cookie == cookie {
+				p.Account = ""
+			}
+		}
+	}
+	return nil
+}
+
+// profileLinked checks if anyone's linked a cookie from a profile to their
+// account
+func profileLinked(id int64) bool {
+	var count int
+	db.Handle.QueryRow("SELECT count(*) FROM account_link WHERE profile = ?", id).Scan(&count)
+	return count > 0
+}
+
+// Link is called when a player issues the link command in-game with the code
+// from the website.
+func Link(fe *frontend.Frontend) {
+	if fe == nil {
+		return
+	}
+	client := (&fe.Message).ReadByte()
+	code := strings.TrimSpace((&fe.Message).ReadString())
+	p, err := fe.FindPlayer(int(client))
+	if err != nil {
+		be.Logf(LogLevelInfo, "link error: %v\n", err)
+		return
+	}
+	if code == "" {
+		SayPlayer(fe, p, PRINT_HIGH, "Usage: link <code>, get a code from your dashboard on the website\n")
+		return
+	}
+	if p.Cookie == "" {
+		SayPlayer(fe, p, PRINT_HIGH, "Your client isn't set up yet, try again in a moment\n")
+		return
+	}
+	if p.LinkAttempts >= linkMaxAttempts {
+		SayPlayer(fe, p, PRINT_HIGH, "Too many link attempts, reconnect to try again\n")
+		return
+	}
+	p.LinkAttempts++
+
+	account, err := RedeemLinkCode(code, time.Now().Unix())
+	if err != nil {
+		SayPlayer(fe, p, PRINT_HIGH, fmt.Sprintf("Not linked: %v\n", err))
+		return
+	}
+	if err := LinkAccount(p, account); err != nil {
+		be.Logln(LogLevelInfo, err)
+		SayPlayer(fe, p, PRINT_HIGH, "Unable to link right now\n")
+		return
+	}
+	msg := fmt.Sprintf("%-20s[%d] %-20q linked to %s", "LINK:", p.ClientID, p.Name, account)
+	fe.Log.Printf("%s", msg)
+	fe.SSHPrintln(msg)
+	SayPlayer(fe, p, PRINT_HIGH, "Linked to your website account\n")
+	CheckReservedName(p)
+}
@@ -0,0 +1,88 @@
+package backend
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/packetflinger/q2admind/frontend"
+)
+
+func TestLinkCode(t *testing.T) {
+	useTestDatabase(t)
+	now := time.Now().Unix()
+
+	code, expires, err := CreateLinkCode("claire@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(code) != linkCodeLength || expires != now+linkCodeLifetime && expires != now+linkCodeLifetime+1 {
+		t.Errorf("CreateLinkCode() = %q, %d", code, expires)
+	}
+	if _, err := RedeemLinkCode(code, expires+1); err == nil {
+		t.Error("RedeemLinkCode() after it expired = nil, want error")
+	}
+	account, err := RedeemLinkCode(" "+strings.ToLower(code), now)
+	if err != nil || account != "claire@example.com" {
+		t.Errorf("RedeemLinkCode() = %q, %v, want claire@example.com", account, err)
+	}
+	if _, err := RedeemLinkCode(code, now); err == nil {
+		t.Error("RedeemLinkCode() a second time = nil, want error")
+	}
+
+	// a new code replaces an unused one
+	old, _, _ := CreateLinkCode("leon@example.com")
+	latest, _, _ := CreateLinkCode("leon@example.com")
+	if _, err := RedeemLinkCode(old, now); err == nil && old != latest {
+		t.Error("RedeemLinkCode() with a replaced code = nil, want error")
+	}
+	if _, err := RedeemLinkCode(latest, now); err != nil {
+		t.Errorf("RedeemLinkCode() with the latest code = %v", err)
+	}
+	if _, _, err := CreateLinkCode(""); err == nil {
+		t.Error("CreateLinkCode() without an account = nil, want error")
+	}
+}
+
+func TestLinkAccount(t *testing.T) {
+	useTestDatabase(t)
+	fe := &frontend.Frontend{Name: "test1"}
+	p := &frontend.Player{ClientID: 1, Name: "claire", Cookie: "aaaa", ProfileID: 5, Frontend: fe}
+	if err := LinkAccount(&frontend.Player{Name: "nocookie"}, "claire@example.com"); err == nil {
+		t.Error("LinkAccount() without a cookie = nil, want error")
+	}
+	if err := LinkAccount(p, "claire@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if !profileVerified(p) || !profileLinked(5) || profileLinked(6) {
+		t.Error("linked player isn't verified")
+	}
+
+	again := &frontend.Player{Name: "claire", Cookie: "aaaa"}
+	if err := LoadPlayerAccount(again); err != nil || again.Account != "claire@example.com" {
+		t.Errorf("LoadPlayerAccount() = %q, %v, want claire@example.com", again.Account, err)
+	}
+
+	// linking the same cookie again moves it
+	if err := LinkAccount(p, "leon@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if links, _ := LoadAccountLinks("claire@example.com"); len(links) != 0 {
+		t.Errorf("LoadAccountLinks() old account = %+v, want none", links)
+	}
+	links, err := LoadAccountLinks("leon@example.com")
+	if err != nil || len(links) != 1 || links[0].Server != "test1" || links[0].Profile != 5 {
+		t.Fatalf("LoadAccountLinks() = %+v, %v", links, err)
+	}
+
+	if err := UnlinkAccount("claire@example.com", links[0].ID); err == nil {
+		t.Error("UnlinkAccount() someone else's link = nil, want error")
+	}
+	if err := UnlinkAccount("leon@example.com", links[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	none := &frontend.Player{Cookie: "aaaa"}
+	if err := LoadPlayerAccount(none); err != nil || none.Account != "" {
+		t.Errorf("LoadPlayerAccount() after unlinking = %q, %v", none.Account, err)
+	}
+}
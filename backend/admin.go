@@ -0,0 +1,268 @@
+// Admins who are playing can moderate without leaving the game. The game
+// library forwards their "admin <command>" to us and we do it the same way
+// the SSH commands do:
+//
+//	kick <player> [reason]
+//	mute <player> <seconds>
+//	stifle <player> <seconds>
+//	ban <player> <minutes> [reason]
+//	map <name>
+//
+// Players are allowed if their linked website account is the frontend
+// owner's or a delegated user's with write access, or if they're in the
+// frontend's admin list (cookie:, profile: or account: identities like
+// reserved names use). Read and chat-only access isn't enough.
+//
+// Every action is logged and recorded in the admin log with who did it.
+package backend
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/packetflinger/q2admind/frontend"
+
+	pb "github.com/packetflinger/q2admind/proto"
+)
+
+// Admin commands
+const (
+	AdminKick   = "kick"
+	AdminMute   = "mute"
+	AdminStifle = "stifle"
+	AdminBan    = "ban"
+	AdminMap    = "map"
+)
+
+const adminBanMax = 7 * 24 * 60 // minutes, longer bans are done from SSH or the web
+
+var adminMapName = regexp.MustCompile(`^[A-Za-z0-9_\-/]+$`)
+
+// AdminAction is an entry in the admin log
+type AdminAction struct {
+	ID      int64
+	Server  string
+	Actor   string // identity of the admin
+	Action  string
+	Target  string // player name or map
+	Profile int64  // target player's profile
+	Detail  string
+	Time    int64
+}
+
+// ParseAdmins will read a frontend's admin list as it's stored in the
+// database or sent from the website, dropping anything that isn't a valid
+// identity.
+func ParseAdmins(in string) []string {
+	var admins []string
+	for _, a := range strings.Split(in, ",") {
+		a = strings.TrimSpace(a)
+		var r Reservation
+		if a == "" || r.SetIdentity(a) != nil {
+			continue
+		}
+		if strings.HasPrefix(a, "account:") {
+			a = "account:" + r.Account
+		}
+		admins = append(admins, a)
+	}
+	return admins
+}
+
+// roleAllowsAdmin checks if any of a user's roles are enough to moderate
+func roleAllowsAdmin(roles []*pb.Role) bool {
+	for _, r := range roles {
+		if r.GetDisabled() {
+			continue
+		}
+		if r.GetAccess() == pb.Access_Full || r.GetAccess() == pb.Access_Write {
+			return true
+		}
+	}
+	return false
+}
+
+// AdminIdentity finds who a player is allowed to use the admin commands as.
+// Empty if they're not allowed.
+func AdminIdentity(p *frontend.Player) string {
+	if p == nil || p.Frontend == nil {
+		return ""
+	}
+	fe := p.Frontend
+	if p.Account != "" {
+		account := "account:" + p.Account
+		if strings.EqualFold(p.Account, fe.Owner) {
+			return account
+		}
+		for u, roles := range fe.Users {
+			if strings.EqualFold(u.GetEmail(), p.Account) && roleAllowsAdmin(roles) {
+				return account
+			}
+		}
+		for email, write := range fe.WebUsers {
+			if write && strings.EqualFold(email, p.Account) {
+				return account
+			}
+		}
+	}
+	for _, a := range fe.Admins {
+		var r Reservation
+		if r.SetIdentity(a) == nil && r.Allows(p) {
+			return a
+		}
+	}
+	return ""
+}
+
+// LogAdminAction saves an entry to the admin log
+func LogAdminAction(a *AdminAction) error {
+	if a.Time == 0 {
+		a.Time = time.Now().Unix()
+	}
+	qry := `
+		INSERT INTO admin_log (server, actor, action, target, profile, detail, time)
+		VALUES (?,?,?,?,?,?,?)`
+	res, err := db.Handle.Exec(qry, a.Server, a.Actor, a.Action, a.Target, a.Profile, a.Detail, a.Time)
+	if err != nil {
+		return fmt.Errorf("error saving admin action: %v", err)
+	}
+	a.ID, err = res.LastInsertId()
+	return err
+}
+
+// LoadAdminLog fetches a frontend's most recent admin actions, newest first
+func LoadAdminLog(server string, limit int) ([]AdminAction, error) {
+	var actions []AdminAction
+	qry := `
+		SELECT id, server, actor, action, target, profile, detail, time
+		FROM admin_log WHERE server = ? ORDER BY id DESC LIMIT ?`
+	rows, err := db.Handle.Query(qry, server, limit)
+	if err != nil {
+		return actions, fmt.Errorf("error loading admin log: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a AdminAction
+		err := rows.Scan(&a.ID, &a.Server, &a.Actor, &a.Action, &a.Target, &a.Profile, &a.Detail, &a.Time)
+		if err != nil {
+			return actions, fmt.Errorf("error scanning admin log: %v", err)
+		}
+		actions = append(actions, a)
+	}
+	return actions, nil
+}
+
+// Admin is called when a player issues an admin command in-game
+func Admin(fe *frontend.Frontend) {
+	if fe == nil {
+		return
+	}
+	client := (&fe.Message).ReadByte()
+	text := (&fe.Message).ReadString()
+	p, err := fe.FindPlayer(int(client))
+	if err != nil {
+		be.Logf(LogLevelInfo, "admin error: %v\n", err)
+		return
+	}
+	actor := AdminIdentity(p)
+	if actor == "" {
+		SayPlayer(fe, p, PRINT_HIGH, "You're not an admin on this server\n")
+		return
+	}
+	a, err := AdminCommand(p, actor, text)
+	if err != nil {
+		SayPlayer(fe, p, PRINT_HIGH, fmt.Sprintf("admin: %v\n", err))
+		return
+	}
+	if a == nil {
+		return // they've already been told why
+	}
+	msg := fmt.Sprintf("%-20s[%d] %-20q %s %q %s (as %s)", "ADMIN:", p.ClientID, p.Name, a.Action, a.Target, a.Detail, actor)
+	fe.Log.Printf("%s", msg)
+	fe.SSHPrintln(msg)
+	if err := LogAdminAction(a); err != nil {
+		be.Logln(LogLevelInfo, err)
+	}
+	SayPlayer(fe, p, PRINT_HIGH, fmt.Sprintf("Done: %s %s\n", a.Action, a.Target))
+}
+
+// AdminCommand runs an in-game admin command for a player already allowed
+// to use them as actor, returning what was done. Nothing is returned if the
+// target couldn't be found, the player was already told.
+func AdminCommand(p *frontend.Player, actor string, text string) (*AdminAction, error) {
+	fe := p.Frontend
+	c, _ := ParseCmdArgs(text)
+	a := &AdminAction{Server: fe.Name, Actor: actor, Action: c.command}
+
+	if c.command == AdminMap {
+		if c.argc != 1 || !adminMapName.MatchString(c.argv[0]) {
+			return nil, fmt.Errorf("usage: admin map <name>")
+		}
+		a.Target = c.argv[0]
+		ConsoleCommand(fe, fmt.Sprintf("gamemap %s", c.argv[0]))
+		return a, nil
+	}
+
+	usage := map[string]string{
+		AdminKick:   "usage: admin kick <player> [reason]",
+		AdminMute:   "usage: admin mute <player> <seconds>",
+		AdminStifle: "usage: admin stifle <player> <seconds>",
+		AdminBan:    fmt.Sprintf("usage: admin ban <player> <minutes, up to %d> [reason]", adminBanMax),
+	}
+	if _, ok := usage[c.command]; !ok {
+		return nil, fmt.Errorf("usage: admin kick|mute|stifle|ban|map ...")
+	}
+	if c.argc == 0 || (c.command != AdminKick && c.argc < 2) {
+		return nil, fmt.Errorf("%s", usage[c.command])
+	}
+	var length int
+	if c.command != AdminKick {
+		n, err := strconv.Atoi(c.argv[1])
+		if err != nil || n < 0 || (c.command == AdminBan && (n == 0 || n > adminBanMax)) {
+			return nil, fmt.Errorf("%s", usage[c.command])
+		}
+		length = n
+	}
+	target := findTarget(fe, p, c.argv[0])
+	if target == nil {
+		return nil, nil
+	}
+	if target.ClientID == p.ClientID {
+		return nil, fmt.Errorf("you can't %s yourself", c.command)
+	}
+	if AdminIdentity(target) != "" {
+		return nil, fmt.Errorf("%s is an admin too", target.Name)
+	}
+	a.Target = target.Name
+	a.Profile = target.ProfileID
+
+	switch c.command {
+	case AdminKick:
+		a.Detail = strings.Join(c.argv[1:], " ")
+		KickPlayer(fe, target, a.Detail)
+	case AdminMute:
+		a.Detail = fmt.Sprintf("%ds", length)
+		MutePlayer(fe, target, length)
+		var expires int64
+		if length > 0 {
+			expires = time.Now().Unix() + int64(length)
+		}
+		if err := RecordOffense(target, OffenseMute, "", "in-game: "+actor, expires); err != nil {
+			be.Logln(LogLevelInfo, err)
+		}
+	case AdminStifle:
+		a.Detail = fmt.Sprintf("%ds", length)
+		StiflePlayer(fe, target, length)
+	case AdminBan:
+		reason := strings.Join(c.argv[2:], " ")
+		a.Detail = strings.TrimSpace(fmt.Sprintf("%dm %s", length, reason))
+		if _, err := TempBanPlayer(fe, target, actor, reason, int64(length)*60); err != nil {
+			be.Logln(LogLevelInfo, err)
+			return nil, fmt.Errorf("unable to ban %s right now", target.Name)
+		}
+	}
+	return a, nil
+}
@@ -0,0 +1,122 @@
+package backend
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"slices"
+	"testing"
+
+	"github.com/packetflinger/q2admind/frontend"
+
+	pb "github.com/packetflinger/q2admind/proto"
+)
+
+func testAdminFrontend(t *testing.T) *frontend.Frontend {
+	t.Helper()
+	return &frontend.Frontend{
+		Name:     "test1",
+		Owner:    "owner@example.com",
+		Path:     t.TempDir(),
+		Log:      log.New(io.Discard, "", 0),
+		Admins:   []string{"cookie:aaaa"},
+		WebUsers: map[string]bool{"writer@example.com": true, "reader@example.com": false},
+		Users: map[*pb.User][]*pb.Role{
+			{Email: "chat@example.com"}:     {{Access: pb.Access_Chat}},
+			{Email: "disabled@example.com"}: {{Access: pb.Access_Full, Disabled: true}},
+			{Email: "mod@example.com"}:      {{Access: pb.Access_Write, Context: pb.Context_SSH}},
+		},
+	}
+}
+
+func TestParseAdmins(t *testing.T) {
+	got := ParseAdmins(" cookie:aaaa, account:Owner@Example.com,bogus,, profile:x, profile:3")
+	want := []string{"cookie:aaaa", "account:owner@example.com", "profile:3"}
+	if !slices.Equal(got, want) {
+		t.Errorf("ParseAdmins() = %q, want %q", got, want)
+	}
+}
+
+func TestAdminIdentity(t *testing.T) {
+	fe := testAdminFrontend(t)
+	tests := []struct {
+		desc string
+		p    *frontend.Player
+		want string
+	}{
+		{"owner", &frontend.Player{Account: "Owner@example.com"}, "account:Owner@example.com"},
+		{"web write", &frontend.Player{Account: "writer@example.com"}, "account:writer@example.com"},
+		{"web read", &frontend.Player{Account: "reader@example.com"}, ""},
+		{"write role", &frontend.Player{Account: "mod@example.com"}, "account:mod@example.com"},
+		{"chat role", &frontend.Player{Account: "chat@example.com"}, ""},
+		{"disabled role", &frontend.Player{Account: "disabled@example.com"}, ""},
+		{"allowed cookie", &frontend.Player{Cookie: "aaaa"}, "cookie:aaaa"},
+		{"nobody", &frontend.Player{Cookie: "bbbb"}, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.p.Frontend = fe
+			if got := AdminIdentity(tc.p); got != tc.want {
+				t.Errorf("AdminIdentity() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAdminCommand(t *testing.T) {
+	useTestDatabase(t)
+	fe := testAdminFrontend(t)
+	fe.MaxPlayers = 4
+	fe.Players = make([]frontend.Player, fe.MaxPlayers)
+	for _, p := range []*frontend.Player{
+		{ClientID: 1, Name: "claire", Cookie: "aaaa", ConnectTime: 1},
+		{ClientID: 2, Name: "leon", Cookie: "bbbb", IP: "192.0.2.8", ConnectTime: 1},
+	} {
+		addTestPlayer(t, fe, p)
+		fe.Players[p.ClientID] = *p
+	}
+	admin, target := &fe.Players[1], &fe.Players[2]
+
+	for _, bad := range []string{"", "slap 2", "kick", "mute 2", "mute 2 x", "ban 2 0", "ban 2 99999", "map", "map q2dm1;quit", "kick 1"} {
+		if a, err := AdminCommand(admin, "cookie:aaaa", bad); err == nil || a != nil {
+			t.Errorf("AdminCommand(%q) = %+v, %v, want error", bad, a, err)
+		}
+	}
+
+	fe.MessageOut.Reset()
+	a, err := AdminCommand(admin, "cookie:aaaa", "mute leon 60")
+	if err != nil || a == nil || a.Target != "leon" || a.Detail != "60s" {
+		t.Fatalf("AdminCommand(mute) = %+v, %v", a, err)
+	}
+	if !bytes.Contains(fe.MessageOut.Data, []byte("sv !mute CL 2 60")) {
+		t.Errorf("AdminCommand(mute) sent %q", fe.MessageOut.Data)
+	}
+	if err := LogAdminAction(a); err != nil {
+		t.Fatal(err)
+	}
+
+	fe.MessageOut.Reset()
+	if _, err := AdminCommand(admin, "cookie:aaaa", "map q2dm1"); err != nil || !bytes.Contains(fe.MessageOut.Data, []byte("gamemap q2dm1")) {
+		t.Errorf("AdminCommand(map) = %v, sent %q", err, fe.MessageOut.Data)
+	}
+
+	fe.MessageOut.Reset()
+	a, err = AdminCommand(admin, "cookie:aaaa", "kick 2 camping")
+	if err != nil || a.Detail != "camping" || !bytes.Contains(fe.MessageOut.Data, []byte("kick 2")) {
+		t.Fatalf("AdminCommand(kick) = %+v, %v, sent %q", a, err, fe.MessageOut.Data)
+	}
+	if err := LogAdminAction(a); err != nil {
+		t.Fatal(err)
+	}
+
+	// admins can't act on each other
+	target.Cookie = "aaaa"
+	if _, err := AdminCommand(admin, "cookie:aaaa", "kick 2"); err == nil {
+		t.Error("AdminCommand() on another admin = nil, want error")
+	}
+
+	entries, err := LoadAdminLog("test1", 10)
+	if err != nil || len(entries) != 2 || entries[0].Action != AdminKick || entries[1].Actor != "cookie:aaaa" {
+		t.Errorf("LoadAdminLog() = %+v, %v", entries, err)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"path"
 	"runtime"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/packetflinger/q2admind/crypto"
 	"github.com/packetflinger/q2admind/database"
 	"github.com/packetflinger/q2admind/frontend"
+	"github.com/packetflinger/q2admind/maprotator"
 	"google.golang.org/protobuf/encoding/prototext"
 
 	pb "github.com/packetflinger/q2admind/proto"
@@ -29,8 +31,12 @@ import (
 
 // "This" admin server
 type Backend struct {
+	chat       *pb.ChatConfig      // cross-server chat bridge settings
+	discord    *pb.DiscordConfig   // discord integration settings
 	config     pb.Config           // global config
+	evasion    *pb.EvasionConfig   // ban evasion detection settings
 	frontends  []frontend.Frontend // managed quake 2 servers
+	geo        *GeoIP              // country/ASN lookups
 	maintCount int                 // total maintenance runs
 	privateKey *rsa.PrivateKey     // private to us
 	publicKey  *rsa.PublicKey      // known to clients
@@ -38,6 +44,8 @@ type Backend struct {
 	rpcStart   int64               // unix timestamp
 	rules      []*pb.Rule          // bans/mutes/etc
 	users      []*pb.User          // website users
+	vpn        *pb.VPNConfig       // VPN detection settings
+	vpnLookup  VPNProvider         // where VPN addresses are looked up
 }
 
 var (
@@ -97,6 +105,10 @@ const (
 	PCMDInvite
 	PCMDWhois
 	PCMDReport
+	PCMDStats
+	PCMDLink
+	PCMDAdmin
+	PCMDVote
 )
 
 // Print levels
@@ -271,7 +283,16 @@ func RotateKeys(fe *frontend.Frontend) {
 // Fetch all frontends from the database
 func (b *Backend) LoadFrontends() ([]frontend.Frontend, error) {
 	var fes []frontend.Frontend
-	qry := "SELECT * FROM frontend"
+	qry := `
+	SELECT
+		id, uuid, name, owner, enabled, description, allow_teleport,
+		allow_invite, ip_address, port, public_key_data, verified,
+		invite_tokens, invite_frequency, delete_protection, rating_pool,
+		whois_fields, chat_group, chat_trigger, discord_webhook,
+		discord_channel, discord_events, flood_chat, flood_userinfo,
+		flood_actions, admins, vote_enabled, vote_call, vote_candidates,
+		vote_duration, vote_quorum, vote_cooldown, vote_min_time
+	FROM frontend`
 	rs, err := db.Handle.Query(qry)
 	if err != nil {
 		return nil, fmt.Errorf("error selecting frontends: %v", err)
@@ -279,10 +300,22 @@ func (b *Backend) LoadFrontends() ([]frontend.Frontend, error) {
 	defer rs.Close()
 	for rs.Next() {
 		var fe frontend.Frontend
-		err = rs.Scan(&fe.ID, &fe.UUID, &fe.Name, &fe.Owner, &fe.Enabled, &fe.Description, &fe.AllowTeleport, &fe.AllowInvite, &fe.IPAddress, &fe.Port, &fe.PublicKeyData, &fe.Verified, &fe.Invites.Tokens, &fe.Invites.Freq, &fe.DeleteProtect)
+		var whois, events, floodChat, floodUserinfo, floodActions, admins string
+		vote := &pb.VoteConfig{}
+		err = rs.Scan(&fe.ID, &fe.UUID, &fe.Name, &fe.Owner, &fe.Enabled, &fe.Description, &fe.AllowTeleport, &fe.AllowInvite, &fe.IPAddress, &fe.Port, &fe.PublicKeyData, &fe.Verified, &fe.Invites.Tokens, &fe.Invites.Freq, &fe.DeleteProtect, &fe.RatingPool, &whois, &fe.ChatGroup, &fe.ChatTrigger, &fe.DiscordHook, &fe.DiscordChan, &events, &floodChat, &floodUserinfo, &floodActions, &admins,
+			&vote.Enabled, &vote.Call, &vote.Candidates, &vote.Duration, &vote.Quorum, &vote.Cooldown, &vote.MinTime)
 		if err != nil {
 			return fes, fmt.Errorf("error scanning frontend: %v", err)
 		}
+		fe.WhoisFields = ParseWhoisFields(whois)
+		fe.DiscordEvents = ParseDiscordEvents(events)
+		fe.Flood = ParseFloodConfig(floodChat, floodUserinfo, floodActions)
+		fe.Admins = ParseAdmins(admins)
+		fe.Vote = vote
+		fe.Maplist, err = LoadActiveRotation(fe.Name)
+		if err != nil {
+			be.Logln(LogLevelInfo, err)
+		}
 		fe.Data = &db
 		fes = append(fes, fe)
 	}
@@ -575,6 +608,12 @@ func (b *Backend) HandleConnection(c net.Conn) {
 	}
 	fe.ServerVars = vars
 
+	// before the rotation changes them
+	fe.DMFlags = maprotator.DefaultDMFlags
+	if flags, err := strconv.ParseInt(vars["dmflags"], 10, 64); err == nil {
+		fe.DMFlags = flags
+	}
+
 	// main connection loop for this frontend
 	// - wait for input
 	// - parse any messages received, react as necessary
@@ -612,6 +651,9 @@ func (b *Backend) HandleConnection(c net.Conn) {
 		ParseMessage(fe)
 		SendMessages(fe)
 	}
+	if err := EndMapPlay(fe); err != nil {
+		be.Logln(LogLevelInfo, err)
+	}
 	err = fe.Seen()
 	if err != nil {
 		be.Logln(LogLevelInfo, err)
@@ -667,6 +709,7 @@ func Shutdown() {
 			be.Logf(LogLevelNormal, "    error writing players for %q: %v", f.Name, err)
 		}
 	}
+	be.geo.Close()
 	be.Logf(LogLevelNormal, "  Closing database")
 	db.Handle.Close() // not sure if this is necessary
 }
@@ -746,6 +789,14 @@ func Startup(configFile string, foreground bool) {
 		log.Fatalln(err)
 	}
 
+	if be.config.GetGeoipDatabase() != "" || be.config.GetAsnDatabase() != "" {
+		be.Logf(LogLevelInfo, "%-21s %s %s\n", "loading geoip:", be.config.GetGeoipDatabase(), be.config.GetAsnDatabase())
+		be.geo, err = OpenGeoIP(be.config.GetGeoipDatabase(), be.config.GetAsnDatabase())
+		if err != nil {
+			log.Println(err)
+		}
+	}
+
 	be.Logf(LogLevelInfo, "%-21s %s\n", "loading global rules:", be.config.GetRuleFile())
 	rules, err := FetchRules(be.config.GetRuleFile())
 	if err != nil {
@@ -754,6 +805,43 @@ func Startup(configFile string, foreground bool) {
 		be.rules = rules
 	}
 
+	if be.config.GetEvasionFile() != "" {
+		be.Logf(LogLevelInfo, "%-21s %s\n", "loading evasion config:", be.config.GetEvasionFile())
+		be.evasion, err = ReadEvasionConfig(be.config.GetEvasionFile())
+		if err != nil {
+			log.Println(err)
+		}
+	}
+
+	if be.config.GetVpnFile() != "" {
+		be.Logf(LogLevelInfo, "%-21s %s\n", "loading vpn config:", be.config.GetVpnFile())
+		be.vpn, err = ReadVPNConfig(be.config.GetVpnFile())
+		if err != nil {
+			log.Println(err)
+		} else if be.vpn.GetEnabled() {
+			be.vpnLookup, err = NewVPNProvider(be.vpn)
+			if err != nil {
+				log.Println(err)
+			}
+		}
+	}
+
+	if be.config.GetChatFile() != "" {
+		be.Logf(LogLevelInfo, "%-21s %s\n", "loading chat config:", be.config.GetChatFile())
+		be.chat, err = ReadChatConfig(be.config.GetChatFile())
+		if err != nil {
+			log.Println(err)
+		}
+	}
+
+	if be.config.GetDiscordFile() != "" {
+		be.Logf(LogLevelInfo, "%-21s %s\n", "loading discord config:", be.config.GetDiscordFile())
+		be.discord, err = ReadDiscordConfig(be.config.GetDiscordFile())
+		if err != nil {
+			log.Println(err)
+		}
+	}
+
 	be.Logf(LogLevelInfo, "%-21s %s\n", "loading users:", be.config.GetUserFile())
 	users, err := api.ReadUsersFromDisk(be.config.GetUserFile())
 	if err != nil {
@@ -774,7 +862,7 @@ func Startup(configFile string, foreground bool) {
 		})
 		be.frontends = frontends
 		for _, c := range be.frontends {
-			be.Logf(LogLevelNormal, "  %-25s [%s:%d]", c.Name, c.IPAddress, c.Port)
+			be.Logf(LogLevelNormal, "  %-25s [%s]", c.Name, c.Address())
 		}
 	}
 
@@ -800,8 +888,10 @@ func Startup(configFile string, foreground bool) {
 	}
 
 	go be.startMaintenance()
+	go be.startScheduler()
 	go be.startRPCServer()
 	go be.startSSHServer()
+	go be.startDiscordRelay()
 
 	for {
 		c, err := listener.Accept()
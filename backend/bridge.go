@@ -0,0 +1,237 @@
+// The chat bridge joins the chat of frontends in the same chat group. Groups
+// belong to the frontend owner, other people's servers using the same group
+// name aren't joined to them. Players start a message with the group's
+// trigger (default "!g") and it's relayed to every other server in the group,
+// tagged with where it came from. SSH and web console users can read and
+// write the channel too.
+//
+// Muted players can't use the bridge and stifled players are limited to one
+// message per stifle period, same as in-game. Everyone else gets a few
+// messages in a short window before being told to slow down. Filtered words
+// are replaced with asterisks.
+package backend
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/packetflinger/q2admind/frontend"
+	"google.golang.org/protobuf/encoding/prototext"
+
+	pb "github.com/packetflinger/q2admind/proto"
+)
+
+// Where bridged messages not from a player come from
+const (
+	BridgeOriginConsole = "console"
+	BridgeOriginWeb     = "web"
+)
+
+const (
+	bridgeDefaultTrigger       = "!g"
+	bridgeDefaultFloodMessages = 3
+	bridgeDefaultFloodWindow   = 10 // seconds
+	bridgeDefaultMaxLength     = 150
+	bridgeHistoryLength        = 50 // messages kept per group
+)
+
+// BridgeMessage is a single message sent across a chat group
+type BridgeMessage struct {
+	Time   int64
+	Owner  string // who the group belongs to
+	Group  string
+	Origin string // frontend name, or console/web
+	Name   string // who said it
+	Text   string
+}
+
+// String is how the message is shown on every frontend in the group
+func (m BridgeMessage) String() string {
+	return fmt.Sprintf("[%s] %s: %s", m.Origin, m.Name, m.Text)
+}
+
+// recent messages for each owner's chat group, for consoles joining late
+var bridgeHistory = struct {
+	sync.Mutex
+	groups map[string][]BridgeMessage
+}{groups: make(map[string][]BridgeMessage)}
+
+// Load the chat bridge config proto from disk
+func ReadChatConfig(cfgfile string) (*pb.ChatConfig, error) {
+	cfg := &pb.ChatConfig{}
+	contents, err := os.ReadFile(cfgfile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open chat config: %v", err)
+	}
+	err = prototext.Unmarshal(contents, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling chat config: %v", err)
+	}
+	return cfg, nil
+}
+
+// bridgeTrigger is what a frontend's players start a message with to bridge
+// it
+func bridgeTrigger(fe *frontend.Frontend) string {
+	if fe.ChatTrigger != "" {
+		return fe.ChatTrigger
+	}
+	if t := be.chat.GetTrigger(); t != "" {
+		return t
+	}
+	return bridgeDefaultTrigger
+}
+
+// bridgeText checks if a chat message is meant for the bridge and returns
+// it without the trigger
+func bridgeText(trigger string, text string) (string, bool) {
+	rest, found := strings.CutPrefix(text, trigger+" ")
+	if !found {
+		return "", false
+	}
+	rest = strings.TrimSpace(rest)
+	return rest, rest != ""
+}
+
+// FilterChat replaces any filtered words in a message with asterisks,
+// ignoring case
+func FilterChat(text string, words []string) string {
+	for _, w := range words {
+		if w == "" {
+			continue
+		}
+		re := regexp.MustCompile("(?i)" + regexp.QuoteMeta(w))
+		text = re.ReplaceAllString(text, strings.Repeat("*", len(w)))
+	}
+	return text
+}
+
+// bridgeGroupKey is what a chat group's history is kept under. Owners are
+// compared ignoring case like everywhere else.
+func bridgeGroupKey(owner string, group string) string {
+	return strings.ToLower(owner) + "/" + group
+}
+
+// cleanBridgeText filters a message and cuts it to the max length in
+// characters, never splitting one
+func cleanBridgeText(text string) string {
+	limit := int(be.chat.GetMaxLength())
+	if limit <= 0 {
+		limit = bridgeDefaultMaxLength
+	}
+	if utf8.RuneCountInString(text) > limit {
+		n := 0
+		for i := range text {
+			if n == limit {
+				text = text[:i]
+				break
+			}
+			n++
+		}
+	}
+	return FilterChat(text, be.chat.GetFilter())
+}
+
+// bridgeFlooded decides if one more message would be too many in the
+// window. The times of the messages still in the window are returned with
+// now added if it's allowed.
+func bridgeFlooded(times []int64, now int64, limit int, window int64) ([]int64, bool) {
+	var recent []int64
+	for _, t := range times {
+		if now-t < window {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) >= limit {
+		return recent, true
+	}
+	return append(recent, now), false
+}
+
+// BridgeChat is run for every chat message a player sends. If it starts with
+// the trigger and the player is allowed to, it's relayed to their frontend's
+// chat group.
+//
+// Called from ParsePrint()
+func BridgeChat(fe *frontend.Frontend, p *frontend.Player, text string) {
+	if fe == nil || p == nil || fe.ChatGroup == "" || be.chat.GetDisabled() {
+		return
+	}
+	text, ok := bridgeText(bridgeTrigger(fe), text)
+	if !ok {
+		return
+	}
+	if p.Muted {
+		SayPlayer(fe, p, PRINT_HIGH, "You're muted, your message wasn't sent to the other servers\n")
+		return
+	}
+	limit, window := int(be.chat.GetFloodMessages()), int64(be.chat.GetFloodWindow())
+	if limit <= 0 {
+		limit = bridgeDefaultFloodMessages
+	}
+	if window <= 0 {
+		window = bridgeDefaultFloodWindow
+	}
+	if p.Stifled {
+		limit, window = 1, int64(p.StifleLength)
+	}
+	var flooded bool
+	p.BridgeChats, flooded = bridgeFlooded(p.BridgeChats, time.Now().Unix(), limit, window)
+	if flooded {
+		SayPlayer(fe, p, PRINT_HIGH, "Slow down, your message wasn't sent to the other servers\n")
+		return
+	}
+	RelayChat(BridgeMessage{
+		Owner:  fe.Owner,
+		Group:  fe.ChatGroup,
+		Origin: fe.Name,
+		Name:   p.Name,
+		Text:   text,
+	})
+}
+
+// RelayChat will send a message to every frontend in its owner's chat group
+// (other than where it came from) and every SSH console watching one of them.
+func RelayChat(msg BridgeMessage) {
+	if msg.Group == "" || msg.Text == "" {
+		return
+	}
+	msg.Text = cleanBridgeText(msg.Text)
+	if msg.Time == 0 {
+		msg.Time = time.Now().Unix()
+	}
+	key := bridgeGroupKey(msg.Owner, msg.Group)
+	bridgeHistory.Lock()
+	history := append(bridgeHistory.groups[key], msg)
+	if len(history) > bridgeHistoryLength {
+		history = history[len(history)-bridgeHistoryLength:]
+	}
+	bridgeHistory.groups[key] = history
+	bridgeHistory.Unlock()
+
+	for i := range be.frontends {
+		fe := &be.frontends[i]
+		if fe.ChatGroup != msg.Group || !strings.EqualFold(fe.Owner, msg.Owner) {
+			continue
+		}
+		fe.SSHPrintln("BRIDGE " + msg.String())
+		if fe.Name == msg.Origin || !fe.Connected {
+			continue
+		}
+		SayEveryone(fe, PRINT_CHAT, msg.String())
+	}
+	be.Logf(LogLevelNormal, "[%s/BRIDGE] %s\n", msg.Group, msg.String())
+}
+
+// BridgeHistory is the recent messages sent in an owner's chat group, oldest
+// first
+func BridgeHistory(owner string, group string) []BridgeMessage {
+	bridgeHistory.Lock()
+	defer bridgeHistory.Unlock()
+	return append([]BridgeMessage(nil), bridgeHistory.groups[bridgeGroupKey(owner, group)]...)
+}
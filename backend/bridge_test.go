@@ -0,0 +1,99 @@
+package backend
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/packetflinger/q2admind/frontend"
+)
+
+func TestBridgeText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+		ok   bool
+	}{
+		{"!g hello there", "hello there", true},
+		{"!g    ", "", false},
+		{"!ghello", "", false},
+		{"hello !g there", "", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.text, func(t *testing.T) {
+			got, ok := bridgeText("!g", tc.text)
+			if got != tc.want || ok != tc.ok {
+				t.Errorf("bridgeText(%q) = %q, %t, want %q, %t", tc.text, got, ok, tc.want, tc.ok)
+			}
+		})
+	}
+}
+
+func TestFilterChat(t *testing.T) {
+	got := FilterChat("You Noob, what a NOOB", []string{"noob", ""})
+	if want := "You ****, what a ****"; got != want {
+		t.Errorf("FilterChat() = %q, want %q", got, want)
+	}
+}
+
+func TestBridgeFlooded(t *testing.T) {
+	var times []int64
+	var flooded bool
+	for i := range 3 {
+		if times, flooded = bridgeFlooded(times, 100, 3, 10); flooded {
+			t.Fatalf("bridgeFlooded() message %d flooded, want allowed", i)
+		}
+	}
+	if times, flooded = bridgeFlooded(times, 105, 3, 10); !flooded {
+		t.Error("bridgeFlooded() 4th message allowed, want flooded")
+	}
+	if times, flooded = bridgeFlooded(times, 110, 3, 10); flooded || !slices.Equal(times, []int64{110}) {
+		t.Errorf("bridgeFlooded() after the window = %v, %t, want [110], false", times, flooded)
+	}
+}
+
+func TestRelayChat(t *testing.T) {
+	saved := be.frontends
+	t.Cleanup(func() { be.frontends = saved })
+	be.frontends = []frontend.Frontend{
+		{Name: "one", Owner: "leon@example.com", ChatGroup: "tdm", Connected: true},
+		{Name: "two", Owner: "Leon@example.com", ChatGroup: "tdm", Connected: true},
+		{Name: "three", Owner: "leon@example.com", ChatGroup: "ffa", Connected: true},
+		{Name: "four", Owner: "ada@example.com", ChatGroup: "tdm", Connected: true},
+	}
+
+	RelayChat(BridgeMessage{Owner: "leon@example.com", Group: "tdm", Origin: "one", Name: "claire", Text: "gg"})
+
+	if len(be.frontends[0].MessageOut.Data) != 0 {
+		t.Error("RelayChat() sent the message back to where it came from")
+	}
+	if !bytes.Contains(be.frontends[1].MessageOut.Data, []byte("[one] claire: gg")) {
+		t.Errorf("RelayChat() other group member got %q", be.frontends[1].MessageOut.Data)
+	}
+	if len(be.frontends[2].MessageOut.Data) != 0 {
+		t.Error("RelayChat() sent the message outside the group")
+	}
+	if len(be.frontends[3].MessageOut.Data) != 0 {
+		t.Error("RelayChat() sent the message to another owner's group with the same name")
+	}
+	history := BridgeHistory("LEON@example.com", "tdm")
+	if len(history) == 0 || history[len(history)-1].Text != "gg" || history[len(history)-1].Time == 0 {
+		t.Errorf("BridgeHistory() = %+v, want the relayed message", history)
+	}
+	if other := BridgeHistory("ada@example.com", "tdm"); len(other) != 0 {
+		t.Errorf("BridgeHistory() for another owner = %+v, want nothing", other)
+	}
+}
+
+func TestCleanBridgeText(t *testing.T) {
+	text := strings.Repeat("é", bridgeDefaultMaxLength+5)
+	got := cleanBridgeText(text)
+	if !utf8.ValidString(got) || utf8.RuneCountInString(got) != bridgeDefaultMaxLength {
+		t.Errorf("cleanBridgeText() kept %d characters (valid %t), want %d", utf8.RuneCountInString(got), utf8.ValidString(got), bridgeDefaultMaxLength)
+	}
+	if got := cleanBridgeText("short"); got != "short" {
+		t.Errorf("cleanBridgeText(%q) = %q", "short", got)
+	}
+}
@@ -0,0 +1,376 @@
+// Frontends can post events (chat, connects, map changes, bans and reports)
+// to a Discord channel and have a channel relayed back into the game. Events
+// go to the frontend's webhook if it has one, otherwise the bot posts them to
+// the frontend's channel. Relaying needs the bot, it polls the channel for new
+// messages and says them to everyone on the server.
+//
+// Posts are rate limited per frontend, anything over the limit is dropped
+// rather than queued so a busy server can't fall minutes behind. If Discord
+// asks us to back off we do.
+package backend
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/packetflinger/q2admind/frontend"
+	"google.golang.org/protobuf/encoding/prototext"
+
+	pb "github.com/packetflinger/q2admind/proto"
+)
+
+// The events frontends can post to Discord
+const (
+	DiscordChat    = "chat"
+	DiscordConnect = "connect"
+	DiscordMap     = "map"
+	DiscordBan     = "ban"
+	DiscordReport  = "report"
+	discordRelay   = "relay" // only a format, for messages coming from discord
+)
+
+const (
+	discordDefaultAPI          = "https://discord.com/api/v10"
+	discordDefaultPoll         = 5 // seconds
+	discordDefaultRateMessages = 5
+	discordDefaultRateWindow   = 5 // seconds
+	discordMessageLength       = 2000
+	discordPollLimit           = 50 // most messages fetched per poll
+)
+
+// DiscordEvents are all the events that can be posted, in display order
+var DiscordEvents = []string{DiscordChat, DiscordConnect, DiscordMap, DiscordBan, DiscordReport}
+
+var discordFormats = map[string]string{
+	DiscordChat:    "**{{.Name}}**: {{.Text}}",
+	DiscordConnect: "{{.Name}} connected",
+	DiscordMap:     "Map changed to {{.Text}}",
+	DiscordBan:     "{{.Name}} was banned: {{.Text}}",
+	DiscordReport:  "{{.Name}} was reported: {{.Text}}",
+	discordRelay:   "[discord] {{.Name}}: {{.Text}}",
+}
+
+// DiscordEvent is what the format templates are executed with
+type DiscordEvent struct {
+	Event  string
+	Server string // frontend name
+	Name   string // player (or discord user) involved
+	Text   string // chat, map name or reason
+}
+
+// discordMessage is the part of Discord's message object we use
+type discordMessage struct {
+	ID        string `json:"id"`
+	Content   string `json:"content"`
+	WebhookID string `json:"webhook_id,omitempty"`
+	Author    struct {
+		Username string `json:"username"`
+		Bot      bool   `json:"bot"`
+	} `json:"author"`
+}
+
+// per-frontend posting and polling state, keyed by frontend UUID
+var discordState = struct {
+	sync.Mutex
+	posts   map[string][]int64 // times of recent posts
+	blocked map[string]int64   // discord said to wait until then
+	after   map[string]string  // last channel message relayed
+}{
+	posts:   make(map[string][]int64),
+	blocked: make(map[string]int64),
+	after:   make(map[string]string),
+}
+
+var discordClient = http.Client{Timeout: time.Second * 5}
+
+// Load the discord config proto from disk
+func ReadDiscordConfig(cfgfile string) (*pb.DiscordConfig, error) {
+	cfg := &pb.DiscordConfig{}
+	contents, err := os.ReadFile(cfgfile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open discord config: %v", err)
+	}
+	err = prototext.Unmarshal(contents, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling discord config: %v", err)
+	}
+	return cfg, nil
+}
+
+// ParseDiscordEvents will turn a comma separated list of events (from the
+// database or the website) into the events a frontend posts. Unknown events
+// are dropped.
+func ParseDiscordEvents(in string) []string {
+	var events []string
+	for _, e := range strings.Split(in, ",") {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if slices.Contains(DiscordEvents, e) && !slices.Contains(events, e) {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// discordFormat is the template for an event, the config's if it has one
+func discordFormat(event string) string {
+	cfg := be.discord
+	custom := map[string]string{
+		DiscordChat:    cfg.GetChatFormat(),
+		DiscordConnect: cfg.GetConnectFormat(),
+		DiscordMap:     cfg.GetMapFormat(),
+		DiscordBan:     cfg.GetBanFormat(),
+		DiscordReport:  cfg.GetReportFormat(),
+		discordRelay:   cfg.GetRelayFormat(),
+	}[event]
+	if custom != "" {
+		return custom
+	}
+	return discordFormats[event]
+}
+
+// FormatDiscordEvent renders an event with its format, cut to the longest
+// message Discord accepts
+func FormatDiscordEvent(ev DiscordEvent) (string, error) {
+	tmpl, err := template.New(ev.Event).Parse(discordFormat(ev.Event))
+	if err != nil {
+		return "", fmt.Errorf("bad discord %s format: %v", ev.Event, err)
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, ev); err != nil {
+		return "", fmt.Errorf("error formatting discord %s: %v", ev.Event, err)
+	}
+	text := out.String()
+	if utf8.RuneCountInString(text) > discordMessageLength {
+		n := 0
+		for i := range text {
+			if n == discordMessageLength {
+				text = text[:i]
+				break
+			}
+			n++
+		}
+	}
+	return text, nil
+}
+
+// discordAllowed checks the frontend's rate limit and records a post if
+// there's room for one
+func discordAllowed(fe *frontend.Frontend, now int64) bool {
+	limit, window := int(be.discord.GetRateMessages()), int64(be.discord.GetRateWindow())
+	if limit <= 0 {
+		limit = discordDefaultRateMessages
+	}
+	if window <= 0 {
+		window = discordDefaultRateWindow
+	}
+	discordState.Lock()
+	defer discordState.Unlock()
+	if now < discordState.blocked[fe.UUID] {
+		return false
+	}
+	var flooded bool
+	discordState.posts[fe.UUID], flooded = bridgeFlooded(discordState.posts[fe.UUID], now, limit, window)
+	return !flooded
+}
+
+// discordBackoff honors a 429 from Discord, the frontend won't post again
+// until the retry time is up
+func discordBackoff(fe *frontend.Frontend, res *http.Response) {
+	wait, err := strconv.ParseFloat(res.Header.Get("Retry-After"), 64)
+	if err != nil || wait <= 0 {
+		wait = 1
+	}
+	discordState.Lock()
+	discordState.blocked[fe.UUID] = time.Now().Unix() + int64(wait+0.5)
+	discordState.Unlock()
+}
+
+// discordRequest calls the Discord API (or a webhook), the bot's token is
+// added for anything that isn't a webhook
+func discordRequest(method, url string, body any, bot bool) (*http.Response, error) {
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		payload = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, url, payload)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if bot {
+		req.Header.Set("Authorization", "Bot "+be.discord.GetBotToken())
+	}
+	return discordClient.Do(req)
+}
+
+// discordAPI is the base URL of the Discord API
+func discordAPI() string {
+	if u := be.discord.GetApiUrl(); u != "" {
+		return strings.TrimSuffix(u, "/")
+	}
+	return discordDefaultAPI
+}
+
+// PostDiscord sends an event to a frontend's webhook (or channel). Nothing is
+// sent if the frontend doesn't post that event or is over its rate limit.
+func PostDiscord(fe *frontend.Frontend, ev DiscordEvent) error {
+	if fe == nil || be.discord == nil || be.discord.GetDisabled() {
+		return nil
+	}
+	if !slices.Contains(fe.DiscordEvents, ev.Event) {
+		return nil
+	}
+	url, bot := fe.DiscordHook, false
+	if url == "" {
+		if fe.DiscordChan == "" || be.discord.GetBotToken() == "" {
+			return nil
+		}
+		url, bot = fmt.Sprintf("%s/channels/%s/messages", discordAPI(), fe.DiscordChan), true
+	}
+	if !discordAllowed(fe, time.Now().Unix()) {
+		return fmt.Errorf("discord rate limit reached for %s, %s dropped", fe.Name, ev.Event)
+	}
+	ev.Server = fe.Name
+	text, err := FormatDiscordEvent(ev)
+	if err != nil {
+		return err
+	}
+	body := map[string]any{
+		"content":          text,
+		"allowed_mentions": map[string][]string{"parse": {}}, // no @everyone from players
+	}
+	if !bot {
+		body["username"] = fe.Name
+	}
+	res, err := discordRequest(http.MethodPost, url, body, bot)
+	if err != nil {
+		return fmt.Errorf("error posting to discord: %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusTooManyRequests {
+		discordBackoff(fe, res)
+		return fmt.Errorf("discord rate limited %s", fe.Name)
+	}
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("error posting to discord: %s", res.Status)
+	}
+	return nil
+}
+
+// NotifyDiscord posts an event in the background so the frontend's
+// connection isn't held up waiting on Discord.
+//
+// Called when events happen on a frontend
+func NotifyDiscord(fe *frontend.Frontend, event string, name string, text string) {
+	if fe == nil || !slices.Contains(fe.DiscordEvents, event) {
+		return
+	}
+	go func() {
+		err := PostDiscord(fe, DiscordEvent{Event: event, Name: name, Text: text})
+		if err != nil {
+			be.Logln(LogLevelInfo, err)
+		}
+	}()
+}
+
+// PollDiscord fetches new messages from a frontend's channel and says them
+// to everyone on the server. The first poll only finds where the channel is
+// up to, older messages aren't relayed.
+func PollDiscord(fe *frontend.Frontend) error {
+	if fe == nil || fe.DiscordChan == "" || be.discord.GetBotToken() == "" || be.discord.GetDisabled() {
+		return nil
+	}
+	discordState.Lock()
+	after, started := discordState.after[fe.UUID]
+	discordState.Unlock()
+
+	url := fmt.Sprintf("%s/channels/%s/messages?limit=%d", discordAPI(), fe.DiscordChan, discordPollLimit)
+	if !started {
+		url = fmt.Sprintf("%s/channels/%s/messages?limit=1", discordAPI(), fe.DiscordChan)
+	} else if after != "" {
+		url += "&after=" + after
+	}
+	res, err := discordRequest(http.MethodGet, url, nil, true)
+	if err != nil {
+		return fmt.Errorf("error polling discord: %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("error polling discord channel %s: %s", fe.DiscordChan, res.Status)
+	}
+	var msgs []discordMessage
+	if err := json.NewDecoder(res.Body).Decode(&msgs); err != nil {
+		return fmt.Errorf("error decoding discord messages: %v", err)
+	}
+
+	// newest first
+	if len(msgs) > 0 {
+		after = msgs[0].ID
+	}
+	discordState.Lock()
+	discordState.after[fe.UUID] = after
+	discordState.Unlock()
+	if !started {
+		return nil
+	}
+
+	slices.Reverse(msgs)
+	for _, m := range msgs {
+		if m.Author.Bot || m.WebhookID != "" || strings.TrimSpace(m.Content) == "" {
+			continue // our own posts, or embeds/attachments only
+		}
+		text, err := FormatDiscordEvent(DiscordEvent{
+			Event:  discordRelay,
+			Server: fe.Name,
+			Name:   m.Author.Username,
+			Text:   cleanBridgeText(strings.ReplaceAll(m.Content, "\n", " ")),
+		})
+		if err != nil {
+			return err
+		}
+		fe.SSHPrintln("DISCORD " + text)
+		SayEveryone(fe, PRINT_CHAT, text)
+	}
+	return nil
+}
+
+// startDiscordRelay polls the channel of every connected frontend relaying
+// one. Run as a goroutine at startup.
+func (s *Backend) startDiscordRelay() {
+	if s.discord.GetBotToken() == "" {
+		return
+	}
+	interval := int(s.discord.GetPollInterval())
+	if interval <= 0 {
+		interval = discordDefaultPoll
+	}
+	for {
+		time.Sleep(time.Duration(interval) * time.Second)
+		for i := range s.frontends {
+			fe := &s.frontends[i]
+			if !fe.Connected || fe.DiscordChan == "" {
+				continue
+			}
+			if err := PollDiscord(fe); err != nil {
+				s.Logln(LogLevelInfo, err)
+			}
+		}
+	}
+}
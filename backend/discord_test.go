@@ -0,0 +1,223 @@
+package backend
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/packetflinger/q2admind/frontend"
+
+	pb "github.com/packetflinger/q2admind/proto"
+)
+
+// discordStandIn is a local stand-in for the Discord API. It records what's
+// posted to it and serves the channel messages it's given.
+type discordStandIn struct {
+	sync.Mutex
+	posts    []map[string]any
+	auth     []string
+	messages []discordMessage
+	status   int
+}
+
+func (d *discordStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.Lock()
+	defer d.Unlock()
+	d.auth = append(d.auth, r.Header.Get("Authorization"))
+	if d.status != 0 {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(d.status)
+		return
+	}
+	if r.Method == http.MethodPost {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		d.posts = append(d.posts, body)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	// newest first, like discord
+	var out []discordMessage
+	after := r.URL.Query().Get("after")
+	for i := len(d.messages) - 1; i >= 0; i-- {
+		if after == "" || d.messages[i].ID > after {
+			out = append(out, d.messages[i])
+		}
+	}
+	if r.URL.Query().Get("limit") == "1" && len(out) > 1 {
+		out = out[:1]
+	}
+	json.NewEncoder(w).Encode(out)
+}
+
+func useDiscordStandIn(t *testing.T, cfg *pb.DiscordConfig) (*discordStandIn, *httptest.Server) {
+	t.Helper()
+	d := &discordStandIn{}
+	srv := httptest.NewServer(d)
+	saved := be.discord
+	t.Cleanup(func() {
+		srv.Close()
+		be.discord = saved
+	})
+	cfg.ApiUrl = srv.URL
+	be.discord = cfg
+	return d, srv
+}
+
+func TestParseDiscordEvents(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"chat", []string{"chat"}},
+		{" Chat, ban,ban , relay,bogus", []string{"chat", "ban"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			if got := ParseDiscordEvents(tc.in); !slices.Equal(got, tc.want) {
+				t.Errorf("ParseDiscordEvents(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFormatDiscordEvent(t *testing.T) {
+	saved := be.discord
+	t.Cleanup(func() { be.discord = saved })
+
+	be.discord = &pb.DiscordConfig{}
+	got, err := FormatDiscordEvent(DiscordEvent{Event: DiscordBan, Name: "leon", Text: "aimbot"})
+	if err != nil || got != "leon was banned: aimbot" {
+		t.Errorf("FormatDiscordEvent() = %q, %v, want the default ban format", got, err)
+	}
+
+	be.discord = &pb.DiscordConfig{ChatFormat: "`{{.Server}}` {{.Name}} > {{.Text}}"}
+	got, err = FormatDiscordEvent(DiscordEvent{Event: DiscordChat, Server: "test1", Name: "claire", Text: "gg"})
+	if err != nil || got != "`test1` claire > gg" {
+		t.Errorf("FormatDiscordEvent() = %q, %v, want the configured chat format", got, err)
+	}
+
+	got, _ = FormatDiscordEvent(DiscordEvent{Event: DiscordChat, Text: strings.Repeat("x", 3000)})
+	if len(got) != discordMessageLength {
+		t.Errorf("FormatDiscordEvent() length = %d, want %d", len(got), discordMessageLength)
+	}
+	got, _ = FormatDiscordEvent(DiscordEvent{Event: DiscordChat, Text: strings.Repeat("é", 3000)})
+	if !utf8.ValidString(got) || utf8.RuneCountInString(got) != discordMessageLength {
+		t.Errorf("FormatDiscordEvent() cut multi-byte text to %d runes (valid %t), want %d", utf8.RuneCountInString(got), utf8.ValidString(got), discordMessageLength)
+	}
+
+	be.discord = &pb.DiscordConfig{MapFormat: "{{.Nope}}"}
+	if _, err := FormatDiscordEvent(DiscordEvent{Event: DiscordMap}); err == nil {
+		t.Error("FormatDiscordEvent() with a bad format = nil, want error")
+	}
+}
+
+func TestPostDiscord(t *testing.T) {
+	d, srv := useDiscordStandIn(t, &pb.DiscordConfig{BotToken: "sekrit", RateMessages: 2, RateWindow: 60})
+	fe := &frontend.Frontend{
+		Name:          "test1",
+		UUID:          "discord-post",
+		DiscordHook:   srv.URL + "/webhooks/1/abc",
+		DiscordEvents: []string{DiscordChat, DiscordMap},
+	}
+
+	if err := PostDiscord(fe, DiscordEvent{Event: DiscordConnect, Name: "claire"}); err != nil || len(d.posts) != 0 {
+		t.Errorf("PostDiscord() posted an event the frontend doesn't want: %v", err)
+	}
+	if err := PostDiscord(fe, DiscordEvent{Event: DiscordChat, Name: "claire", Text: "@everyone gg"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(d.posts) != 1 || d.posts[0]["content"] != "**claire**: @everyone gg" || d.posts[0]["username"] != "test1" {
+		t.Errorf("PostDiscord() webhook got %v", d.posts)
+	}
+	if _, ok := d.posts[0]["allowed_mentions"]; !ok {
+		t.Error("PostDiscord() didn't turn off mentions")
+	}
+	if d.auth[0] != "" {
+		t.Errorf("PostDiscord() sent the bot token to a webhook: %q", d.auth[0])
+	}
+
+	if err := PostDiscord(fe, DiscordEvent{Event: DiscordMap, Text: "q2dm1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := PostDiscord(fe, DiscordEvent{Event: DiscordMap, Text: "q2dm2"}); err == nil {
+		t.Error("PostDiscord() over the rate limit = nil, want error")
+	}
+	if len(d.posts) != 2 {
+		t.Errorf("PostDiscord() posts = %d, want 2", len(d.posts))
+	}
+
+	// no webhook, the bot posts to the channel
+	bot := &frontend.Frontend{Name: "test2", UUID: "discord-bot", DiscordChan: "42", DiscordEvents: []string{DiscordReport}}
+	if err := PostDiscord(bot, DiscordEvent{Event: DiscordReport, Name: "leon", Text: "wallhack"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := d.auth[len(d.auth)-1]; got != "Bot sekrit" {
+		t.Errorf("PostDiscord() bot authorization = %q", got)
+	}
+
+	// discord wants us to back off
+	d.status = http.StatusTooManyRequests
+	if err := PostDiscord(bot, DiscordEvent{Event: DiscordReport}); err == nil {
+		t.Error("PostDiscord() rate limited by discord = nil, want error")
+	}
+	d.status = 0
+	calls := len(d.auth)
+	PostDiscord(bot, DiscordEvent{Event: DiscordReport})
+	if len(d.auth) != calls {
+		t.Error("PostDiscord() didn't wait out discord's Retry-After")
+	}
+}
+
+func TestPollDiscord(t *testing.T) {
+	d, _ := useDiscordStandIn(t, &pb.DiscordConfig{BotToken: "sekrit"})
+	fe := &frontend.Frontend{Name: "test1", UUID: "discord-poll", DiscordChan: "42"}
+	msg := func(id, name, text string, bot bool) discordMessage {
+		m := discordMessage{ID: id, Content: text}
+		m.Author.Username = name
+		m.Author.Bot = bot
+		return m
+	}
+	d.messages = []discordMessage{msg("100", "ada", "old news", false)}
+
+	// first poll only catches up
+	if err := PollDiscord(fe); err != nil {
+		t.Fatal(err)
+	}
+	if len(fe.MessageOut.Data) != 0 {
+		t.Errorf("PollDiscord() relayed history: %q", fe.MessageOut.Data)
+	}
+
+	d.messages = append(d.messages,
+		msg("101", "ada", "first", false),
+		msg("102", "q2admin", "from a server", true),
+		msg("103", "grace", "second", false),
+	)
+	if err := PollDiscord(fe); err != nil {
+		t.Fatal(err)
+	}
+	out := fe.MessageOut.Data
+	first := bytes.Index(out, []byte("[discord] ada: first"))
+	second := bytes.Index(out, []byte("[discord] grace: second"))
+	if first < 0 || second < first {
+		t.Errorf("PollDiscord() relayed %q, want both messages in order", out)
+	}
+	if bytes.Contains(out, []byte("from a server")) || bytes.Contains(out, []byte("old news")) {
+		t.Errorf("PollDiscord() relayed a bot or old message: %q", out)
+	}
+
+	fe.MessageOut.Reset()
+	if err := PollDiscord(fe); err != nil {
+		t.Fatal(err)
+	}
+	if len(fe.MessageOut.Data) != 0 {
+		t.Errorf("PollDiscord() relayed messages twice: %q", fe.MessageOut.Data)
+	}
+}
@@ -25,7 +25,7 @@ func (e *Emailer) Send(recipient, subject, body string) error {
 	if err != nil {
 		return fmt.Errorf("error sending DATA command: %v", err)
 	}
-	_, err = fmt.Fprintln(wc, body)
+	_, err = fmt.Fprintf(wc, "From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s\r\n", e.From, recipient, subject, body)
 	if err != nil {
 		return fmt.Errorf("error writing email body: %v", err)
 	}
@@ -0,0 +1,351 @@
+// Ban evasion detection. Players who are banned or muted often come right
+// back with a new name, from a VPN or after clearing their cookie. Each new
+// connection is compared to the players with active sanctions and scored on
+// how much they have in common:
+//
+//   - same cookie: definitive
+//   - same identity profile: definitive
+//   - same network (/24 for IPv4, /64 for IPv6)
+//   - same PTR domain (same ISP)
+//   - same client version with a similar name
+//   - same userinfo fingerprint (skin, fov, rate, hand, etc)
+//
+// Scores at or above the configured threshold alert the SSH consoles and
+// optionally the frontend owner. The other signals are shared by plenty of
+// innocent players (same ISP, same popular client) so they only alert, the
+// automatic action needs the cookie or profile to match.
+package backend
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/packetflinger/q2admind/crypto"
+	"github.com/packetflinger/q2admind/frontend"
+	"google.golang.org/protobuf/encoding/prototext"
+
+	pb "github.com/packetflinger/q2admind/proto"
+)
+
+const (
+	evasionScoreCookie      = 1.0
+	evasionScoreProfile     = 1.0
+	evasionScoreNetwork     = 0.4
+	evasionScorePTR         = 0.2
+	evasionScoreClientName  = 0.3
+	evasionScoreFingerprint = 0.4
+	evasionDefaultThreshold = 0.7
+	evasionDefaultLookback  = 30 * 24 * 60 * 60 // 30 days
+)
+
+// userinfo keys that identify the person rather than their settings, these
+// are left out of the fingerprint
+var fingerprintIgnore = []string{"name", "ip", "port", "cl_cookie", "phash"}
+
+// EvasionSuspect is a previously sanctioned sighting that a new player has
+// a lot in common with.
+type EvasionSuspect struct {
+	Offense  Offense
+	Name     string
+	IP       string
+	Hostname string
+	Cookie   string
+	Version  string
+	Userinfo string
+	Profile  int64 // the sanctioned player's current profile
+	Score    float64
+	Signals  []string
+}
+
+// Load the evasion config proto from disk
+func ReadEvasionConfig(cfgfile string) (*pb.EvasionConfig, error) {
+	cfg := &pb.EvasionConfig{}
+	contents, err := os.ReadFile(cfgfile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open evasion config: %v", err)
+	}
+	err = prototext.Unmarshal(contents, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling evasion config: %v", err)
+	}
+	return cfg, nil
+}
+
+// UserinfoFingerprint is a hash of a player's client settings. Identifying
+// values (name, ip, cookie) are ignored so the fingerprint stays the same
+// when someone changes names or addresses. Too few settings aren't unique
+// enough to be useful, those get a blank fingerprint.
+func UserinfoFingerprint(info map[string]string) string {
+	var pairs []string
+	for k, v := range info {
+		if slices.Contains(fingerprintIgnore, k) {
+			continue
+		}
+		pairs = append(pairs, k+"="+v)
+	}
+	if len(pairs) < 4 {
+		return ""
+	}
+	slices.Sort(pairs)
+	return crypto.MD5Hash(strings.Join(pairs, "\\"))
+}
+
+// hostnameDomain gives the last two labels of a PTR record, usually the ISP's
+// domain. Unresolved addresses return blank.
+func hostnameDomain(host string, ip string) string {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "" || host == ip || !strings.Contains(host, ".") {
+		return ""
+	}
+	labels := strings.Split(host, ".")
+	if len(labels) < 2 {
+		return ""
+	}
+	return strings.Join(labels[len(labels)-2:], ".")
+}
+
+// normalizeName makes names comparable by dropping case, spaces, punctuation
+// and the high-bit (colored) characters quake allows.
+func normalizeName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		r = r & 0x7f
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// levenshtein is the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// SimilarNames decides if two player names are close enough to likely be the
+// same person: one containing the other ("claire" and "claire2") or only a
+// few characters different ("c1aire").
+func SimilarNames(a, b string) bool {
+	a, b = normalizeName(a), normalizeName(b)
+	if len(a) < 3 || len(b) < 3 {
+		return a == b && a != ""
+	}
+	if strings.Contains(a, b) || strings.Contains(b, a) {
+		return true
+	}
+	return levenshtein(a, b) <= max(1, min(len(a), len(b))/4)
+}
+
+// scoreEvasion compares a connecting player to a sanctioned one. Returns a
+// score from 0-1 and the names of the signals that matched.
+//
+// Called from CheckEvasion()
+func scoreEvasion(p *frontend.Player, s *EvasionSuspect) (float64, []string) {
+	var score float64
+	var signals []string
+	if p.Cookie != "" && p.Cookie == s.Cookie {
+		score += evasionScoreCookie
+		signals = append(signals, "cookie")
+	}
+	if p.ProfileID != 0 && p.ProfileID == s.Profile {
+		score += evasionScoreProfile
+		signals = append(signals, "profile")
+	}
+	pn, err1 := frontend.AddressPrefix(p.IP)
+	sn, err2 := frontend.AddressPrefix(s.IP)
+	if err1 == nil && err2 == nil && pn == sn {
+		score += evasionScoreNetwork
+		signals = append(signals, "network "+pn.String())
+	}
+	if d := hostnameDomain(p.Hostname, p.IP); d != "" && d == hostnameDomain(s.Hostname, s.IP) {
+		score += evasionScorePTR
+		signals = append(signals, "ptr "+d)
+	}
+	if p.Version != "" && p.Version == s.Version && SimilarNames(p.Name, s.Name) {
+		score += evasionScoreClientName
+		signals = append(signals, "client+name")
+	}
+	if fp := UserinfoFingerprint(p.UserinfoMap); fp != "" && fp == UserinfoFingerprint(frontend.UserinfoMap(s.Userinfo)) {
+		score += evasionScoreFingerprint
+		signals = append(signals, "userinfo")
+	}
+	return min(score, 1.0), signals
+}
+
+// offenseActive checks if a sanction is still in effect. Sanctions from rules
+// last until the rule is removed, disabled or expires. Only rules this
+// frontend uses are considered, so bans on other servers don't count.
+func offenseActive(o Offense, fe *frontend.Frontend, now int64) bool {
+	if o.Expires > 0 && now > o.Expires {
+		return false
+	}
+	if o.Rule == "" {
+		return o.Server == fe.Name
+	}
+	for _, r := range append(fe.Rules, be.rules...) {
+		if r.GetUuid() != o.Rule {
+			continue
+		}
+		if r.GetDisabled() {
+			return false
+		}
+		return r.GetExpirationTime() == 0 || now <= r.GetExpirationTime()
+	}
+	return false
+}
+
+// FindEvasionSuspects will compare a player to everyone with an active
+// sanction on their frontend and return the ones scoring at or above the
+// threshold, best match first. Sanctions from rules that already matched the
+// player are skipped, they're serving those rather than evading them.
+func FindEvasionSuspects(p *frontend.Player, cfg *pb.EvasionConfig) ([]EvasionSuspect, error) {
+	var suspects []EvasionSuspect
+	if p == nil || p.Frontend == nil {
+		return suspects, fmt.Errorf("error checking evasion: null player")
+	}
+	threshold := float64(cfg.GetThreshold())
+	if threshold <= 0 {
+		threshold = evasionDefaultThreshold
+	}
+	lookback := cfg.GetLookback()
+	if lookback <= 0 {
+		lookback = evasionDefaultLookback
+	}
+	now := time.Now().Unix()
+	qry := `
+		SELECT
+			o.id, o.player, o.profile, o.server, o.kind, o.rule, o.detail,
+			o.time, o.expires, p.name, p.ip, p.hostname, p.cookie, p.version,
+			p.userinfo, p.profile
+		FROM offense AS o
+		JOIN player AS p ON p.id = o.player
+		WHERE o.kind IN (?, ?) AND o.time > ? AND o.player != ?
+		ORDER BY o.time DESC`
+	rows, err := db.Handle.Query(qry, OffenseBan, OffenseMute, now-lookback, p.Database_ID)
+	if err != nil {
+		return suspects, fmt.Errorf("error finding offenses: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var s EvasionSuspect
+		o := &s.Offense
+		err := rows.Scan(&o.ID, &o.Player, &o.Profile, &o.Server, &o.Kind, &o.Rule,
+			&o.Detail, &o.Time, &o.Expires, &s.Name, &s.IP, &s.Hostname, &s.Cookie,
+			&s.Version, &s.Userinfo, &s.Profile)
+		if err != nil {
+			return suspects, fmt.Errorf("error scanning offenses: %v", err)
+		}
+		if !offenseActive(s.Offense, p.Frontend, now) {
+			continue
+		}
+		if o.Rule != "" && slices.ContainsFunc(p.Rules, func(r *pb.Rule) bool {
+			return r.GetUuid() == o.Rule
+		}) {
+			continue
+		}
+		s.Score, s.Signals = scoreEvasion(p, &s)
+		if s.Score >= threshold {
+			suspects = append(suspects, s)
+		}
+	}
+	slices.SortStableFunc(suspects, func(a, b EvasionSuspect) int {
+		switch {
+		case a.Score > b.Score:
+			return -1
+		case a.Score < b.Score:
+			return 1
+		}
+		return 0
+	})
+	return suspects, nil
+}
+
+// Identified checks if the suspect matched on something that identifies the
+// person rather than something they only share with them.
+func (s EvasionSuspect) Identified() bool {
+	return slices.Contains(s.Signals, "cookie") || slices.Contains(s.Signals, "profile")
+}
+
+// CheckEvasion is run for every connecting player who wasn't already banned
+// by a rule. The best matching suspect is reported to anyone watching and the
+// configured action is taken if they were identified.
+//
+// Called from ParseConnect()
+func CheckEvasion(p *frontend.Player) {
+	cfg := be.evasion
+	if !cfg.GetEnabled() || p == nil {
+		return
+	}
+	suspects, err := FindEvasionSuspects(p, cfg)
+	if err != nil {
+		be.Logln(LogLevelInfo, err)
+		return
+	}
+	if len(suspects) == 0 {
+		return
+	}
+	fe := p.Frontend
+	s := suspects[0]
+	msg := fmt.Sprintf("%-20s[%d] %-20q likely evading %s of %q (%.0f%%: %s)",
+		"EVASION:", p.ClientID, p.Name, s.Offense.Kind, s.Name, s.Score*100,
+		strings.Join(s.Signals, ", "))
+	fe.Log.Printf("%s", msg)
+	fe.SSHPrintln(msg)
+
+	if cfg.GetEmailOwner() && be.config.GetSmtpServer() != "" && fe.Owner != "" {
+		go func() {
+			e := Emailer{Server: be.config.GetSmtpServer(), From: be.config.GetSmtpFrom()}
+			subject := fmt.Sprintf("[%s] possible %s evasion by %s", fe.Name, s.Offense.Kind, p.Name)
+			if err := e.Send(fe.Owner, subject, msg); err != nil {
+				be.Logf(LogLevelInfo, "error emailing evasion alert for %q: %v", fe.Name, err)
+			}
+		}()
+	}
+
+	if !s.Identified() {
+		return
+	}
+
+	// the action is applied as if the original rule matched so it's
+	// recorded against the same sanction
+	rule := &pb.Rule{
+		Uuid:           s.Offense.Rule,
+		Message:        cfg.GetMessage(),
+		ExpirationTime: s.Offense.Expires,
+		StifleLength:   cfg.GetStifleLength(),
+		Description:    []string{"ban/mute evasion"},
+	}
+	switch cfg.GetAction() {
+	case pb.EvasionAction_EVASION_ACTION_KICK:
+		rule.Type = pb.RuleType_BAN
+	case pb.EvasionAction_EVASION_ACTION_MUTE:
+		rule.Type = pb.RuleType_MUTE
+	case pb.EvasionAction_EVASION_ACTION_STIFLE:
+		rule.Type = pb.RuleType_STIFLE
+	default:
+		return
+	}
+	p.Rules = append(p.Rules, rule)
+	ApplyMatchedRules(p, []*pb.Rule{rule})
+}
@@ -0,0 +1,232 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/packetflinger/q2admind/frontend"
+
+	pb "github.com/packetflinger/q2admind/proto"
+)
+
+const testUserinfo = `\name\claire\skin\female/athena\fov\95\rate\25000\hand\2\msg\1\ip\192.0.2.4:27901`
+
+func TestSimilarNames(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"claire", "claire", true},
+		{"Claire", "[clan]claire", true},
+		{"claire", "c1aire", true},
+		{"claire", "leon", false},
+		{"ab", "ab", true},
+		{"ab", "abc", false},
+		{"  ", "", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.a+"_"+tc.b, func(t *testing.T) {
+			if got := SimilarNames(tc.a, tc.b); got != tc.want {
+				t.Errorf("SimilarNames(%q, %q) = %t, want %t", tc.a, tc.b, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUserinfoFingerprint(t *testing.T) {
+	base := frontend.UserinfoMap(testUserinfo)
+	renamed := frontend.UserinfoMap(`\name\someone\skin\female/athena\fov\95\rate\25000\hand\2\msg\1\ip\198.51.100.9:27901`)
+	different := frontend.UserinfoMap(`\name\claire\skin\male/grunt\fov\95\rate\25000\hand\2\msg\1\ip\192.0.2.4:27901`)
+	sparse := frontend.UserinfoMap(`\name\claire\skin\male/grunt\ip\192.0.2.4:27901`)
+
+	fp := UserinfoFingerprint(base)
+	if fp == "" {
+		t.Fatal("UserinfoFingerprint() is blank")
+	}
+	if got := UserinfoFingerprint(renamed); got != fp {
+		t.Errorf("name/ip change altered fingerprint: %q != %q", got, fp)
+	}
+	if got := UserinfoFingerprint(different); got == fp {
+		t.Errorf("skin change didn't alter fingerprint")
+	}
+	if got := UserinfoFingerprint(sparse); got != "" {
+		t.Errorf("sparse userinfo fingerprint = %q, want blank", got)
+	}
+}
+
+func TestHostnameDomain(t *testing.T) {
+	tests := []struct {
+		host, ip, want string
+	}{
+		{"cpe-1-2-3-4.nyc.res.rr.com", "1.2.3.4", "rr.com"},
+		{"host.example.net.", "192.0.2.4", "example.net"},
+		{"192.0.2.4", "192.0.2.4", ""},
+		{"", "192.0.2.4", ""},
+		{"localhost", "127.0.0.1", ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.host, func(t *testing.T) {
+			if got := hostnameDomain(tc.host, tc.ip); got != tc.want {
+				t.Errorf("hostnameDomain(%q) = %q, want %q", tc.host, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestScoreEvasion(t *testing.T) {
+	banned := &EvasionSuspect{
+		Name:     "claire",
+		IP:       "192.0.2.4",
+		Hostname: "cpe-192-0-2-4.example.net",
+		Cookie:   "aaaa",
+		Version:  "q2pro r2000",
+		Userinfo: testUserinfo,
+		Profile:  7,
+	}
+	tests := []struct {
+		desc   string
+		player *frontend.Player
+		want   float64
+	}{
+		{
+			desc:   "nothing in common",
+			player: &frontend.Player{Name: "leon", IP: "198.51.100.9", Version: "r1q2"},
+			want:   0,
+		},
+		{
+			desc:   "cookie",
+			player: &frontend.Player{Name: "leon", IP: "198.51.100.9", Cookie: "aaaa"},
+			want:   1.0,
+		},
+		{
+			desc:   "same network and isp",
+			player: &frontend.Player{Name: "leon", IP: "192.0.2.99", Hostname: "cpe-192-0-2-99.example.net"},
+			want:   0.6,
+		},
+		{
+			desc:   "vpn with same client and similar name",
+			player: &frontend.Player{Name: "c1aire", IP: "198.51.100.9", Version: "q2pro r2000"},
+			want:   0.3,
+		},
+		{
+			desc: "vpn with same settings, client and name",
+			player: &frontend.Player{
+				Name:        "claire2",
+				IP:          "198.51.100.9",
+				Version:     "q2pro r2000",
+				UserinfoMap: frontend.UserinfoMap(testUserinfo),
+			},
+			want: 0.7,
+		},
+		{
+			desc:   "same profile",
+			player: &frontend.Player{Name: "leon", IP: "198.51.100.9", ProfileID: 7},
+			want:   1.0,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, signals := scoreEvasion(tc.player, banned)
+			if int(got*100) != int(tc.want*100) {
+				t.Errorf("scoreEvasion() = %.2f %v, want %.2f", got, signals, tc.want)
+			}
+		})
+	}
+}
+
+func TestEvasionSuspectIdentified(t *testing.T) {
+	tests := []struct {
+		signals []string
+		want    bool
+	}{
+		{[]string{"cookie"}, true},
+		{[]string{"network 192.0.2.0/24", "profile"}, true},
+		{[]string{"network 192.0.2.0/24", "userinfo"}, false},
+		{[]string{"ptr example.net", "client+name", "userinfo"}, false},
+	}
+	for _, tc := range tests {
+		s := EvasionSuspect{Score: 0.8, Signals: tc.signals}
+		if got := s.Identified(); got != tc.want {
+			t.Errorf("Identified() with %v = %t, want %t", tc.signals, got, tc.want)
+		}
+	}
+}
+
+func TestFindEvasionSuspects(t *testing.T) {
+	useTestDatabase(t)
+	now := time.Now().Unix()
+	fe := &frontend.Frontend{
+		Name: "test1",
+		Rules: []*pb.Rule{
+			{Uuid: "active", Type: pb.RuleType_BAN},
+			{Uuid: "expired", Type: pb.RuleType_BAN, ExpirationTime: now - 60},
+			{Uuid: "muted", Type: pb.RuleType_MUTE},
+		},
+	}
+
+	offenders := []struct {
+		player *frontend.Player
+		kind   string
+		rule   string
+	}{
+		{&frontend.Player{Name: "claire", IP: "192.0.2.4", Cookie: "aaaa"}, OffenseBan, "active"},
+		{&frontend.Player{Name: "leon", IP: "198.51.100.9", Cookie: "bbbb"}, OffenseBan, "expired"},
+		{&frontend.Player{Name: "ada", IP: "203.0.113.5", Cookie: "cccc"}, OffenseBan, "deleted"},
+		{&frontend.Player{Name: "rebecca", IP: "203.0.113.8", Cookie: "dddd"}, OffenseMute, "muted"},
+	}
+	for _, o := range offenders {
+		addTestPlayer(t, fe, o.player)
+		if err := RecordOffense(o.player, o.kind, o.rule, "", 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		desc   string
+		player *frontend.Player
+		want   int
+	}{
+		{
+			desc:   "active ban",
+			player: &frontend.Player{Name: "someone", IP: "198.51.100.200", Cookie: "aaaa"},
+			want:   1,
+		},
+		{
+			desc:   "expired ban",
+			player: &frontend.Player{Name: "someone", IP: "192.0.2.200", Cookie: "bbbb"},
+			want:   0,
+		},
+		{
+			desc:   "deleted rule",
+			player: &frontend.Player{Name: "someone", IP: "192.0.2.200", Cookie: "cccc"},
+			want:   0,
+		},
+		{
+			desc:   "evading a mute",
+			player: &frontend.Player{Name: "someone", IP: "192.0.2.200", Cookie: "dddd"},
+			want:   1,
+		},
+		{
+			desc: "reconnected while muted",
+			player: &frontend.Player{
+				Name:   "rebecca",
+				IP:     "203.0.113.8",
+				Cookie: "dddd",
+				Rules:  []*pb.Rule{fe.Rules[2]},
+			},
+			want: 0,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			addTestPlayer(t, fe, tc.player)
+			got, err := FindEvasionSuspects(tc.player, &pb.EvasionConfig{Enabled: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tc.want {
+				t.Errorf("FindEvasionSuspects() found %d suspects, want %d", len(got), tc.want)
+			}
+		})
+	}
+}
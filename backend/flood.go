@@ -1,7 +1,19 @@
+// Flood protection watches how fast each player chats and changes their name
+// or skin. Going over a frontend's limit within its sliding window is a flood
+// and gets a response, each flood the next one in the frontend's list (warn,
+// stifle, mute, kick by default). Players who behave for a while start back
+// at the first response. Flood stifles wear off on their own, stifles from
+// rules last the whole connection.
+//
+// Every flood is logged and recorded as an offense. Flood mutes are recorded
+// as mutes so they're considered by evasion detection like any other mute.
 package backend
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/packetflinger/q2admind/frontend"
@@ -9,16 +21,222 @@ import (
 	pb "github.com/packetflinger/q2admind/proto"
 )
 
-func TextFlood(player *frontend.Player, message string) {
-	now := time.Now().UnixMilli()
-	fmt.Println(now)
+// Responses to a flood
+const (
+	FloodWarn   = "warn"
+	FloodStifle = "stifle"
+	FloodMute   = "mute"
+	FloodKick   = "kick"
+)
+
+// What was flooded
+const (
+	FloodChat     = "chat"
+	FloodUserinfo = "userinfo"
+)
+
+// OffenseFlood is recorded for floods not ending in a mute
+const OffenseFlood = "flood"
+
+const (
+	floodDefaultChatMessages    = 5
+	floodDefaultChatWindow      = 3 // seconds
+	floodDefaultUserinfoChanges = 4
+	floodDefaultUserinfoWindow  = 10  // seconds
+	floodStifleLength           = 30  // seconds
+	floodStifleTime             = 300 // seconds a flood stifle lasts
+	floodMuteLength             = 120 // seconds
+	floodForgive                = 300 // seconds without flooding to start over
+)
+
+// FloodActions are the default responses, in the order they're given
+var FloodActions = []string{FloodWarn, FloodStifle, FloodMute, FloodKick}
+
+// ParseFloodLimit will read a limit written as count/seconds, like "5/3"
+func ParseFloodLimit(in string) (int32, int32, error) {
+	count, window, found := strings.Cut(strings.TrimSpace(in), "/")
+	if !found {
+		return 0, 0, fmt.Errorf("flood limit %q isn't count/seconds", in)
+	}
+	c, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil || c <= 0 {
+		return 0, 0, fmt.Errorf("bad flood limit count %q", count)
+	}
+	w, err := strconv.Atoi(strings.TrimSpace(window))
+	if err != nil || w <= 0 {
+		return 0, 0, fmt.Errorf("bad flood limit window %q", window)
+	}
+	return int32(c), int32(w), nil
 }
 
-func CheckTextFlood(info *pb.FloodInfo, when int64, tolerance int64) bool {
+// FormatFloodLimit is the opposite of ParseFloodLimit, empty for no limit
+func FormatFloodLimit(count int32, window int32) string {
+	if count <= 0 || window <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", count, window)
+}
+
+// ParseFloodConfig builds a frontend's flood settings from how they're
+// stored in the database or sent from the website. Anything missing or
+// invalid uses the default, nil means all defaults.
+func ParseFloodConfig(chat string, userinfo string, actions string) *pb.FloodConfig {
+	cfg := &pb.FloodConfig{}
+	cfg.ChatMessages, cfg.ChatWindow, _ = ParseFloodLimit(chat)
+	cfg.UserinfoChanges, cfg.UserinfoWindow, _ = ParseFloodLimit(userinfo)
+	for _, a := range strings.Split(actions, ",") {
+		a = strings.ToLower(strings.TrimSpace(a))
+		if slices.Contains(FloodActions, a) {
+			cfg.Actions = append(cfg.Actions, a)
+		}
+	}
+	if cfg.ChatMessages == 0 && cfg.UserinfoChanges == 0 && len(cfg.Actions) == 0 {
+		return nil
+	}
+	return cfg
+}
+
+// floodLimit is how many of something a player on the frontend can do in
+// how many milliseconds
+func floodLimit(fe *frontend.Frontend, kind string) (int, int64) {
+	cfg := fe.Flood
+	if kind == FloodUserinfo {
+		if cfg.GetUserinfoChanges() > 0 {
+			return int(cfg.GetUserinfoChanges()), int64(cfg.GetUserinfoWindow()) * 1000
+		}
+		return floodDefaultUserinfoChanges, floodDefaultUserinfoWindow * 1000
+	}
+	if cfg.GetChatMessages() > 0 {
+		return int(cfg.GetChatMessages()), int64(cfg.GetChatWindow()) * 1000
+	}
+	return floodDefaultChatMessages, floodDefaultChatWindow * 1000
+}
+
+// floodAction picks the response for a player's nth recent flood (from 0),
+// the frontend's last action repeats
+func floodAction(fe *frontend.Frontend, level int) string {
+	actions := fe.Flood.GetActions()
+	if len(actions) == 0 {
+		actions = FloodActions
+	}
+	return actions[min(level, len(actions)-1)]
+}
+
+// TextFlood is called for every chat line a player sends. It returns true
+// if the player is flooding, the line shouldn't be passed on (bridged, etc).
+//
+// Called from ParsePrint()
+func TextFlood(p *frontend.Player, message string) bool {
+	if p == nil || p.Frontend == nil {
+		return false
+	}
+	if p.FloodInfo == nil {
+		p.FloodInfo = &pb.FloodInfo{}
+	}
+	info := p.FloodInfo
+	now := time.Now().UnixMilli()
+	EndFloodStifle(p, now/1000)
+	if info.FirstPrintTime == 0 {
+		info.FirstPrintTime = now
+	}
+	info.LastPrintTime = now
 	info.PrintTotal++
-	if when-info.GetLastPrintTime() < tolerance {
-		return true
+
+	limit, window := floodLimit(p.Frontend, FloodChat)
+	var flooded bool
+	info.PrintTimes, flooded = bridgeFlooded(info.PrintTimes, now, limit, window)
+	if !flooded {
+		return false
+	}
+	info.PrintTimes = nil
+	FloodResponse(p, FloodChat, fmt.Sprintf("%d lines in %ds: %q", limit+1, window/1000, message), now)
+	return true
+}
+
+// UserinfoFlood is called when a player changes their name or skin. It
+// returns true if they're changing too often.
+//
+// Called from ParsePlayerUpdate()
+func UserinfoFlood(p *frontend.Player) bool {
+	if p == nil || p.Frontend == nil {
+		return false
+	}
+	if p.FloodInfo == nil {
+		p.FloodInfo = &pb.FloodInfo{}
+	}
+	info := p.FloodInfo
+	now := time.Now().UnixMilli()
+	if info.FirstUserinfoTime == 0 {
+		info.FirstUserinfoTime = now
+	}
+	info.LastUserinfoTime = now
+	info.UserinfoTotal++
+
+	limit, window := floodLimit(p.Frontend, FloodUserinfo)
+	var flooded bool
+	info.UserinfoTimes, flooded = bridgeFlooded(info.UserinfoTimes, now, limit, window)
+	if !flooded {
+		return false
+	}
+	info.UserinfoTimes = nil
+	FloodResponse(p, FloodUserinfo, fmt.Sprintf("%d name/skin changes in %ds", limit+1, window/1000), now)
+	return true
+}
+
+// EndFloodStifle lifts a stifle given for flooding once it's run out. Now is
+// in seconds.
+//
+// Called from TextFlood() and ParsePrint()
+func EndFloodStifle(p *frontend.Player, now int64) {
+	if !p.Stifled || p.StifledUntil == 0 || now < p.StifledUntil {
+		return
+	}
+	p.Stifled = false
+	p.StifledUntil = 0
+	p.StifleLength = 0
+	SayPlayer(p.Frontend, p, PRINT_HIGH, "You're no longer stifled\n")
+}
+
+// FloodResponse escalates a player's flood level and applies the matching
+// action, which is returned. Now is in milliseconds.
+func FloodResponse(p *frontend.Player, kind string, detail string, now int64) string {
+	fe := p.Frontend
+	info := p.FloodInfo
+	if now-info.GetLastFloodTime() > floodForgive*1000 {
+		info.Level = 0
+	}
+	action := floodAction(fe, int(info.Level))
+	info.Level++
+	info.LastFloodTime = now
+
+	offense, expires := OffenseFlood, int64(0)
+	switch action {
+	case FloodWarn:
+		SayPlayer(fe, p, PRINT_HIGH, "Stop flooding or you'll be silenced\n")
+	case FloodStifle:
+		if !p.Muted {
+			if !p.Stifled {
+				p.Stifled = true
+				p.StifledUntil = now/1000 + floodStifleTime
+				p.StifleLength = floodStifleLength
+			}
+			SayPlayer(fe, p, PRINT_CHAT, "You're stifled for flooding")
+			MutePlayer(fe, p, floodStifleLength)
+		}
+	case FloodMute:
+		offense, expires = OffenseMute, now/1000+floodMuteLength
+		SayPlayer(fe, p, PRINT_CHAT, fmt.Sprintf("You're muted for %ds for flooding", floodMuteLength))
+		MutePlayer(fe, p, floodMuteLength)
+	case FloodKick:
+		KickPlayer(fe, p, "Kicked for flooding")
+	}
+
+	msg := fmt.Sprintf("%-20s[%d] %-20q %s flood, %s (%s)", "FLOOD:", p.ClientID, p.Name, kind, action, detail)
+	fe.Log.Printf("%s", msg)
+	fe.SSHPrintln(msg)
+	err := RecordOffense(p, offense, "", fmt.Sprintf("%s flood: %s", kind, action), expires)
+	if err != nil {
+		be.Logln(LogLevelInfo, err)
 	}
-	info.LastPrintTime = when
-	return false
+	return action
 }
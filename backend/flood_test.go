@@ -1,8 +1,144 @@
 package backend
 
-import "testing"
+import (
+	"io"
+	"log"
+	"slices"
+	"testing"
+
+	"github.com/packetflinger/q2admind/frontend"
+
+	pb "github.com/packetflinger/q2admind/proto"
+)
+
+func testFloodPlayer(t *testing.T, cfg *pb.FloodConfig) *frontend.Player {
+	t.Helper()
+	fe := &frontend.Frontend{Name: "test1", Flood: cfg, Log: log.New(io.Discard, "", 0)}
+	p := &frontend.Player{ClientID: 2, Name: "spammer", IP: "192.0.2.8", Frontend: fe}
+	addTestPlayer(t, fe, p)
+	return p
+}
+
+func TestParseFloodConfig(t *testing.T) {
+	if cfg := ParseFloodConfig("", "", ""); cfg != nil {
+		t.Errorf("ParseFloodConfig() with nothing set = %v, want nil", cfg)
+	}
+	cfg := ParseFloodConfig(" 8/4", "bogus", "Stifle, kick, shout")
+	if cfg.GetChatMessages() != 8 || cfg.GetChatWindow() != 4 || cfg.GetUserinfoChanges() != 0 {
+		t.Errorf("ParseFloodConfig() limits = %v", cfg)
+	}
+	if !slices.Equal(cfg.GetActions(), []string{FloodStifle, FloodKick}) {
+		t.Errorf("ParseFloodConfig() actions = %q", cfg.GetActions())
+	}
+	if got := FormatFloodLimit(cfg.GetChatMessages(), cfg.GetChatWindow()); got != "8/4" {
+		t.Errorf("FormatFloodLimit() = %q, want 8/4", got)
+	}
+	for _, bad := range []string{"5", "0/3", "5/-1", "a/b"} {
+		if _, _, err := ParseFloodLimit(bad); err == nil {
+			t.Errorf("ParseFloodLimit(%q) = nil, want error", bad)
+		}
+	}
+}
 
 func TestTextFlood(t *testing.T) {
-	TextFlood(nil, "hi")
-	t.Fail()
+	useTestDatabase(t)
+	p := testFloodPlayer(t, &pb.FloodConfig{ChatMessages: 3, ChatWindow: 60})
+
+	for i := range 3 {
+		if TextFlood(p, "hi") {
+			t.Fatalf("TextFlood() line %d flooded, want allowed", i+1)
+		}
+	}
+	if !TextFlood(p, "hi") {
+		t.Fatal("TextFlood() 4th line allowed, want flooded")
+	}
+	if p.FloodInfo.GetLevel() != 1 || p.FloodInfo.GetPrintTotal() != 4 || p.Stifled {
+		t.Errorf("TextFlood() first flood = %v, stifled %t, want a warning", p.FloodInfo, p.Stifled)
+	}
+
+	// the window starts over after a flood
+	for range 3 {
+		TextFlood(p, "hi")
+	}
+	if !TextFlood(p, "hi") || !p.Stifled {
+		t.Error("TextFlood() second flood didn't stifle")
+	}
+
+	var kinds []string
+	rows, err := db.Handle.Query("SELECT kind FROM offense WHERE player = ? ORDER BY id", p.Database_ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var k string
+		rows.Scan(&k)
+		kinds = append(kinds, k)
+	}
+	if !slices.Equal(kinds, []string{OffenseFlood, OffenseFlood}) {
+		t.Errorf("TextFlood() offenses = %q, want two floods", kinds)
+	}
+}
+
+func TestUserinfoFlood(t *testing.T) {
+	useTestDatabase(t)
+	p := testFloodPlayer(t, &pb.FloodConfig{UserinfoChanges: 1, UserinfoWindow: 60, Actions: []string{FloodKick}})
+	if UserinfoFlood(p) {
+		t.Fatal("UserinfoFlood() first change flooded, want allowed")
+	}
+	if !UserinfoFlood(p) {
+		t.Fatal("UserinfoFlood() second change allowed, want flooded")
+	}
+	if p.FloodInfo.GetUserinfoTotal() != 2 || p.FloodInfo.GetPrintTotal() != 0 {
+		t.Errorf("UserinfoFlood() counters = %v", p.FloodInfo)
+	}
+}
+
+func TestFloodResponse(t *testing.T) {
+	useTestDatabase(t)
+	p := testFloodPlayer(t, nil)
+	p.FloodInfo = &pb.FloodInfo{}
+	now := int64(1_000_000_000)
+
+	var got []string
+	for i := range 5 {
+		got = append(got, FloodResponse(p, FloodChat, "test", now+int64(i)*1000))
+	}
+	want := []string{FloodWarn, FloodStifle, FloodMute, FloodKick, FloodKick}
+	if !slices.Equal(got, want) {
+		t.Errorf("FloodResponse() escalation = %q, want %q", got, want)
+	}
+
+	// behaving for a while starts over
+	later := now + (floodForgive+10)*1000
+	if action := FloodResponse(p, FloodChat, "test", later); action != FloodWarn {
+		t.Errorf("FloodResponse() after forgiving = %q, want %q", action, FloodWarn)
+	}
+}
+
+func TestEndFloodStifle(t *testing.T) {
+	useTestDatabase(t)
+	p := testFloodPlayer(t, &pb.FloodConfig{Actions: []string{FloodStifle}})
+	p.FloodInfo = &pb.FloodInfo{}
+	now := int64(1_000_000_000)
+	FloodResponse(p, FloodChat, "test", now*1000)
+	if !p.Stifled || p.StifledUntil != now+floodStifleTime {
+		t.Fatalf("flood stifle = %t until %d, want until %d", p.Stifled, p.StifledUntil, now+floodStifleTime)
+	}
+	EndFloodStifle(p, now+floodStifleTime-1)
+	if !p.Stifled {
+		t.Error("EndFloodStifle() lifted the stifle early")
+	}
+	EndFloodStifle(p, now+floodStifleTime)
+	if p.Stifled || p.StifleLength != 0 {
+		t.Errorf("EndFloodStifle() after it ran out = %t/%d, want lifted", p.Stifled, p.StifleLength)
+	}
+
+	// stifles from rules aren't ours to lift, and flooding doesn't shorten them
+	p.Stifled, p.StifleLength = true, 60
+	FloodResponse(p, FloodChat, "test", now*1000)
+	EndFloodStifle(p, now+2*floodStifleTime)
+	if !p.Stifled || p.StifleLength != 60 {
+		t.Errorf("EndFloodStifle() rule stifle = %t/%d, want left alone", p.Stifled, p.StifleLength)
+	}
 }
@@ -0,0 +1,112 @@
+package backend
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// GeoIP resolves the country and network operator of a player's address
+// using local MaxMind-format (mmdb) database files. Nothing is looked up
+// remotely, so resolution is quick enough to happen inline with a connect.
+//
+// The country and ASN data usually come in separate files (GeoLite2-Country
+// and GeoLite2-ASN for example), but a combined database can be used for
+// both by setting the same file in the config.
+type GeoIP struct {
+	country *maxminddb.Reader
+	asn     *maxminddb.Reader
+}
+
+// GeoRecord is the location/network data for a single address
+type GeoRecord struct {
+	Country string // ISO 3166-1 alpha-2 code ("US", "DE", etc)
+	ASN     uint32 // autonomous system number
+	ASNOrg  string // the organization that owns the ASN
+}
+
+// The subset of the mmdb record structure we care about. Field names match
+// the MaxMind GeoLite2/GeoIP2 schema.
+type mmdbRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	RegisteredCountry struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"registered_country"`
+	ASN    uint32 `maxminddb:"autonomous_system_number"`
+	ASNOrg string `maxminddb:"autonomous_system_organization"`
+}
+
+// OpenGeoIP will load the country and ASN databases. Either can be blank if
+// that data isn't wanted, but not both.
+//
+// Called from Startup()
+func OpenGeoIP(countryFile, asnFile string) (*GeoIP, error) {
+	if countryFile == "" && asnFile == "" {
+		return nil, fmt.Errorf("no geoip database files specified")
+	}
+	g := &GeoIP{}
+	var err error
+	if countryFile != "" {
+		g.country, err = maxminddb.Open(countryFile)
+		if err != nil {
+			return nil, fmt.Errorf("error opening geoip country database %q: %v", countryFile, err)
+		}
+	}
+	if asnFile != "" {
+		g.asn, err = maxminddb.Open(asnFile)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("error opening geoip asn database %q: %v", asnFile, err)
+		}
+	}
+	return g, nil
+}
+
+// Lookup will resolve an IP address to a country and ASN. Addresses not found
+// in the databases (private ranges, etc) result in an empty record and no
+// error.
+func (g *GeoIP) Lookup(ip string) (GeoRecord, error) {
+	var rec GeoRecord
+	if g == nil {
+		return rec, fmt.Errorf("geoip lookup: databases not loaded")
+	}
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return rec, fmt.Errorf("geoip lookup: invalid address %q", ip)
+	}
+	if g.country != nil {
+		var r mmdbRecord
+		if err := g.country.Lookup(addr, &r); err != nil {
+			return rec, fmt.Errorf("geoip country lookup for %q: %v", ip, err)
+		}
+		rec.Country = r.Country.ISOCode
+		if rec.Country == "" {
+			rec.Country = r.RegisteredCountry.ISOCode
+		}
+	}
+	if g.asn != nil {
+		var r mmdbRecord
+		if err := g.asn.Lookup(addr, &r); err != nil {
+			return rec, fmt.Errorf("geoip asn lookup for %q: %v", ip, err)
+		}
+		rec.ASN = r.ASN
+		rec.ASNOrg = r.ASNOrg
+	}
+	return rec, nil
+}
+
+// Close the underlying database files
+func (g *GeoIP) Close() {
+	if g == nil {
+		return
+	}
+	if g.country != nil {
+		g.country.Close()
+	}
+	if g.asn != nil {
+		g.asn.Close()
+	}
+}
@@ -51,7 +51,7 @@ func Invite(fe *frontend.Frontend) {
 		}
 	}
 
-	inv := fmt.Sprintf("%s invites you to play at %s (%s:%d)", p.Name, fe.Name, fe.IPAddress, fe.Port)
+	inv := fmt.Sprintf("%s invites you to play at %s (%s)", p.Name, fe.Name, fe.Address())
 	for _, s := range be.frontends {
 		if s.Enabled && s.Connected && s.AllowInvite {
 			SayEveryone(&s, PRINT_CHAT, inv)
@@ -0,0 +1,252 @@
+// Leaderboards rank player profiles by frags over a period of time, either
+// overall or for a single weapon, on one frontend or across all of them.
+// Bots and short sessions (joining just long enough to grab a few frags)
+// aren't counted.
+package backend
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/packetflinger/q2admind/frontend"
+)
+
+// Leaderboard periods
+const (
+	LeaderboardDaily   = "daily"
+	LeaderboardWeekly  = "weekly"
+	LeaderboardAllTime = "all"
+)
+
+const (
+	leaderboardMinSession = 120 // seconds, shorter sessions don't count
+	leaderboardSize       = 10
+
+	// in-game output for a leaderboard
+	leaderboardTemplate = `
+Top players ({{ .Period }}{{ if .Weapon }}, {{ .Weapon }}{{ end }}):
+  #  name             frags deaths   kdr
+  -- ---------------- ----- ------ -----
+{{ range .Entries }}  {{ printf "%-2d" .Rank }} {{ printf "%-16.16s" .Name }} {{ printf "%5d" .Frags }} {{ printf "%6d" .Deaths }} {{ printf "%5.2f" .KDR }}
+{{ else }}  nobody yet
+{{ end }}`
+
+	// in-game output for a player's own weapon stats
+	weaponTemplate = `
+Your weapon stats:
+  weapon            frags deaths
+  ----------------- ----- ------
+{{ range . }}  {{ printf "%-17s" .Weapon }} {{ printf "%5d" .Frags }} {{ printf "%6d" .Deaths }}
+{{ else }}  nothing recorded yet
+{{ end }}`
+)
+
+// Leaderboard is a ranked list of the top profiles for a period
+type Leaderboard struct {
+	Server  string // frontend name, blank for all of them
+	Period  string // daily, weekly or all
+	Weapon  string // blank for overall
+	Entries []LeaderboardEntry
+}
+
+// LeaderboardEntry is a single profile's totals on a leaderboard
+type LeaderboardEntry struct {
+	Rank     int
+	Profile  int64
+	Name     string // most recently used name
+	Frags    int
+	Deaths   int
+	Suicides int
+	KDR      float64
+	PlayTime int64 // seconds, not tracked per weapon
+}
+
+// WeaponTotal is how many frags and deaths a profile has with a weapon
+type WeaponTotal struct {
+	Weapon string
+	Frags  int
+	Deaths int
+}
+
+// LeaderboardPeriods are all the valid periods, in display order
+var LeaderboardPeriods = []string{LeaderboardDaily, LeaderboardWeekly, LeaderboardAllTime}
+
+// periodStart gives the unix timestamp a leaderboard period begins. Daily
+// starts at midnight, weekly on the most recent Monday.
+func periodStart(period string, now time.Time) (int64, error) {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch period {
+	case LeaderboardDaily:
+		return midnight.Unix(), nil
+	case LeaderboardWeekly:
+		days := (int(now.Weekday()) + 6) % 7 // days since monday
+		return midnight.AddDate(0, 0, -days).Unix(), nil
+	case LeaderboardAllTime, "":
+		return 0, nil
+	}
+	return 0, fmt.Errorf("unknown leaderboard period %q", period)
+}
+
+// ratio is frags per death, frags alone if never fragged
+func ratio(frags, deaths int) float64 {
+	if deaths == 0 {
+		return float64(frags)
+	}
+	return float64(frags) / float64(deaths)
+}
+
+// LoadLeaderboard will rank the top profiles by frags for the given period.
+// Server limits it to a single frontend and weapon limits it to frags with
+// that weapon (see frontend.WeaponNames()). Ties go to whoever died less.
+func LoadLeaderboard(server, period, weapon string, limit int) (Leaderboard, error) {
+	lb := Leaderboard{Server: server, Period: period, Weapon: weapon}
+	if lb.Period == "" {
+		lb.Period = LeaderboardAllTime
+	}
+	if limit <= 0 {
+		limit = leaderboardSize
+	}
+	since, err := periodStart(lb.Period, time.Now())
+	if err != nil {
+		return lb, err
+	}
+	if weapon != "" && !slices.Contains(frontend.WeaponNames(), weapon) {
+		return lb, fmt.Errorf("unknown weapon %q", weapon)
+	}
+
+	var qry strings.Builder
+	args := []any{}
+	if weapon == "" {
+		qry.WriteString(`
+			SELECT p.profile, pr.name, SUM(s.frags) AS total_frags, SUM(s.deaths) AS total_deaths,
+				SUM(s.suicides), SUM(s.play_time)
+			FROM player_stat AS s
+			JOIN player AS p ON p.id = s.player
+			JOIN profile AS pr ON pr.id = p.profile
+			WHERE p.bot = 0 AND s.play_time >= ? AND s.time >= ?`)
+		args = append(args, leaderboardMinSession, since)
+	} else {
+		qry.WriteString(`
+			SELECT p.profile, pr.name, SUM(w.frags) AS total_frags, SUM(w.deaths) AS total_deaths, 0, 0
+			FROM weapon_stat AS w
+			JOIN player_stat AS s ON s.id = w.session
+			JOIN player AS p ON p.id = s.player
+			JOIN profile AS pr ON pr.id = p.profile
+			WHERE p.bot = 0 AND s.play_time >= ? AND s.time >= ? AND w.weapon = ?`)
+		args = append(args, leaderboardMinSession, since, weapon)
+	}
+	if server != "" {
+		qry.WriteString(" AND p.server = ?")
+		args = append(args, server)
+	}
+	qry.WriteString(`
+			GROUP BY p.profile
+			HAVING total_frags > 0
+			ORDER BY total_frags DESC, total_deaths ASC, p.profile ASC
+			LIMIT ?`)
+	args = append(args, limit)
+
+	rows, err := db.Handle.Query(qry.String(), args...)
+	if err != nil {
+		return lb, fmt.Errorf("error loading leaderboard: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var e LeaderboardEntry
+		err := rows.Scan(&e.Profile, &e.Name, &e.Frags, &e.Deaths, &e.Suicides, &e.PlayTime)
+		if err != nil {
+			return lb, fmt.Errorf("error scanning leaderboard: %v", err)
+		}
+		e.Rank = len(lb.Entries) + 1
+		e.KDR = ratio(e.Frags, e.Deaths)
+		lb.Entries = append(lb.Entries, e)
+	}
+	return lb, nil
+}
+
+// ProfileWeaponStats will total a profile's frags and deaths for each weapon,
+// most frags first. Server limits it to a single frontend.
+func ProfileWeaponStats(profile int64, server string) ([]WeaponTotal, error) {
+	var totals []WeaponTotal
+	qry := `
+		SELECT w.weapon, SUM(w.frags), SUM(w.deaths)
+		FROM weapon_stat AS w
+		JOIN player AS p ON p.id = w.player
+		WHERE p.profile = ? AND (? = '' OR p.server = ?)
+		GROUP BY w.weapon
+		ORDER BY 2 DESC, 3 ASC, w.weapon ASC`
+	rows, err := db.Handle.Query(qry, profile, server, server)
+	if err != nil {
+		return totals, fmt.Errorf("error loading weapon stats for profile %d: %v", profile, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var t WeaponTotal
+		if err := rows.Scan(&t.Weapon, &t.Frags, &t.Deaths); err != nil {
+			return totals, fmt.Errorf("error scanning weapon stats for profile %d: %v", profile, err)
+		}
+		totals = append(totals, t)
+	}
+	return totals, nil
+}
+
+// Stats is called when a player issues the stats command in-game. With no
+// arguments the player is shown their own weapon stats, otherwise the first
+// argument is a leaderboard period and the rest an optional weapon. For
+// example "stats weekly rocket launcher".
+func Stats(fe *frontend.Frontend) {
+	if fe == nil {
+		return
+	}
+	client := (&fe.Message).ReadByte()
+	args := strings.Fields((&fe.Message).ReadString())
+	p, err := fe.FindPlayer(int(client))
+	if err != nil {
+		be.Logf(LogLevelInfo, "stats error: %v\n", err)
+		return
+	}
+
+	var rendered bytes.Buffer
+	if len(args) == 0 {
+		// stats are written at the end of a session, the current one
+		// isn't included
+		var weapons []WeaponTotal
+		if p.ProfileID != 0 {
+			weapons, err = ProfileWeaponStats(p.ProfileID, "")
+			if err != nil {
+				be.Logln(LogLevelInfo, err)
+				return
+			}
+		}
+		tmpl := template.Must(template.New("weapons").Parse(weaponTemplate))
+		if err := tmpl.Execute(&rendered, weapons); err != nil {
+			be.Logf(LogLevelInfo, "error executing weapon template: %v\n", err)
+			return
+		}
+		SayPlayer(fe, p, PRINT_CHAT, rendered.String())
+		return
+	}
+
+	period := strings.ToLower(args[0])
+	weapon := strings.ToLower(strings.Join(args[1:], " "))
+	if !slices.Contains(LeaderboardPeriods, period) {
+		txt := fmt.Sprintf("Usage: stats [%s [weapon]]\n", strings.Join(LeaderboardPeriods, "|"))
+		SayPlayer(fe, p, PRINT_HIGH, txt)
+		return
+	}
+	lb, err := LoadLeaderboard(fe.Name, period, weapon, leaderboardSize)
+	if err != nil {
+		SayPlayer(fe, p, PRINT_HIGH, err.Error()+"\n")
+		return
+	}
+	tmpl := template.Must(template.New("leaderboard").Parse(leaderboardTemplate))
+	if err := tmpl.Execute(&rendered, lb); err != nil {
+		be.Logf(LogLevelInfo, "error executing leaderboard template: %v\n", err)
+		return
+	}
+	SayPlayer(fe, p, PRINT_CHAT, rendered.String())
+}
@@ -0,0 +1,150 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/packetflinger/q2admind/frontend"
+)
+
+// addTestSession will record a finished session for a player like when they
+// disconnect.
+func addTestSession(t *testing.T, fe *frontend.Frontend, p frontend.Player, playtime int64) *frontend.Player {
+	t.Helper()
+	p.ClientID = 0
+	p.ConnectTime = time.Now().Unix() - playtime
+	fe.MaxPlayers = 1
+	fe.Players = []frontend.Player{p}
+	pl := &fe.Players[0]
+	addTestPlayer(t, fe, pl)
+	if err := LinkPlayerProfile(pl); err != nil {
+		t.Fatal(err)
+	}
+	if err := fe.WritePlayer(0); err != nil {
+		t.Fatal(err)
+	}
+	return pl
+}
+
+func TestPeriodStart(t *testing.T) {
+	now := time.Date(2026, time.October, 15, 13, 45, 0, 0, time.UTC) // thursday
+	tests := []struct {
+		period  string
+		want    time.Time
+		wantErr bool
+	}{
+		{period: LeaderboardDaily, want: time.Date(2026, time.October, 15, 0, 0, 0, 0, time.UTC)},
+		{period: LeaderboardWeekly, want: time.Date(2026, time.October, 12, 0, 0, 0, 0, time.UTC)},
+		{period: LeaderboardAllTime, want: time.Unix(0, 0)},
+		{period: "monthly", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.period, func(t *testing.T) {
+			got, err := periodStart(tc.period, now)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("periodStart(%q) error = %v, wantErr %t", tc.period, err, tc.wantErr)
+			}
+			if !tc.wantErr && got != tc.want.Unix() {
+				t.Errorf("periodStart(%q) = %v, want %v", tc.period, time.Unix(got, 0).UTC(), tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadLeaderboard(t *testing.T) {
+	useTestDatabase(t)
+	fe1 := &frontend.Frontend{Name: "test1"}
+	fe2 := &frontend.Frontend{Name: "test2"}
+
+	addTestSession(t, fe1, frontend.Player{
+		Name: "claire", IP: "192.0.2.4", Cookie: "aaaa", Frags: 10, Deaths: 5,
+		Weapons: map[int]frontend.WeaponStat{
+			frontend.ModRailgun: {Frags: 8, Deaths: 1},
+			frontend.ModRocket:  {Frags: 2, Deaths: 4},
+		},
+	}, 600)
+	addTestSession(t, fe2, frontend.Player{
+		Name: "claire", IP: "192.0.2.4", Cookie: "aaaa", Frags: 5, Deaths: 5,
+		Weapons: map[int]frontend.WeaponStat{
+			frontend.ModRSplash: {Frags: 5, Deaths: 5},
+		},
+	}, 600)
+	addTestSession(t, fe1, frontend.Player{
+		Name: "leon", IP: "198.51.100.9", Cookie: "bbbb", Frags: 12, Deaths: 2,
+		Weapons: map[int]frontend.WeaponStat{
+			frontend.ModRocket: {Frags: 12, Deaths: 2},
+		},
+	}, 900)
+	// too short
+	addTestSession(t, fe1, frontend.Player{
+		Name: "ada", IP: "203.0.113.5", Cookie: "cccc", Frags: 50, Deaths: 0,
+	}, 30)
+	// bot
+	addTestSession(t, fe1, frontend.Player{
+		Name: "bot1", IP: "loopback", Frags: 40, Deaths: 1,
+	}, 900)
+
+	tests := []struct {
+		desc   string
+		server string
+		weapon string
+		want   []string
+		frags  []int
+	}{
+		{desc: "all servers", want: []string{"claire", "leon"}, frags: []int{15, 12}},
+		{desc: "one server", server: "test1", want: []string{"leon", "claire"}, frags: []int{12, 10}},
+		{desc: "rocket", weapon: "rocket launcher", want: []string{"leon", "claire"}, frags: []int{12, 7}},
+		{desc: "rail on test2", server: "test2", weapon: "railgun", want: nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			lb, err := LoadLeaderboard(tc.server, LeaderboardDaily, tc.weapon, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(lb.Entries) != len(tc.want) {
+				t.Fatalf("LoadLeaderboard() = %+v, want %v", lb.Entries, tc.want)
+			}
+			for i, e := range lb.Entries {
+				if e.Name != tc.want[i] || e.Frags != tc.frags[i] || e.Rank != i+1 {
+					t.Errorf("entry %d = %s with %d frags at #%d, want %s with %d", i, e.Name, e.Frags, e.Rank, tc.want[i], tc.frags[i])
+				}
+			}
+		})
+	}
+
+	if _, err := LoadLeaderboard("", LeaderboardDaily, "spoon", 0); err == nil {
+		t.Error("LoadLeaderboard() with an unknown weapon didn't fail")
+	}
+}
+
+func TestProfileWeaponStats(t *testing.T) {
+	useTestDatabase(t)
+	fe := &frontend.Frontend{Name: "test1"}
+	p := addTestSession(t, fe, frontend.Player{
+		Name: "claire", IP: "192.0.2.4", Cookie: "aaaa",
+		Weapons: map[int]frontend.WeaponStat{
+			frontend.ModRocket:  {Frags: 2, Deaths: 1},
+			frontend.ModRSplash: {Frags: 3, Deaths: 0},
+			frontend.ModRailgun: {Frags: 4, Deaths: 2},
+			frontend.ModFalling: {Deaths: 1},
+		},
+	}, 60)
+	got, err := ProfileWeaponStats(p.ProfileID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []WeaponTotal{
+		{Weapon: "rocket launcher", Frags: 5, Deaths: 1},
+		{Weapon: "railgun", Frags: 4, Deaths: 2},
+		{Weapon: "fall", Frags: 0, Deaths: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ProfileWeaponStats() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ProfileWeaponStats()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
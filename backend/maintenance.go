@@ -53,6 +53,11 @@ func (s *Backend) startMaintenance() {
 			}
 			cl.ServerVars = vars
 		}
+
+		// stale vpn lookups would be asked again anyway
+		if _, err := PurgeVPNCache(""); err != nil {
+			be.Logln(LogLevelInfo, err)
+		}
 		be.maintCount++
 	}
 }
@@ -0,0 +1,262 @@
+// Map analytics. While a map is loaded we keep track of how many players
+// are on it (summed over every second so the average can be worked out),
+// the most at once, and how many joined and left before it ended. That's
+// saved when the map changes, along with how the map does in votes, so
+// owners can see which maps players actually stick around for and prune the
+// rest from their rotations.
+package backend
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/packetflinger/q2admind/frontend"
+)
+
+// MapStat is how a map has done on a frontend
+type MapStat struct {
+	Map        string
+	Plays      int
+	PlayTime   int64 // seconds
+	AvgPlayers float64
+	Peak       int
+	Joined     int
+	Left       int  // players who left before the map ended
+	Offered    int  // times it was a choice in an end of match vote
+	Votes      int  // votes it got, at the end of a match or to play it next
+	Won        int  // votes it won
+	Skipped    int  // times players voted to skip it
+	InRotation bool // in the frontend's active rotation
+}
+
+// Hours is how long the map has been played
+func (s MapStat) Hours() float64 {
+	return float64(s.PlayTime) / 3600
+}
+
+// LeftPerHour is how many players leave early for each hour the map is
+// played
+func (s MapStat) LeftPerHour() float64 {
+	if s.PlayTime == 0 {
+		return 0
+	}
+	return float64(s.Left) * 3600 / float64(s.PlayTime)
+}
+
+// humanPlayers counts the players connected to a frontend, not bots
+func humanPlayers(fe *frontend.Frontend) int {
+	count := 0
+	for _, p := range fe.Players {
+		if p.ConnectTime != 0 && !p.IsBot() {
+			count++
+		}
+	}
+	return count
+}
+
+// countMapPlayers brings the current map's player count up to date
+func countMapPlayers(fe *frontend.Frontend, now int64) {
+	mp := fe.MapPlay
+	if mp == nil {
+		return
+	}
+	mp.PlayerSeconds += int64(mp.Players) * (now - mp.Counted)
+	mp.Counted = now
+	mp.Players = humanPlayers(fe)
+	mp.Peak = max(mp.Peak, mp.Players)
+}
+
+// StartMapPlay saves how the last map was played and starts counting for
+// the current one.
+//
+// Called from ParseMap()
+func StartMapPlay(fe *frontend.Frontend) {
+	if err := EndMapPlay(fe); err != nil {
+		be.Logln(LogLevelInfo, err)
+	}
+	now := time.Now().Unix()
+	players := humanPlayers(fe)
+	fe.MapPlay = &frontend.MapPlay{
+		Map:     fe.CurrentMap,
+		Start:   now,
+		Counted: now,
+		Players: players,
+		Peak:    players,
+	}
+}
+
+// MapPlayJoined counts a player connecting to the current map.
+//
+// Called from ParseConnect()
+func MapPlayJoined(fe *frontend.Frontend, bot bool) {
+	if fe.MapPlay == nil {
+		return
+	}
+	countMapPlayers(fe, time.Now().Unix())
+	if !bot {
+		fe.MapPlay.Joined++
+	}
+}
+
+// MapPlayLeft counts a player leaving the current map, after they've been
+// removed.
+//
+// Called from ParseDisconnect()
+func MapPlayLeft(fe *frontend.Frontend, bot bool) {
+	if fe.MapPlay == nil {
+		return
+	}
+	countMapPlayers(fe, time.Now().Unix())
+	if !bot {
+		fe.MapPlay.Left++
+	}
+}
+
+// EndMapPlay saves how the current map was played. Its play time is added
+// to the map in the rotation too.
+func EndMapPlay(fe *frontend.Frontend) error {
+	mp := fe.MapPlay
+	if mp == nil {
+		return nil
+	}
+	now := time.Now().Unix()
+	countMapPlayers(fe, now)
+	fe.MapPlay = nil
+	if mp.Map == "" || now <= mp.Start {
+		return nil
+	}
+	for _, m := range fe.Maplist.GetMaps() {
+		if m.GetName() == mp.Map {
+			m.PlayTimes = append(m.PlayTimes, now-mp.Start)
+		}
+	}
+	qry := `
+		INSERT INTO map_play (server, map, start, end, player_seconds, peak, joined, left_early)
+		VALUES (?,?,?,?,?,?,?,?)`
+	_, err := db.Handle.Exec(qry, fe.Name, mp.Map, mp.Start, now, mp.PlayerSeconds, mp.Peak, mp.Joined, mp.Left)
+	if err != nil {
+		return fmt.Errorf("error saving map play for %q on %s: %v", mp.Map, fe.Name, err)
+	}
+	return nil
+}
+
+// RecordVoteResult saves how each map did in a vote. Skip votes count
+// against the map being played.
+//
+// Called from CloseVote()
+func RecordVoteResult(fe *frontend.Frontend, v *MapVote, passed bool) error {
+	type result struct {
+		mapname string
+		votes   int
+		won     bool
+	}
+	var results []result
+	counts := v.Tally()
+	switch v.Kind {
+	case VoteMap:
+		for i, m := range v.Maps {
+			results = append(results, result{m.GetName(), counts[i], m == v.Winner()})
+		}
+	case VoteSkip:
+		results = append(results, result{fe.CurrentMap, counts[1], passed})
+	case VoteNext:
+		results = append(results, result{v.Maps[0].GetName(), counts[1], passed})
+	}
+	now := time.Now().Unix()
+	for _, r := range results {
+		qry := "INSERT INTO map_vote (server, map, kind, votes, won, time) VALUES (?,?,?,?,?,?)"
+		_, err := db.Handle.Exec(qry, fe.Name, r.mapname, v.Kind, r.votes, r.won, now)
+		if err != nil {
+			return fmt.Errorf("error saving vote result for %q on %s: %v", r.mapname, fe.Name, err)
+		}
+	}
+	return nil
+}
+
+// LoadMapStats ranks the maps played on a frontend in the last number of
+// days (0 for all time) by how many players they keep, then how long
+// they've been played. Maps in the active rotation that haven't been
+// played are included at the bottom.
+func LoadMapStats(fe *frontend.Frontend, days int) ([]MapStat, error) {
+	var since int64
+	if days > 0 {
+		since = time.Now().Unix() - int64(days)*86400
+	}
+	stats := make(map[string]*MapStat)
+	stat := func(name string) *MapStat {
+		if stats[name] == nil {
+			stats[name] = &MapStat{Map: name}
+		}
+		return stats[name]
+	}
+
+	qry := `
+		SELECT map, COUNT(*), SUM(end - start), SUM(player_seconds), MAX(peak), SUM(joined), SUM(left_early)
+		FROM map_play WHERE server = ? AND start >= ? GROUP BY map`
+	rows, err := db.Handle.Query(qry, fe.Name, since)
+	if err != nil {
+		return nil, fmt.Errorf("error loading map stats: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		var s MapStat
+		var playerSeconds int64
+		if err := rows.Scan(&name, &s.Plays, &s.PlayTime, &playerSeconds, &s.Peak, &s.Joined, &s.Left); err != nil {
+			return nil, fmt.Errorf("error scanning map stats: %v", err)
+		}
+		if s.PlayTime > 0 {
+			s.AvgPlayers = float64(playerSeconds) / float64(s.PlayTime)
+		}
+		s.Map = name
+		stats[name] = &s
+	}
+
+	qry = `
+		SELECT map, kind, COUNT(*), SUM(votes), SUM(won)
+		FROM map_vote WHERE server = ? AND time >= ? GROUP BY map, kind`
+	votes, err := db.Handle.Query(qry, fe.Name, since)
+	if err != nil {
+		return nil, fmt.Errorf("error loading map votes: %v", err)
+	}
+	defer votes.Close()
+	for votes.Next() {
+		var name, kind string
+		var count, sum, won int
+		if err := votes.Scan(&name, &kind, &count, &sum, &won); err != nil {
+			return nil, fmt.Errorf("error scanning map votes: %v", err)
+		}
+		s := stat(name)
+		switch kind {
+		case VoteMap:
+			s.Offered += count
+			s.Votes += sum
+			s.Won += won
+		case VoteNext:
+			s.Votes += sum
+			s.Won += won
+		case VoteSkip:
+			s.Skipped += won
+		}
+	}
+
+	for _, m := range fe.Maplist.GetMaps() {
+		stat(m.GetName()).InRotation = true
+	}
+	var out []MapStat
+	for _, s := range stats {
+		out = append(out, *s)
+	}
+	slices.SortFunc(out, func(a, b MapStat) int {
+		if a.AvgPlayers != b.AvgPlayers {
+			return cmp.Compare(b.AvgPlayers, a.AvgPlayers)
+		}
+		if a.PlayTime != b.PlayTime {
+			return cmp.Compare(b.PlayTime, a.PlayTime)
+		}
+		return cmp.Compare(a.Map, b.Map)
+	})
+	return out, nil
+}
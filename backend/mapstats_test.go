@@ -0,0 +1,110 @@
+package backend
+
+import (
+	"io"
+	"log"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/packetflinger/q2admind/frontend"
+	"github.com/packetflinger/q2admind/maprotator"
+)
+
+// playTestMap pretends a map was loaded for a while with a number of
+// players and some leaving
+func playTestMap(t *testing.T, fe *frontend.Frontend, name string, seconds int64, players int, left int) {
+	t.Helper()
+	fe.CurrentMap = name
+	StartMapPlay(fe)
+	fe.MapPlay.Start -= seconds
+	fe.MapPlay.Counted -= seconds
+	fe.MapPlay.Players = players
+	fe.MapPlay.Peak = players
+	fe.MapPlay.Left = left
+	if err := EndMapPlay(fe); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMapPlayCounting(t *testing.T) {
+	fe := &frontend.Frontend{Name: "test1", CurrentMap: "q2dm1", Players: make([]frontend.Player, 4)}
+	StartMapPlay(fe)
+	mp := fe.MapPlay
+	now := mp.Start
+
+	fe.Players[0] = frontend.Player{ConnectTime: now, IP: "192.0.2.1"}
+	fe.Players[1] = frontend.Player{ConnectTime: now, IP: "192.0.2.2"}
+	countMapPlayers(fe, now+10) // nobody for 10 seconds
+	fe.Players[2] = frontend.Player{ConnectTime: now, IP: "bot"}
+	countMapPlayers(fe, now+20) // 2 players for 10 seconds, bots don't count
+	fe.Players[1] = frontend.Player{}
+	countMapPlayers(fe, now+50) // still 2 for another 30 seconds
+	if mp.PlayerSeconds != 20+60 || mp.Peak != 2 || mp.Players != 1 {
+		t.Errorf("after counting: %+v", mp)
+	}
+}
+
+func TestMapStats(t *testing.T) {
+	useTestDatabase(t)
+	fe := &frontend.Frontend{Name: "test1", Log: log.New(io.Discard, "", 0)}
+	fe.Maplist = maprotator.NewMapRotation("default", []string{"q2dm1", "q2dm2", "q2dm3"})
+	playTestMap(t, fe, "q2dm1", 600, 2, 1)
+	playTestMap(t, fe, "q2dm1", 1200, 5, 0)
+	playTestMap(t, fe, "q2dm2", 600, 8, 4)
+	playTestMap(t, fe, "q2dm8", 300, 1, 0)
+
+	v := &MapVote{Kind: VoteMap, Maps: fe.Maplist.GetMaps(), Ballots: map[int]int{0: 1, 1: 1, 2: 0}}
+	if err := RecordVoteResult(fe, v, true); err != nil {
+		t.Fatal(err)
+	}
+	fe.CurrentMap = "q2dm2"
+	skip := &MapVote{Kind: VoteSkip, Ballots: map[int]int{0: 1, 1: 1}, Eligible: 2}
+	if err := RecordVoteResult(fe, skip, true); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := LoadMapStats(fe, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var order []string
+	for _, s := range stats {
+		order = append(order, s.Map)
+	}
+	want := []string{"q2dm2", "q2dm1", "q2dm8", "q2dm3"}
+	if len(order) != len(want) {
+		t.Fatalf("LoadMapStats() ranked %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("LoadMapStats() ranked %v, want %v", order, want)
+		}
+	}
+
+	dm1 := stats[1]
+	if dm1.Plays != 2 || dm1.PlayTime != 1800 || math.Abs(dm1.AvgPlayers-4) > 0.01 || dm1.Peak != 5 || dm1.Left != 1 {
+		t.Errorf("q2dm1 stats = %+v", dm1)
+	}
+	if dm1.Offered != 1 || dm1.Votes != 1 || dm1.Won != 0 || !dm1.InRotation {
+		t.Errorf("q2dm1 vote stats = %+v", dm1)
+	}
+	dm2 := stats[0]
+	if dm2.Votes != 2 || dm2.Won != 1 || dm2.Skipped != 1 || dm2.LeftPerHour() != 24 {
+		t.Errorf("q2dm2 stats = %+v", dm2)
+	}
+	if stats[2].InRotation || !stats[3].InRotation || stats[3].Plays != 0 {
+		t.Errorf("rotation flags = %+v, %+v", stats[2], stats[3])
+	}
+	if times := fe.Maplist.GetMaps()[0].GetPlayTimes(); len(times) != 2 || times[1] != 1200 {
+		t.Errorf("q2dm1 play times = %v, want [600 1200]", times)
+	}
+
+	if recent, _ := LoadMapStats(fe, 1); len(recent) != 4 {
+		t.Errorf("LoadMapStats(1 day) = %d maps, want 4", len(recent))
+	}
+	db.Handle.Exec("UPDATE map_play SET start = ?", time.Now().Unix()-10*86400)
+	if recent, _ := LoadMapStats(fe, 1); len(recent) != 3 || recent[0].Plays != 0 {
+		t.Errorf("LoadMapStats(1 day) after they aged = %+v", recent)
+	}
+}
@@ -0,0 +1,199 @@
+// A match runs from the start of a map until the time or frag limit is hit
+// (or the map is changed some other way). When it ends, the scoreboard of
+// everyone still connected is saved so it can be browsed or exported later.
+package backend
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/packetflinger/q2admind/frontend"
+)
+
+// Why a match ended
+const (
+	MatchTimelimit = "timelimit"
+	MatchFraglimit = "fraglimit"
+	MatchMapChange = "map change" // ended without hitting a limit
+)
+
+// Match is a single game on a frontend and its final scores
+type Match struct {
+	ID     int64        `json:"id"`
+	Server string       `json:"server"`
+	Map    string       `json:"map"`
+	Reason string       `json:"reason"`
+	Start  int64        `json:"start"`
+	End    int64        `json:"end"`
+	Scores []MatchScore `json:"scores,omitempty"`
+}
+
+// MatchScore is one player's line on the scoreboard at the end of a match
+type MatchScore struct {
+	Player   int64   `json:"player"` // player table id for the sighting
+	Profile  int64   `json:"profile"`
+	ClientID int     `json:"client"`
+	Name     string  `json:"name"`
+	Frags    int     `json:"frags"`
+	Deaths   int     `json:"deaths"`
+	Suicides int     `json:"suicides"`
+	KDR      float64 `json:"kdr"`
+}
+
+// Duration is how long the match lasted in seconds
+func (m Match) Duration() int64 {
+	return m.End - m.Start
+}
+
+// StartMatch resets the scoreboard for a new match. Each player's current
+// counters are saved so only what happens from here on counts.
+//
+// Called from ParseMap()
+func StartMatch(fe *frontend.Frontend) {
+	if fe == nil {
+		return
+	}
+	fe.MatchStart = time.Now().Unix()
+	fe.MatchFrags = nil
+	for i := range fe.Players {
+		p := &fe.Players[i]
+		p.MatchBase = frontend.Score{Frags: p.Frags, Deaths: p.Deaths, Suicides: p.Suicides}
+	}
+}
+
+// Scoreboard is the current match score for everyone connected, highest
+// frags first.
+func Scoreboard(fe *frontend.Frontend) []MatchScore {
+	var scores []MatchScore
+	if fe == nil {
+		return scores
+	}
+	for _, p := range fe.Players {
+		if p.ConnectTime == 0 || p.IsBot() {
+			continue
+		}
+		s := MatchScore{
+			Player:   p.Database_ID,
+			Profile:  p.ProfileID,
+			ClientID: p.ClientID,
+			Name:     p.Name,
+			Frags:    p.Frags - p.MatchBase.Frags,
+			Deaths:   p.Deaths - p.MatchBase.Deaths,
+			Suicides: p.Suicides - p.MatchBase.Suicides,
+		}
+		s.KDR = ratio(s.Frags, s.Deaths)
+		scores = append(scores, s)
+	}
+	slices.SortStableFunc(scores, func(a, b MatchScore) int {
+		if a.Frags != b.Frags {
+			return b.Frags - a.Frags
+		}
+		return a.Deaths - b.Deaths
+	})
+	return scores
+}
+
+// EndMatch will save the current match and its scoreboard. Nothing is saved
+// if a match isn't in progress (the frontend connected mid-map or the limit
+// was already hit) or nobody played.
+//
+// Called from ParsePrint() and ParseMap()
+func EndMatch(fe *frontend.Frontend, reason string) (*Match, error) {
+	if fe == nil || fe.MatchStart == 0 {
+		return nil, nil
+	}
+	m := &Match{
+		Server: fe.Name,
+		Map:    fe.CurrentMap,
+		Reason: reason,
+		Start:  fe.MatchStart,
+		End:    time.Now().Unix(),
+		Scores: Scoreboard(fe),
+	}
+	fe.MatchStart = 0
+	rateMatch(fe, fe.MatchFrags)
+	fe.MatchFrags = nil
+	if len(m.Scores) == 0 {
+		return nil, nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error saving match on %s: %v", fe.Name, err)
+	}
+	defer tx.Rollback()
+	qry := "INSERT INTO match_result (server, map, reason, start, end) VALUES (?,?,?,?,?)"
+	res, err := tx.Exec(qry, m.Server, m.Map, m.Reason, m.Start, m.End)
+	if err != nil {
+		return nil, fmt.Errorf("error saving match on %s: %v", fe.Name, err)
+	}
+	m.ID, err = res.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("error getting id of match on %s: %v", fe.Name, err)
+	}
+	qry = `
+		INSERT INTO match_score (match, player, profile, client, name, frags, deaths, suicides, kdr)
+		VALUES (?,?,?,?,?,?,?,?,?)`
+	for _, s := range m.Scores {
+		_, err := tx.Exec(qry, m.ID, s.Player, s.Profile, s.ClientID, s.Name, s.Frags, s.Deaths, s.Suicides, s.KDR)
+		if err != nil {
+			return nil, fmt.Errorf("error saving score for %s in match %d: %v", s.Name, m.ID, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error saving match on %s: %v", fe.Name, err)
+	}
+	return m, nil
+}
+
+// LoadMatches will fetch the most recent matches for a frontend, without
+// their scores.
+func LoadMatches(server string, limit int) ([]Match, error) {
+	var matches []Match
+	qry := `
+		SELECT id, server, map, reason, start, end
+		FROM match_result WHERE server = ?
+		ORDER BY end DESC LIMIT ?`
+	rows, err := db.Handle.Query(qry, server, limit)
+	if err != nil {
+		return matches, fmt.Errorf("error loading matches for %s: %v", server, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var m Match
+		if err := rows.Scan(&m.ID, &m.Server, &m.Map, &m.Reason, &m.Start, &m.End); err != nil {
+			return matches, fmt.Errorf("error scanning matches for %s: %v", server, err)
+		}
+		matches = append(matches, m)
+	}
+	return matches, nil
+}
+
+// LoadMatch will fetch a single match and its scoreboard
+func LoadMatch(id int64) (*Match, error) {
+	m := &Match{}
+	qry := "SELECT id, server, map, reason, start, end FROM match_result WHERE id = ?"
+	err := db.Handle.QueryRow(qry, id).Scan(&m.ID, &m.Server, &m.Map, &m.Reason, &m.Start, &m.End)
+	if err != nil {
+		return nil, fmt.Errorf("error loading match %d: %v", id, err)
+	}
+	qry = `
+		SELECT player, profile, client, name, frags, deaths, suicides, kdr
+		FROM match_score WHERE match = ?
+		ORDER BY frags DESC, deaths ASC, id ASC`
+	rows, err := db.Handle.Query(qry, id)
+	if err != nil {
+		return nil, fmt.Errorf("error loading scores for match %d: %v", id, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var s MatchScore
+		err := rows.Scan(&s.Player, &s.Profile, &s.ClientID, &s.Name, &s.Frags, &s.Deaths, &s.Suicides, &s.KDR)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning scores for match %d: %v", id, err)
+		}
+		m.Scores = append(m.Scores, s)
+	}
+	return m, nil
+}
@@ -0,0 +1,99 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/packetflinger/q2admind/frontend"
+)
+
+func TestScoreboard(t *testing.T) {
+	now := time.Now().Unix()
+	fe := &frontend.Frontend{
+		Name: "test1",
+		Players: []frontend.Player{
+			{ClientID: 0, Name: "claire", IP: "192.0.2.4", ConnectTime: now, Frags: 20, Deaths: 4},
+			{ClientID: 1, Name: "leon", IP: "198.51.100.9", ConnectTime: now, Frags: 3, Deaths: 1, Suicides: 1},
+			{ClientID: 2}, // empty slot
+			{ClientID: 3, Name: "bot1", IP: "loopback", ConnectTime: now, Frags: 50},
+		},
+	}
+	StartMatch(fe)
+	if fe.MatchStart == 0 {
+		t.Fatal("StartMatch() didn't set the start time")
+	}
+
+	// claire: +2 frags, leon +5 frags +2 deaths, ada joins mid-match
+	fe.Players[0].Frags += 2
+	fe.Players[1].Frags += 5
+	fe.Players[1].Deaths += 2
+	fe.Players[2] = frontend.Player{ClientID: 2, Name: "ada", IP: "203.0.113.5", ConnectTime: now, Frags: 2, Deaths: 1}
+
+	got := Scoreboard(fe)
+	want := []MatchScore{
+		{ClientID: 1, Name: "leon", Frags: 5, Deaths: 2, KDR: 2.5},
+		{ClientID: 0, Name: "claire", Frags: 2, Deaths: 0, KDR: 2},
+		{ClientID: 2, Name: "ada", Frags: 2, Deaths: 1, KDR: 2},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Scoreboard() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Scoreboard()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestEndMatch(t *testing.T) {
+	useTestDatabase(t)
+	fe := &frontend.Frontend{Name: "test1", CurrentMap: "q2dm1"}
+	fe.Players = []frontend.Player{
+		{ClientID: 0, Name: "claire", IP: "192.0.2.4", ConnectTime: time.Now().Unix()},
+	}
+
+	// frontend connected mid-map, nothing to record
+	m, err := EndMatch(fe, MatchTimelimit)
+	if err != nil || m != nil {
+		t.Fatalf("EndMatch() without a match = %v, %v, want nil", m, err)
+	}
+
+	StartMatch(fe)
+	fe.MatchStart -= 600
+	fe.Players[0].Frags = 7
+	m, err = EndMatch(fe, MatchFraglimit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m == nil || m.ID == 0 {
+		t.Fatalf("EndMatch() = %+v, want a saved match", m)
+	}
+	if fe.MatchStart != 0 {
+		t.Error("EndMatch() left the match running")
+	}
+	if m, _ := EndMatch(fe, MatchMapChange); m != nil {
+		t.Error("EndMatch() recorded the same match twice")
+	}
+
+	loaded, err := LoadMatch(m.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Map != "q2dm1" || loaded.Reason != MatchFraglimit || loaded.Duration() < 600 {
+		t.Errorf("LoadMatch() = %+v", loaded)
+	}
+	if len(loaded.Scores) != 1 || loaded.Scores[0].Frags != 7 {
+		t.Errorf("LoadMatch() scores = %+v, want claire with 7 frags", loaded.Scores)
+	}
+
+	matches, err := LoadMatches("test1", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0].ID != m.ID {
+		t.Errorf("LoadMatches() = %+v, want match %d", matches, m.ID)
+	}
+	if matches, _ := LoadMatches("test2", 10); len(matches) != 0 {
+		t.Errorf("LoadMatches() for another server = %+v, want none", matches)
+	}
+}
@@ -0,0 +1,208 @@
+// Moderators can leave notes on players, like "warned about team-killing" or
+// "smurf of profile 12". A note is attached to a profile, a cookie or an IP
+// address and belongs to the frontend it was written on, only that
+// frontend's owner and the users they've delegated to can see it. Backend
+// admins see notes from every frontend and can write global ones only they
+// can see.
+package backend
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/packetflinger/q2admind/database"
+	"github.com/packetflinger/q2admind/frontend"
+)
+
+// Note is a moderator's note on a player
+type Note struct {
+	ID      int64
+	Server  string // frontend name, empty for global
+	Author  string // user's email
+	Profile int64  // who it's about, one of these
+	Cookie  string
+	IP      string
+	Text    string
+	Created int64
+}
+
+// Target describes who a note is about
+func (n Note) Target() string {
+	switch {
+	case n.Profile != 0:
+		return fmt.Sprintf("profile:%d", n.Profile)
+	case n.Cookie != "":
+		return "cookie:" + n.Cookie
+	}
+	return "ip:" + n.IP
+}
+
+// SetTarget sets who a note is about, written as profile:<id>,
+// cookie:<value> or ip:<address>
+func (n *Note) SetTarget(in string) error {
+	kind, value, _ := strings.Cut(in, ":")
+	switch kind {
+	case "ip":
+		if net.ParseIP(value) == nil {
+			return fmt.Errorf("invalid ip %q", value)
+		}
+		n.IP = value
+		return nil
+	case "cookie":
+		if value == "" {
+			return fmt.Errorf("empty cookie")
+		}
+		n.Cookie = value
+		return nil
+	case "profile":
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id <= 0 {
+			return fmt.Errorf("invalid profile %q", value)
+		}
+		n.Profile = id
+		return nil
+	}
+	return fmt.Errorf("note target %q isn't profile:<id>, cookie:<value> or ip:<address>", in)
+}
+
+// SetPlayer attaches a note to a player, using the most lasting thing we
+// know about them: their profile, otherwise their cookie, otherwise their IP
+func (n *Note) SetPlayer(p *frontend.Player) {
+	switch {
+	case p.ProfileID != 0:
+		n.Profile = p.ProfileID
+	case p.Cookie != "":
+		n.Cookie = p.Cookie
+	default:
+		n.IP = p.IP
+	}
+}
+
+// BackendAdmin checks if a user runs this backend
+func BackendAdmin(email string) bool {
+	return email != "" && slices.ContainsFunc(be.config.GetAdmins(), func(a string) bool {
+		return strings.EqualFold(a, email)
+	})
+}
+
+// noteAccess checks if a user can read and write notes on a frontend: its
+// owner, anyone they've delegated to and backend admins
+func noteAccess(fe *frontend.Frontend, email string) bool {
+	if email == "" || fe == nil {
+		return false
+	}
+	if strings.EqualFold(fe.Owner, email) || BackendAdmin(email) {
+		return true
+	}
+	for u := range fe.WebUsers {
+		if strings.EqualFold(u, email) {
+			return true
+		}
+	}
+	for u, roles := range fe.Users {
+		if !strings.EqualFold(u.GetEmail(), email) {
+			continue
+		}
+		for _, r := range roles {
+			if !r.GetDisabled() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SaveNote adds a note
+func SaveNote(n *Note) error {
+	if strings.TrimSpace(n.Text) == "" {
+		return fmt.Errorf("empty note")
+	}
+	if n.Profile == 0 && n.Cookie == "" && n.IP == "" {
+		return fmt.Errorf("note isn't about anyone")
+	}
+	if n.Server == "" && !BackendAdmin(n.Author) {
+		return fmt.Errorf("only backend admins can write global notes")
+	}
+	if n.Created == 0 {
+		n.Created = time.Now().Unix()
+	}
+	qry := `
+		INSERT INTO player_note (server, author, profile, cookie, ip, note, created)
+		VALUES (?,?,?,?,?,?,?)`
+	res, err := db.Handle.Exec(qry, n.Server, n.Author, n.Profile, n.Cookie, n.IP, n.Text, n.Created)
+	if err != nil {
+		return fmt.Errorf("error saving note: %v", err)
+	}
+	n.ID, err = res.LastInsertId()
+	return err
+}
+
+// DeleteNote removes a note, only its author or a backend admin can
+func DeleteNote(id int64, email string) error {
+	var author string
+	if err := db.Handle.QueryRow("SELECT author FROM player_note WHERE id = ?", id).Scan(&author); err != nil {
+		return fmt.Errorf("no note %d", id)
+	}
+	if !strings.EqualFold(author, email) && !BackendAdmin(email) {
+		return fmt.Errorf("note %d isn't yours", id)
+	}
+	if _, err := db.Handle.Exec("DELETE FROM player_note WHERE id = ?", id); err != nil {
+		return fmt.Errorf("error removing note %d: %v", id, err)
+	}
+	return nil
+}
+
+// VisibleNotes fetches the notes a user can see on a frontend about a
+// profile, cookie or IP, newest first
+func VisibleNotes(fe *frontend.Frontend, email string, profile int64, cookie string, ip string) ([]Note, error) {
+	var notes []Note
+	if !noteAccess(fe, email) {
+		return notes, nil
+	}
+	qry := `
+		SELECT id, server, author, profile, cookie, ip, note, created
+		FROM player_note
+		WHERE (server = ? OR ?)
+			AND ((profile != 0 AND profile = ?) OR (cookie != '' AND cookie = ?) OR (ip != '' AND ip = ?))
+		ORDER BY created DESC, id DESC`
+	rows, err := db.Handle.Query(qry, fe.Name, BackendAdmin(email), profile, cookie, ip)
+	if err != nil {
+		return notes, fmt.Errorf("error loading notes: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var n Note
+		err := rows.Scan(&n.ID, &n.Server, &n.Author, &n.Profile, &n.Cookie, &n.IP, &n.Text, &n.Created)
+		if err != nil {
+			return notes, fmt.Errorf("error scanning notes: %v", err)
+		}
+		notes = append(notes, n)
+	}
+	return notes, nil
+}
+
+// PlayerNotes fetches the notes a user can see about a player
+func PlayerNotes(fe *frontend.Frontend, email string, p *frontend.Player) ([]Note, error) {
+	if p == nil {
+		return nil, nil
+	}
+	return VisibleNotes(fe, email, p.ProfileID, p.Cookie, p.IP)
+}
+
+// annotateSearch fills in how many notes a user can see on each search
+// result
+func annotateSearch(fe *frontend.Frontend, email string, results []database.SearchResult) {
+	for i := range results {
+		r := &results[i]
+		notes, err := VisibleNotes(fe, email, r.Profile, r.Cookie, r.IP)
+		if err != nil {
+			be.Logln(LogLevelInfo, err)
+			return
+		}
+		r.Notes = len(notes)
+	}
+}
@@ -0,0 +1,98 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/packetflinger/q2admind/frontend"
+
+	pb "github.com/packetflinger/q2admind/proto"
+)
+
+func TestNoteSetTarget(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"profile:12", "profile:12"},
+		{"cookie:abcd", "cookie:abcd"},
+		{"ip:192.0.2.8", "ip:192.0.2.8"},
+	}
+	for _, tc := range tests {
+		var n Note
+		if err := n.SetTarget(tc.in); err != nil || n.Target() != tc.want {
+			t.Errorf("SetTarget(%q) = %v, target %q", tc.in, err, n.Target())
+		}
+	}
+	for _, bad := range []string{"profile:0", "cookie:", "ip:nope", "abcd"} {
+		var n Note
+		if err := n.SetTarget(bad); err == nil {
+			t.Errorf("SetTarget(%q) = nil, want error", bad)
+		}
+	}
+}
+
+func TestVisibleNotes(t *testing.T) {
+	useTestDatabase(t)
+	admins := be.config.Admins
+	be.config.Admins = []string{"root@example.com"}
+	t.Cleanup(func() { be.config.Admins = admins })
+
+	fe := &frontend.Frontend{
+		Name:     "test1",
+		Owner:    "owner@example.com",
+		WebUsers: map[string]bool{"reader@example.com": false},
+		Users:    map[*pb.User][]*pb.Role{{Email: "gone@example.com"}: {{Disabled: true}}},
+	}
+	other := &frontend.Frontend{Name: "test2", Owner: "other@example.com"}
+	p := &frontend.Player{Name: "leon", Cookie: "aaaa", IP: "192.0.2.8", ProfileID: 5}
+
+	for _, n := range []*Note{
+		{Server: "test1", Author: "owner@example.com", Profile: 5, Text: "warned about team-killing"},
+		{Server: "test1", Author: "reader@example.com", IP: "192.0.2.8", Text: "shared address"},
+		{Server: "test2", Author: "other@example.com", Cookie: "aaaa", Text: "other server"},
+		{Author: "root@example.com", Cookie: "aaaa", Text: "known smurf"},
+		{Server: "test1", Author: "owner@example.com", Cookie: "bbbb", Text: "someone else"},
+	} {
+		if err := SaveNote(n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := SaveNote(&Note{Author: "owner@example.com", Cookie: "aaaa", Text: "global"}); err == nil {
+		t.Error("SaveNote() global by a frontend owner = nil, want error")
+	}
+	if err := SaveNote(&Note{Server: "test1", Author: "owner@example.com", Text: "nobody"}); err == nil {
+		t.Error("SaveNote() about nobody = nil, want error")
+	}
+
+	tests := []struct {
+		desc  string
+		fe    *frontend.Frontend
+		email string
+		want  int
+	}{
+		{"owner", fe, "owner@example.com", 2},
+		{"delegate", fe, "Reader@example.com", 2},
+		{"disabled role", fe, "gone@example.com", 0},
+		{"stranger", fe, "nobody@example.com", 0},
+		{"other owner", other, "other@example.com", 1},
+		{"backend admin", fe, "root@example.com", 4},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			notes, err := PlayerNotes(tc.fe, tc.email, p)
+			if err != nil || len(notes) != tc.want {
+				t.Errorf("PlayerNotes() = %d notes, %v, want %d", len(notes), err, tc.want)
+			}
+		})
+	}
+
+	if err := DeleteNote(1, "reader@example.com"); err == nil {
+		t.Error("DeleteNote() someone else's = nil, want error")
+	}
+	if err := DeleteNote(1, "root@example.com"); err != nil {
+		t.Errorf("DeleteNote() by a backend admin = %v", err)
+	}
+	if got, _ := PlayerNotes(fe, "owner@example.com", p); len(got) != 1 {
+		t.Errorf("PlayerNotes() after delete = %+v", got)
+	}
+}
@@ -0,0 +1,49 @@
+package backend
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/packetflinger/q2admind/frontend"
+)
+
+// The kinds of offenses recorded against a player
+const (
+	OffenseBan  = "ban"
+	OffenseMute = "mute"
+)
+
+// Offense is a single sanction applied to a player
+type Offense struct {
+	ID      int64
+	Player  int64  // player table id for the sighting
+	Profile int64  // player's profile at the time
+	Server  string // frontend name
+	Kind    string // ban, mute, etc
+	Rule    string // uuid of the rule responsible, if any
+	Detail  string
+	Time    int64
+	Expires int64 // 0 is as long as the rule exists
+}
+
+// RecordOffense will save a sanction applied to a player so it can be
+// referenced later (evasion detection, offense history, etc). Offenses from
+// rules last as long as the rule does, others (like a mute from the ssh
+// console) should include when they expire.
+func RecordOffense(p *frontend.Player, kind string, rule string, detail string, expires int64) error {
+	if p == nil {
+		return fmt.Errorf("error recording offense: null player")
+	}
+	server := ""
+	if p.Frontend != nil {
+		server = p.Frontend.Name
+	}
+	qry := `
+		INSERT INTO offense (player, profile, server, kind, rule, detail, time, expires)
+		VALUES (?,?,?,?,?,?,?,?)`
+	_, err := db.Handle.Exec(qry, p.Database_ID, p.ProfileID, server, kind, rule, detail, time.Now().Unix(), expires)
+	if err != nil {
+		return fmt.Errorf("error recording %s offense for %s[%d]: %v", kind, p.Name, p.Database_ID, err)
+	}
+	return nil
+}
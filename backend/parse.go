@@ -13,6 +13,8 @@ import (
 	"github.com/packetflinger/libq2/message"
 	"github.com/packetflinger/q2admind/crypto"
 	"github.com/packetflinger/q2admind/frontend"
+
+	pb "github.com/packetflinger/q2admind/proto"
 )
 
 type greeting struct {
@@ -152,6 +154,7 @@ func ParseFrag(fe *frontend.Frontend) {
 	}
 	victim.Deaths++
 	fe.Log.Println("FRAG", aName, ">", victim.Name)
+	fe.AddFrag(aName + " > " + victim.Name)
 	fe.SSHPrintln("FRAG " + aName + " > " + victim.Name)
 }
 
@@ -190,6 +193,7 @@ func ParsePrint(fe *frontend.Frontend) {
 	switch level {
 	case PRINT_CHAT:
 		fe.Log.Println("CHAT", stripped)
+		fe.AddChat(stripped)
 		fe.SSHPrintln(Font([]int{ColorGreen, WeightBold}, stripped))
 	case PRINT_HIGH:
 		fe.Log.Println("PRINT", stripped)
@@ -198,16 +202,14 @@ func ParsePrint(fe *frontend.Frontend) {
 		// change the map
 		fmt.Println(stripped)
 		if stripped == "Timelimit hit." || stripped == "Fraglimit hit." {
-			fmt.Println("CHANGE MAP SOON")
-			if fe.Maplist != nil {
-				fmt.Println("MAPLIST ISNT NIL")
-				go func() {
-					next := fe.Maplist.Next()
-					fmt.Printf("changing map to %q in a few seconds\n", next.GetName())
-					time.Sleep(time.Second * 5)
-					ConsoleCommand(fe, fmt.Sprintf("gamemap %s", next.GetName()))
-				}()
+			reason := MatchTimelimit
+			if stripped == "Fraglimit hit." {
+				reason = MatchFraglimit
 			}
+			recordMatch(fe, reason)
+
+			fmt.Println("CHANGE MAP SOON")
+			NextMap(fe)
 		}
 	case PRINT_MEDIUM:
 		ParseObituary(fe, stripped)
@@ -216,14 +218,22 @@ func ParsePrint(fe *frontend.Frontend) {
 		//cl.SSHPrintln(msgColor + stripped + AnsiReset)
 	}
 
-	// re-stifle if needed
+	// check for flooding, bridge to other servers and re-stifle if needed
 	if level == PRINT_CHAT {
 		players, err := fe.GetPlayerFromPrint(stripped)
 		if err != nil {
 			fe.Log.Println(err)
 			return
 		}
+		if len(players) == 1 {
+			said := strings.TrimPrefix(stripped, players[0].Name+": ")
+			if !TextFlood(players[0], said) {
+				BridgeChat(fe, players[0], said)
+				NotifyDiscord(fe, DiscordChat, players[0].Name, said)
+			}
+		}
 		for _, p := range players {
+			EndFloodStifle(p, time.Now().Unix())
 			if p.Stifled {
 				MutePlayer(fe, p, p.StifleLength)
 			}
@@ -244,6 +254,7 @@ func ParseConnect(fe *frontend.Frontend) {
 	if p == nil {
 		return
 	}
+	MapPlayJoined(fe, p.IsBot())
 
 	// DNS resolution can take time (up to seconds) to get a response, so
 	// logging a connect should be done concurrently to prevent blocking. Rules
@@ -261,6 +272,27 @@ func ParseConnect(fe *frontend.Frontend) {
 		msg := fmt.Sprintf("%-20s[%d] %-20q %s", "CONNECT:", p.ClientID, p.Name, p.IP)
 		fe.Log.Printf("%s", msg)
 		fe.SSHPrintln(msg)
+		NotifyDiscord(fe, DiscordConnect, p.Name, "")
+
+		// rules can target a profile, so it needs to be known first
+		err = LinkPlayerProfile(p)
+		if err != nil {
+			be.Logln(LogLevelInfo, err)
+		}
+		err = LoadPlayerAccount(p)
+		if err != nil {
+			be.Logln(LogLevelInfo, err)
+		}
+		p.Rating, err = LoadRating(p.ProfileID, fe.Pool())
+		if err != nil {
+			be.Logln(LogLevelInfo, err)
+		}
+		CheckReservedName(p)
+
+		// rules can target VPN users too
+		if CheckVPN(p) {
+			return
+		}
 
 		// add a slight delay when processing rules
 		time.Sleep(1 * time.Second)
@@ -270,6 +302,11 @@ func ParseConnect(fe *frontend.Frontend) {
 			p.Rules = rules
 			ApplyMatchedRules(p, rules)
 		}
+
+		// no point looking for evasion if they're already banned
+		if !match || rules[0].GetType() != pb.RuleType_BAN {
+			CheckEvasion(p)
+		}
 	}()
 }
 
@@ -295,7 +332,9 @@ func ParseDisconnect(fe *frontend.Frontend) {
 	msg := fmt.Sprintf("%-20s[%d] %-20q %s", "DISCONNECT:", pl.ClientID, pl.Name, pl.IP)
 	fe.Log.Printf("%s", msg)
 	fe.SSHPrintln(msg)
+	bot := pl.IsBot()
 	fe.RemovePlayer(clientnum)
+	MapPlayLeft(fe, bot)
 }
 
 // Frontend told us what map is currently running. Typically happens when the
@@ -305,11 +344,32 @@ func ParseMap(fe *frontend.Frontend) {
 		return
 	}
 	mapname := (&fe.Message).ReadString()
+	recordMatch(fe, MatchMapChange)
+	CancelVote(fe)
 	fe.PreviousMap = fe.CurrentMap
 	fe.CurrentMap = mapname
+	StartMapPlay(fe)
 	msg := fmt.Sprintf("%-20s %q (was %q)", "MAP_CHANGE:", fe.CurrentMap, fe.PreviousMap)
 	fe.Log.Println(msg)
 	fe.SSHPrintln(msg)
+	NotifyDiscord(fe, DiscordMap, "", mapname)
+	StartMatch(fe)
+}
+
+// recordMatch will end the current match and log the result
+func recordMatch(fe *frontend.Frontend, reason string) {
+	m, err := EndMatch(fe, reason)
+	if err != nil {
+		be.Logln(LogLevelInfo, err)
+		return
+	}
+	if m == nil {
+		return
+	}
+	msg := fmt.Sprintf("%-20s %q ended by %s after %ds, %q won with %d frags", "MATCH_END:",
+		m.Map, m.Reason, m.Duration(), m.Scores[0].Name, m.Scores[0].Frags)
+	fe.Log.Println(msg)
+	fe.SSHPrintln(msg)
 }
 
 // An obit for every frag is sent from a client.
@@ -339,6 +399,7 @@ func ParseObituary(fe *frontend.Frontend, obit string) {
 		fe.Players[cid].Suicides++
 		fe.Players[cid].Frags--
 		fe.Players[cid].KDR = fe.CalculateKDR(cid)
+		fe.AddFrag("World/Self > " + death.Victim.Name)
 	} else {
 		logObit = fmt.Sprintf("DEATH: %s[%d] -> %s[%d] (%s)",
 			death.Murderer.Name,
@@ -353,7 +414,10 @@ func ParseObituary(fe *frontend.Frontend, obit string) {
 		fe.Players[cidM].Frags++
 		fe.Players[cidV].KDR = fe.CalculateKDR(cidV)
 		fe.Players[cidM].KDR = fe.CalculateKDR(cidM)
+		recordDuel(fe, death.Murderer, death.Victim)
+		fe.AddFrag(death.Murderer.Name + " > " + death.Victim.Name)
 	}
+	death.Tally()
 	fe.Log.Printf("%s", logObit)
 	fe.SSHPrintln(logObit)
 }
@@ -411,7 +475,21 @@ func ParsePlayer(fe *frontend.Frontend) *frontend.Player {
 		Frontend:     fe,
 		Version:      clientVersion,
 		Hostname:     info["ip"], // PTR resolved later
+		FloodInfo: &pb.FloodInfo{
+			FirstPrintTime:    time.Now().UnixMilli(),
+			FirstUserinfoTime: time.Now().UnixMilli(),
+		},
+	}
 
+	// local database lookup, quick enough to do before the player is added
+	if be.geo != nil {
+		geo, err := be.geo.Lookup(newplayer.IP)
+		if err != nil {
+			be.Logf(LogLevelDebug, "%v\n", err)
+		}
+		newplayer.Country = geo.Country
+		newplayer.ASN = geo.ASN
+		newplayer.ASNOrg = geo.ASNOrg
 	}
 
 	fe.Log.Printf("PLAYER %d|%s|%s\n", clientnum, newplayer.UserInfoHash, userinfo)
@@ -438,6 +516,24 @@ func ParseCommand(fe *frontend.Frontend) {
 
 	case PCMDInvite:
 		Invite(fe)
+
+	case PCMDWhois:
+		Whois(fe)
+
+	case PCMDReport:
+		Report(fe)
+
+	case PCMDStats:
+		Stats(fe)
+
+	case PCMDLink:
+		Link(fe)
+
+	case PCMDAdmin:
+		Admin(fe)
+
+	case PCMDVote:
+		Vote(fe)
 	}
 }
 
@@ -465,14 +561,53 @@ func ParsePlayerUpdate(fe *frontend.Frontend) {
 		return
 	}
 
+	hadCookie := player.Cookie != ""
 	info := frontend.UserinfoMap(userinfo)
+	renamed := player.Name != info["name"]
+	if renamed || player.UserinfoMap["skin"] != info["skin"] {
+		UserinfoFlood(player)
+	}
 	player.UserinfoMap = info
+	if renamed {
+		err = AddProfileLink(player.ProfileID, ProfileLinkName, info["name"], player.ProfileScore)
+		if err != nil {
+			be.Logln(LogLevelInfo, err)
+		}
+	}
 	player.Name = info["name"]
 	player.FOV, _ = strconv.Atoi(info["fov"])
 	player.Cookie = info["cl_cookie"]
 	player.UserInfoHash = hash
+	if renamed {
+		CheckReservedName(player)
+	}
 
 	if player.Cookie == "" {
 		SetupPlayerCookie(fe, player)
+		return
+	}
+
+	// A player connecting without a cookie was checked against the rules
+	// using only what we knew at the time. Now that the freshly set cookie
+	// has arrived, record it and check again so cookie rules apply.
+	if !hadCookie {
+		err = fe.UpdatePlayerCookie(player)
+		if err != nil {
+			be.Logln(LogLevelInfo, err)
+		}
+		err = LinkPlayerCookie(player)
+		if err != nil {
+			be.Logln(LogLevelInfo, err)
+		}
+		err = LoadPlayerAccount(player)
+		if err != nil {
+			be.Logln(LogLevelInfo, err)
+		}
+		CheckReservedName(player) // they might be who a reserved name is for
+		match, rules := CheckRules(player, append(fe.Rules, be.rules...))
+		if match {
+			player.Rules = rules
+			ApplyMatchedRules(player, rules)
+		}
 	}
 }
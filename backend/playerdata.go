@@ -0,0 +1,366 @@
+// Tools for handling personal data requests. Everything we have on a player
+// can be found from any one thing that identifies them and exported as JSON,
+// anonymized (personal fields dropped, records kept) or erased (personal
+// records removed). Stats are anonymized either way, other players' matches
+// and ratings were played against them.
+//
+// An identity is written as profile:<id>, cookie:<value>, ip:<address> or
+// name:<name>. It's matched against player records, then everything from
+// the profiles of those records is included too since a profile is the
+// person. Asking about an IP address or a name can cover everyone who's
+// used it, so those only work for exports. Anonymizing and erasing need a
+// profile or cookie.
+//
+// Ban rules live with each frontend's config and aren't touched, they're
+// needed to keep enforcing bans. VPN overrides are kept for the same reason.
+// Chat logs aren't tied to players so they aren't either. Every request is
+// recorded in the admin log with a hash of the identity instead of the
+// identity itself.
+package backend
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Player data actions, as recorded in the admin log
+const (
+	DataExport    = "export"
+	DataAnonymize = "anonymize"
+	DataErase     = "erase"
+)
+
+const anonymousName = "anonymous"
+
+// DataSubject is everything that identifies the player a data request is
+// about
+type DataSubject struct {
+	Identity string
+	Players  []int64 // player records
+	Profiles []int64
+	Cookies  []string
+	IPs      []string
+}
+
+// dataTable is where personal data is kept. The where clause uses
+// {players}, {profiles}, {cookies} and {ips} for the subject's identifiers.
+type dataTable struct {
+	table string
+	where string
+}
+
+// dataTables are searched for exports, in this order. Every table is either
+// here or in dataTablesSkipped.
+var dataTables = []dataTable{
+	{"player", "id IN {players}"},
+	{"player_stat", "player IN {players}"},
+	{"weapon_stat", "player IN {players}"},
+	{"profile", "id IN {profiles}"},
+	{"profile_link", "profile IN {profiles}"},
+	{"rating", "profile IN {profiles}"},
+	{"offense", "player IN {players} OR profile IN {profiles}"},
+	{"match_score", "player IN {players} OR profile IN {profiles}"},
+	{"report", "reporter IN {players} OR target IN {players} OR reporter_profile IN {profiles} OR target_profile IN {profiles}"},
+	{"account_link", "cookie IN {cookies} OR profile IN {profiles}"},
+	{"reserved_name", "cookie IN {cookies} OR profile IN {profiles}"},
+	{"player_note", "profile IN {profiles} OR cookie IN {cookies} OR ip IN {ips}"},
+	{"vpn_cache", "ip IN {ips}"},
+	{"admin_log", "profile IN {profiles}"},
+}
+
+// dataTablesSkipped don't hold anything about players
+var dataTablesSkipped = []string{
+	"link_code", "match_result", "map_rotation", "map_play", "map_vote",
+	"scheduled_job", "scheduled_run",
+}
+
+// anonymizeStatements drop personal fields and keep stats. The stat tables
+// (player_stat, weapon_stat, rating) aren't personal on their own.
+var anonymizeStatements = []dataTable{
+	{"player", "UPDATE player SET name = '" + anonymousName + "', ip = '', hostname = '', cookie = '', userinfo = '', version = '', country = '', asn = 0 WHERE id IN {players}"},
+	{"profile", "UPDATE profile SET name = '" + anonymousName + "' WHERE id IN {profiles}"},
+	{"profile_link", "DELETE FROM profile_link WHERE profile IN {profiles}"},
+	{"offense", "UPDATE offense SET detail = '' WHERE player IN {players} OR profile IN {profiles}"},
+	{"match_score", "UPDATE match_score SET name = '" + anonymousName + "' WHERE player IN {players} OR profile IN {profiles}"},
+	{"report", "UPDATE report SET reporter_name = '', reporter_ip = '' WHERE reporter IN {players} OR reporter_profile IN {profiles}"},
+	{"report", "UPDATE report SET target_name = '', snapshot = '' WHERE target IN {players} OR target_profile IN {profiles}"},
+	{"account_link", "DELETE FROM account_link WHERE cookie IN {cookies} OR profile IN {profiles}"},
+	{"reserved_name", "DELETE FROM reserved_name WHERE cookie IN {cookies} OR profile IN {profiles}"},
+	{"player_note", "DELETE FROM player_note WHERE profile IN {profiles} OR cookie IN {cookies} OR ip IN {ips}"},
+	{"vpn_cache", "DELETE FROM vpn_cache WHERE ip IN {ips} AND override = ''"},
+	{"admin_log", "UPDATE admin_log SET target = '" + anonymousName + "', detail = '' WHERE profile IN {profiles}"},
+}
+
+// eraseStatements remove everything personal. Player records, profiles and
+// match scores are anonymized instead so the stats hanging off them
+// (player_stat, weapon_stat, rating) stay, as does what the admin log says
+// was done.
+var eraseStatements = []dataTable{
+	{"player", "UPDATE player SET name = '" + anonymousName + "', ip = '', hostname = '', cookie = '', userinfo = '', version = '', country = '', asn = 0 WHERE id IN {players}"},
+	{"profile", "UPDATE profile SET name = '" + anonymousName + "' WHERE id IN {profiles}"},
+	{"profile_link", "DELETE FROM profile_link WHERE profile IN {profiles}"},
+	{"offense", "DELETE FROM offense WHERE player IN {players} OR profile IN {profiles}"},
+	{"match_score", "UPDATE match_score SET name = '" + anonymousName + "' WHERE player IN {players} OR profile IN {profiles}"},
+	{"report", "DELETE FROM report WHERE reporter IN {players} OR target IN {players} OR reporter_profile IN {profiles} OR target_profile IN {profiles}"},
+	{"account_link", "DELETE FROM account_link WHERE cookie IN {cookies} OR profile IN {profiles}"},
+	{"reserved_name", "DELETE FROM reserved_name WHERE cookie IN {cookies} OR profile IN {profiles}"},
+	{"player_note", "DELETE FROM player_note WHERE profile IN {profiles} OR cookie IN {cookies} OR ip IN {ips}"},
+	{"vpn_cache", "DELETE FROM vpn_cache WHERE ip IN {ips} AND override = ''"},
+	{"admin_log", "UPDATE admin_log SET target = '" + anonymousName + "', detail = '' WHERE profile IN {profiles}"},
+}
+
+// expand fills in a statement's placeholders for the subject, returning the
+// query and its args
+func (s *DataSubject) expand(stmt string) (string, []any) {
+	var args []any
+	list := func(n int) string {
+		return "(" + strings.TrimSuffix(strings.Repeat("?,", n), ",") + ")"
+	}
+	var out strings.Builder
+	for {
+		start := strings.Index(stmt, "{")
+		if start < 0 {
+			out.WriteString(stmt)
+			break
+		}
+		end := strings.Index(stmt[start:], "}") + start
+		out.WriteString(stmt[:start])
+		switch stmt[start+1 : end] {
+		case "players":
+			out.WriteString(list(len(s.Players)))
+			for _, v := range s.Players {
+				args = append(args, v)
+			}
+		case "profiles":
+			out.WriteString(list(len(s.Profiles)))
+			for _, v := range s.Profiles {
+				args = append(args, v)
+			}
+		case "cookies":
+			out.WriteString(list(len(s.Cookies)))
+			for _, v := range s.Cookies {
+				args = append(args, v)
+			}
+		case "ips":
+			out.WriteString(list(len(s.IPs)))
+			for _, v := range s.IPs {
+				args = append(args, v)
+			}
+		}
+		stmt = stmt[end+1:]
+	}
+	return out.String(), args
+}
+
+// FindDataSubject finds every player record and profile tied to an identity
+func FindDataSubject(identity string) (*DataSubject, error) {
+	kind, value, _ := strings.Cut(strings.TrimSpace(identity), ":")
+	if value == "" {
+		return nil, fmt.Errorf("identity %q isn't profile:<id>, cookie:<value>, ip:<address> or name:<name>", identity)
+	}
+	s := &DataSubject{Identity: identity}
+	var column string
+	switch kind {
+	case "profile":
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid profile %q", value)
+		}
+		s.Profiles = append(s.Profiles, id)
+	case "cookie", "ip", "name":
+		column = kind
+	default:
+		return nil, fmt.Errorf("identity %q isn't profile:<id>, cookie:<value>, ip:<address> or name:<name>", identity)
+	}
+
+	if column != "" {
+		rows, err := db.Handle.Query("SELECT profile FROM player WHERE "+column+" = ?", value)
+		if err != nil {
+			return nil, fmt.Errorf("error finding players: %v", err)
+		}
+		for rows.Next() {
+			var profile int64
+			if err := rows.Scan(&profile); err == nil && profile != 0 && !slices.Contains(s.Profiles, profile) {
+				s.Profiles = append(s.Profiles, profile)
+			}
+		}
+		rows.Close()
+		switch kind {
+		case "cookie":
+			s.Cookies = append(s.Cookies, value)
+		case "ip":
+			s.IPs = append(s.IPs, value)
+		}
+	}
+
+	// profiles merged into theirs are theirs too
+	if len(s.Profiles) > 0 {
+		qry, args := s.expand("SELECT id FROM profile WHERE merged_into IN {profiles}")
+		rows, err := db.Handle.Query(qry, args...)
+		if err != nil {
+			return nil, fmt.Errorf("error finding merged profiles: %v", err)
+		}
+		for rows.Next() {
+			var id int64
+			if err := rows.Scan(&id); err == nil && !slices.Contains(s.Profiles, id) {
+				s.Profiles = append(s.Profiles, id)
+			}
+		}
+		rows.Close()
+	}
+
+	qry, args := s.expand("SELECT id, cookie, ip FROM player WHERE profile IN {profiles}")
+	if column != "" {
+		qry += " OR " + column + " = ?"
+		args = append(args, value)
+	}
+	rows, err := db.Handle.Query(qry, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error finding players: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		var cookie, ip sql.NullString
+		if err := rows.Scan(&id, &cookie, &ip); err != nil {
+			return nil, fmt.Errorf("error scanning players: %v", err)
+		}
+		s.Players = append(s.Players, id)
+		if cookie.String != "" && !slices.Contains(s.Cookies, cookie.String) {
+			s.Cookies = append(s.Cookies, cookie.String)
+		}
+		if ip.String != "" && !slices.Contains(s.IPs, ip.String) {
+			s.IPs = append(s.IPs, ip.String)
+		}
+	}
+	return s, nil
+}
+
+// dataRows reads any query's results as column/value maps
+func dataRows(qry string, args ...any) ([]map[string]any, error) {
+	var out []map[string]any
+	rows, err := db.Handle.Query(qry, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		vals := make([]any, len(cols))
+		ptrs := make([]any, len(cols))
+		for i := range vals {
+			ptrs[i] = &vals[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]any)
+		for i, c := range cols {
+			if b, ok := vals[i].([]byte); ok {
+				vals[i] = string(b)
+			}
+			row[c] = vals[i]
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+// ExportPlayerData collects every record tied to an identity as JSON, by
+// table
+func ExportPlayerData(identity string, actor string) ([]byte, error) {
+	s, err := FindDataSubject(identity)
+	if err != nil {
+		return nil, err
+	}
+	export := struct {
+		Identity string                      `json:"identity"`
+		Exported int64                       `json:"exported"`
+		Tables   map[string][]map[string]any `json:"tables"`
+	}{identity, time.Now().Unix(), make(map[string][]map[string]any)}
+	count := 0
+	for _, t := range dataTables {
+		qry, args := s.expand(fmt.Sprintf("SELECT * FROM %s WHERE %s", t.table, t.where))
+		rows, err := dataRows(qry, args...)
+		if err != nil {
+			return nil, fmt.Errorf("error exporting %s: %v", t.table, err)
+		}
+		if len(rows) > 0 {
+			export.Tables[t.table] = rows
+			count += len(rows)
+		}
+	}
+	out, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding export: %v", err)
+	}
+	logDataRequest(DataExport, identity, actor, fmt.Sprintf("%d records", count))
+	return out, nil
+}
+
+// ErasePlayerData removes everything personal tied to an identity, or only
+// the personal fields when anonymizing. Only profiles and cookies can be
+// erased, addresses and names are shared with other people. Returns the rows
+// changed by table.
+func ErasePlayerData(identity string, anonymize bool, actor string) (map[string]int64, error) {
+	action, statements := DataErase, eraseStatements
+	if anonymize {
+		action, statements = DataAnonymize, anonymizeStatements
+	}
+	if kind, _, _ := strings.Cut(strings.TrimSpace(identity), ":"); kind != "profile" && kind != "cookie" {
+		return nil, fmt.Errorf("%s needs profile:<id> or cookie:<value>, addresses and names can belong to other people", action)
+	}
+	s, err := FindDataSubject(identity)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting %s: %v", action, err)
+	}
+	defer tx.Rollback()
+	changed := make(map[string]int64)
+	var total int64
+	for _, st := range statements {
+		qry, args := s.expand(st.where)
+		res, err := tx.Exec(qry, args...)
+		if err != nil {
+			return nil, fmt.Errorf("error during %s of %s: %v", action, st.table, err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			changed[st.table] += n
+			total += n
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error finishing %s: %v", action, err)
+	}
+	logDataRequest(action, identity, actor, fmt.Sprintf("%d rows", total))
+	return changed, nil
+}
+
+// dataIdentityHash is what's logged instead of an identity, so the log
+// doesn't keep what was asked to be removed. Hash the identity to look it up.
+func dataIdentityHash(identity string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(identity)))
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
+
+// logDataRequest records a data request in the admin log
+func logDataRequest(action string, identity string, actor string, detail string) {
+	a := &AdminAction{Actor: actor, Action: action, Target: dataIdentityHash(identity), Detail: detail}
+	if err := LogAdminAction(a); err != nil {
+		be.Logln(LogLevelInfo, err)
+	}
+	be.Logf(LogLevelInfo, "%s %s of %s: %s\n", actor, action, a.Target, detail)
+}
@@ -0,0 +1,183 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/packetflinger/q2admind/frontend"
+)
+
+// addDataTestPlayers sets up two players who share nothing and one who has
+// played under two profiles since merged
+func addDataTestPlayers(t *testing.T) (*frontend.Player, *frontend.Player) {
+	t.Helper()
+	useTestDatabase(t)
+	fe := &frontend.Frontend{Name: "test1"}
+	leon := &frontend.Player{Name: "leon", Cookie: "aaaa", IP: "192.0.2.8", Hostname: "leon.example.com"}
+	old := &frontend.Player{Name: "leon_", Cookie: "cccc", IP: "192.0.2.9"}
+	claire := &frontend.Player{Name: "claire", Cookie: "bbbb", IP: "198.51.100.4"}
+	for _, p := range []*frontend.Player{leon, old, claire} {
+		addTestPlayer(t, fe, p)
+		if err := LinkPlayerProfile(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := MergeProfiles(old.ProfileID, leon.ProfileID); err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []*Note{
+		{Server: "test1", Author: "owner@example.com", Profile: leon.ProfileID, Text: "warned about spawn camping"},
+		{Server: "test1", Author: "owner@example.com", Cookie: "bbbb", Text: "fine"},
+	} {
+		if err := SaveNote(n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := RecordOffense(leon, OffenseMute, "", "spam", 0); err != nil {
+		t.Fatal(err)
+	}
+	return leon, claire
+}
+
+func TestFindDataSubject(t *testing.T) {
+	leon, _ := addDataTestPlayers(t)
+	for _, id := range []string{"cookie:aaaa", "ip:192.0.2.9", "name:leon"} {
+		s, err := FindDataSubject(id)
+		if err != nil {
+			t.Fatalf("FindDataSubject(%q) = %v", id, err)
+		}
+		if len(s.Players) != 2 || len(s.Profiles) != 2 || s.Profiles[0] != leon.ProfileID || len(s.Cookies) != 2 {
+			t.Errorf("FindDataSubject(%q) = %+v", id, s)
+		}
+	}
+	for _, bad := range []string{"", "leon", "profile:x", "email:leon@example.com"} {
+		if _, err := FindDataSubject(bad); err == nil {
+			t.Errorf("FindDataSubject(%q) = nil, want error", bad)
+		}
+	}
+}
+
+func TestExportPlayerData(t *testing.T) {
+	addDataTestPlayers(t)
+	out, err := ExportPlayerData("cookie:aaaa", "rpc:root@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var export struct {
+		Tables map[string][]map[string]any
+	}
+	if err := json.Unmarshal(out, &export); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{"player": 2, "profile": 2, "offense": 1, "player_note": 1}
+	for table, n := range want {
+		if len(export.Tables[table]) != n {
+			t.Errorf("export has %d %s records, want %d", len(export.Tables[table]), table, n)
+		}
+	}
+	for _, p := range export.Tables["player"] {
+		if p["name"] == "claire" {
+			t.Errorf("export includes someone else: %v", p)
+		}
+	}
+
+	log, err := LoadAdminLog("", 10)
+	if err != nil || len(log) != 1 || log[0].Action != DataExport || log[0].Target != dataIdentityHash("cookie:aaaa") {
+		t.Errorf("LoadAdminLog() = %+v, %v", log, err)
+	}
+}
+
+func TestErasePlayerData(t *testing.T) {
+	t.Run("anonymize", func(t *testing.T) {
+		leon, claire := addDataTestPlayers(t)
+		rows, err := ErasePlayerData(fmt.Sprintf("profile:%d", leon.ProfileID), true, "rpc:root@example.com")
+		if err != nil || rows["player"] != 2 || rows["player_note"] != 1 {
+			t.Fatalf("ErasePlayerData() = %v, %v", rows, err)
+		}
+		var name, cookie, ip string
+		db.Handle.QueryRow("SELECT name, cookie, ip FROM player WHERE id = ?", leon.Database_ID).Scan(&name, &cookie, &ip)
+		if name != anonymousName || cookie != "" || ip != "" {
+			t.Errorf("player after anonymize = %q %q %q", name, cookie, ip)
+		}
+		db.Handle.QueryRow("SELECT name FROM player WHERE id = ?", claire.Database_ID).Scan(&name)
+		if name != "claire" {
+			t.Errorf("someone else was anonymized: %q", name)
+		}
+		var offenses int
+		db.Handle.QueryRow("SELECT COUNT(*) FROM offense").Scan(&offenses)
+		if offenses != 1 {
+			t.Errorf("anonymize removed offenses, %d left", offenses)
+		}
+	})
+
+	t.Run("erase", func(t *testing.T) {
+		leon, _ := addDataTestPlayers(t)
+		db.Handle.Exec("INSERT INTO player_stat (player, frags, deaths) VALUES (?, 10, 2)", leon.Database_ID)
+		db.Handle.Exec("INSERT INTO rating (profile, pool, rating) VALUES (?, 'tdm', 1600)", leon.ProfileID)
+		db.Handle.Exec("INSERT INTO vpn_cache (ip, vpn) VALUES ('192.0.2.8', 0)")
+		db.Handle.Exec("INSERT INTO vpn_cache (ip, vpn, override) VALUES ('192.0.2.9', 0, 'allow')")
+		rows, err := ErasePlayerData("cookie:aaaa", false, "rpc:root@example.com")
+		if err != nil || rows["player"] != 2 || rows["profile"] != 2 || rows["offense"] != 1 || rows["vpn_cache"] != 1 {
+			t.Fatalf("ErasePlayerData() = %v, %v", rows, err)
+		}
+		var personal, notes, stats, ratings, overrides int
+		db.Handle.QueryRow("SELECT COUNT(*) FROM player WHERE name != ? OR ip != '' OR cookie != ''", anonymousName).Scan(&personal)
+		db.Handle.QueryRow("SELECT COUNT(*) FROM player_note").Scan(&notes)
+		db.Handle.QueryRow("SELECT COUNT(*) FROM player_stat WHERE player = ?", leon.Database_ID).Scan(&stats)
+		db.Handle.QueryRow("SELECT COUNT(*) FROM rating WHERE profile = ?", leon.ProfileID).Scan(&ratings)
+		db.Handle.QueryRow("SELECT COUNT(*) FROM vpn_cache").Scan(&overrides)
+		if personal != 1 || notes != 1 {
+			t.Errorf("after erase %d players and %d notes with personal data left, want 1 each", personal, notes)
+		}
+		if stats != 1 || ratings != 1 || overrides != 1 {
+			t.Errorf("after erase %d stats, %d ratings and %d vpn overrides left, want 1 each", stats, ratings, overrides)
+		}
+		if s, _ := FindDataSubject("cookie:aaaa"); len(s.Players) != 0 {
+			t.Errorf("FindDataSubject() after erase = %+v", s)
+		}
+		log, _ := LoadAdminLog("", 10)
+		if len(log) != 1 || log[0].Action != DataErase {
+			t.Errorf("LoadAdminLog() = %+v", log)
+		}
+	})
+
+	t.Run("shared identities", func(t *testing.T) {
+		addDataTestPlayers(t)
+		for _, id := range []string{"name:leon", "ip:192.0.2.8"} {
+			for _, anonymize := range []bool{false, true} {
+				if _, err := ErasePlayerData(id, anonymize, "rpc:root@example.com"); err == nil {
+					t.Errorf("ErasePlayerData(%q, %t) = nil, want error", id, anonymize)
+				}
+			}
+		}
+		var players int
+		db.Handle.QueryRow("SELECT COUNT(*) FROM player WHERE name = 'leon'").Scan(&players)
+		if players != 1 {
+			t.Errorf("refused erase changed players")
+		}
+	})
+}
+
+func TestDataTablesCovered(t *testing.T) {
+	useTestDatabase(t)
+	rows, err := db.Handle.Query("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			t.Fatal(err)
+		}
+		covered := slices.Contains(dataTablesSkipped, table)
+		for _, dt := range dataTables {
+			covered = covered || dt.table == table
+		}
+		if !covered {
+			t.Errorf("table %q isn't in dataTables or dataTablesSkipped", table)
+		}
+	}
+}
@@ -0,0 +1,502 @@
+// Player profiles tie individual sightings (rows in the player table) together
+// into a single person. Every connect is linked to a profile based on what we
+// know about the player:
+//
+//   - cookie: a definitive match, cookies are unique per client install
+//   - address: a strong signal, but addresses change and are shared (NAT),
+//     so it takes another signal with it to link
+//   - name: a weak signal, names are trivially changed or impersonated
+//
+// Each signal adds to a confidence score for any existing profile it's been
+// seen with. If the best score passes the link threshold, the sighting is
+// added to that profile, otherwise a new profile is created. Profiles that
+// turn out to be the same person can be merged by an admin.
+package backend
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/packetflinger/q2admind/frontend"
+	pb "github.com/packetflinger/q2admind/proto"
+)
+
+// The kinds of data linked to a profile
+const (
+	ProfileLinkCookie  = "cookie"
+	ProfileLinkAddress = "ip"
+	ProfileLinkName    = "name"
+	ProfileLinkClient  = "client"
+	ProfileLinkServer  = "server"
+)
+
+const (
+	profileScoreCookie   = 1.0
+	profileScoreAddress  = 0.5
+	profileScoreName     = 0.3
+	profileLinkThreshold = 0.8 // an address and a name, shared addresses aren't enough alone
+	profileMaxMergeDepth = 10  // how many merged_into hops to follow
+)
+
+// Profile is a single person made up of all their sightings
+type Profile struct {
+	ID         int64
+	Name       string // most recently used name
+	Created    int64
+	MergedInto int64
+	Aliases    []ProfileLink
+	Addresses  []ProfileLink
+	Cookies    []ProfileLink
+	Clients    []ProfileLink
+	Servers    []ProfileLink
+	Stats      ProfileStats
+	Weapons    []WeaponTotal
+	Verified   bool // linked to a website account
+}
+
+// ProfileLink is one piece of identifying data (a name, an address, etc) and
+// the history of it being used by the profile.
+type ProfileLink struct {
+	Kind       string
+	Value      string
+	Confidence float64 // 0-1
+	FirstSeen  int64
+	LastSeen   int64
+	Count      int
+}
+
+// ProfileStats are the totals across all sightings of a profile
+type ProfileStats struct {
+	Sightings int
+	Frags     int
+	Deaths    int
+	Suicides  int
+	PlayTime  int64 // seconds
+}
+
+// A profile having been seen with one kind of data the player is using
+type profileCandidate struct {
+	profile int64
+	kind    string
+}
+
+// scoreProfiles will total up the confidence for each candidate profile and
+// return the best one. A cookie match is definitive, the others add up. Ties
+// go to the oldest (lowest ID) profile.
+//
+// Called from LinkPlayerProfile()
+func scoreProfiles(candidates []profileCandidate) (int64, float64) {
+	scores := make(map[int64]float64)
+	seen := make(map[profileCandidate]bool)
+	for _, c := range candidates {
+		if seen[c] {
+			continue
+		}
+		seen[c] = true
+		switch c.kind {
+		case ProfileLinkCookie:
+			scores[c.profile] += profileScoreCookie
+		case ProfileLinkAddress:
+			scores[c.profile] += profileScoreAddress
+		case ProfileLinkName:
+			scores[c.profile] += profileScoreName
+		}
+	}
+	var best int64
+	var bestScore float64
+	for id, score := range scores {
+		score = min(math.Round(score*100)/100, 1.0)
+		if score > bestScore || (score == bestScore && id < best) {
+			best, bestScore = id, score
+		}
+	}
+	return best, bestScore
+}
+
+// profileLinkValues gives the identifying data for a player, keyed by kind.
+// Names are compared case-insensitively so they're stored lowercase.
+func profileLinkValues(p *frontend.Player) map[string]string {
+	values := map[string]string{
+		ProfileLinkCookie:  p.Cookie,
+		ProfileLinkAddress: p.IP,
+		ProfileLinkName:    strings.ToLower(p.Name),
+		ProfileLinkClient:  p.Version,
+	}
+	if p.Frontend != nil {
+		values[ProfileLinkServer] = p.Frontend.Name
+	}
+	return values
+}
+
+// LinkPlayerProfile will find (or create) the profile for a newly connected
+// player, record this sighting's data against it and set the player's
+// ProfileID.
+//
+// Called from ParseConnect() before rules are checked
+func LinkPlayerProfile(p *frontend.Player) error {
+	if p == nil {
+		return fmt.Errorf("error linking profile: null player")
+	}
+	values := profileLinkValues(p)
+	qry := `
+		SELECT profile, kind FROM profile_link
+		WHERE (kind = ? AND value = ? AND value != '')
+			OR (kind = ? AND value = ?)
+			OR (kind = ? AND value = ?)`
+	rows, err := db.Handle.Query(qry,
+		ProfileLinkCookie, values[ProfileLinkCookie],
+		ProfileLinkAddress, values[ProfileLinkAddress],
+		ProfileLinkName, values[ProfileLinkName],
+	)
+	if err != nil {
+		return fmt.Errorf("error finding profile candidates for %q: %v", p.Name, err)
+	}
+	var candidates []profileCandidate
+	for rows.Next() {
+		var c profileCandidate
+		if err := rows.Scan(&c.profile, &c.kind); err != nil {
+			rows.Close()
+			return fmt.Errorf("error scanning profile candidates: %v", err)
+		}
+		candidates = append(candidates, c)
+	}
+	rows.Close()
+
+	id, score := scoreProfiles(candidates)
+	if score < profileLinkThreshold {
+		id, err = createProfile(p.Name)
+		if err != nil {
+			return err
+		}
+		score = 1.0
+	}
+	if err := recordProfileSighting(id, score, p, values); err != nil {
+		return err
+	}
+	p.ProfileID = id
+	p.ProfileScore = score
+	return nil
+}
+
+// LinkPlayerCookie is used when a cookie shows up after the player was
+// already linked to a profile (new players are given a cookie after they
+// connect). If that cookie already belongs to another profile, the player's
+// current profile is merged into it, unless the current profile has its own
+// cookie (then it's a different client install and the sighting is moved).
+//
+// Called from ParsePlayerUpdate()
+func LinkPlayerCookie(p *frontend.Player) error {
+	if p == nil || p.Cookie == "" || p.ProfileID == 0 {
+		return nil
+	}
+	var owner int64
+	qry := "SELECT profile FROM profile_link WHERE kind = ? AND value = ? LIMIT 1"
+	err := db.Handle.QueryRow(qry, ProfileLinkCookie, p.Cookie).Scan(&owner)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("error finding profile for cookie: %v", err)
+	}
+	if owner == 0 || owner == p.ProfileID {
+		return AddProfileLink(p.ProfileID, ProfileLinkCookie, p.Cookie, p.ProfileScore)
+	}
+
+	var cookies int
+	qry = "SELECT COUNT(*) FROM profile_link WHERE profile = ? AND kind = ?"
+	err = db.Handle.QueryRow(qry, p.ProfileID, ProfileLinkCookie).Scan(&cookies)
+	if err != nil {
+		return fmt.Errorf("error counting cookies for profile %d: %v", p.ProfileID, err)
+	}
+	if cookies == 0 {
+		return MergeProfiles(p.ProfileID, owner)
+	}
+	if err := recordProfileSighting(owner, profileScoreCookie, p, profileLinkValues(p)); err != nil {
+		return err
+	}
+	p.ProfileID = owner
+	p.ProfileScore = profileScoreCookie
+	return nil
+}
+
+// createProfile will insert a new, empty profile and return its ID
+func createProfile(name string) (int64, error) {
+	qry := "INSERT INTO profile (name, created) VALUES (?,?)"
+	res, err := db.Handle.Exec(qry, name, time.Now().Unix())
+	if err != nil {
+		return 0, fmt.Errorf("error creating profile for %q: %v", name, err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("error getting id of new profile for %q: %v", name, err)
+	}
+	return id, nil
+}
+
+// recordProfileSighting will add all of a player's data to a profile and
+// point their database record at it.
+func recordProfileSighting(id int64, score float64, p *frontend.Player, values map[string]string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting profile transaction: %v", err)
+	}
+	defer tx.Rollback()
+	now := time.Now().Unix()
+	for kind, value := range values {
+		if value == "" {
+			continue
+		}
+		if err := upsertProfileLink(tx, id, kind, value, score, now); err != nil {
+			return err
+		}
+	}
+	_, err = tx.Exec("UPDATE player SET profile = ? WHERE id = ?", id, p.Database_ID)
+	if err != nil {
+		return fmt.Errorf("error linking player %d to profile %d: %v", p.Database_ID, id, err)
+	}
+	_, err = tx.Exec("UPDATE profile SET name = ? WHERE id = ?", p.Name, id)
+	if err != nil {
+		return fmt.Errorf("error updating name for profile %d: %v", id, err)
+	}
+	return tx.Commit()
+}
+
+// AddProfileLink will record a single piece of data against a profile, like
+// a name change during a game.
+func AddProfileLink(id int64, kind string, value string, score float64) error {
+	if id == 0 || value == "" {
+		return nil
+	}
+	if kind == ProfileLinkName {
+		value = strings.ToLower(value)
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting profile transaction: %v", err)
+	}
+	defer tx.Rollback()
+	if err := upsertProfileLink(tx, id, kind, value, score, time.Now().Unix()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func upsertProfileLink(tx *sql.Tx, id int64, kind, value string, score float64, now int64) error {
+	qry := `
+		INSERT INTO profile_link (profile, kind, value, confidence, first_seen, last_seen, count)
+		VALUES (?,?,?,?,?,?,1)
+		ON CONFLICT (profile, kind, value) DO UPDATE SET
+			confidence = max(confidence, excluded.confidence),
+			last_seen = excluded.last_seen,
+			count = count + 1`
+	_, err := tx.Exec(qry, id, kind, value, score, now, now)
+	if err != nil {
+		return fmt.Errorf("error linking %s %q to profile %d: %v", kind, value, id, err)
+	}
+	return nil
+}
+
+// MergeProfiles will fold one profile into another. All the linked data and
+// player sightings are moved, and the old profile is left pointing at the new
+// one so old references still resolve. Rules referencing the old profile are
+// updated too.
+func MergeProfiles(from int64, into int64) error {
+	if from == into {
+		return fmt.Errorf("can't merge profile %d into itself", from)
+	}
+	if from <= 0 || into <= 0 {
+		return fmt.Errorf("invalid profile ids %d, %d", from, into)
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting merge transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	// "WHERE true" is needed for sqlite to parse the upsert after a select
+	qry := `
+		INSERT INTO profile_link (profile, kind, value, confidence, first_seen, last_seen, count)
+		SELECT ?, kind, value, confidence, first_seen, last_seen, count
+		FROM profile_link WHERE profile = ? AND true
+		ON CONFLICT (profile, kind, value) DO UPDATE SET
+			confidence = max(confidence, excluded.confidence),
+			first_seen = min(first_seen, excluded.first_seen),
+			last_seen = max(last_seen, excluded.last_seen),
+			count = count + excluded.count`
+	stmts := []struct {
+		qry  string
+		args []any
+	}{
+		{qry, []any{into, from}},
+		{"DELETE FROM profile_link WHERE profile = ?", []any{from}},
+		{"UPDATE player SET profile = ? WHERE profile = ?", []any{into, from}},
+		{"UPDATE account_link SET profile = ? WHERE profile = ?", []any{into, from}},
+		{"UPDATE profile SET merged_into = ? WHERE id = ? OR merged_into = ?", []any{into, from, from}},
+	}
+	for _, st := range stmts {
+		if _, err := tx.Exec(st.qry, st.args...); err != nil {
+			return fmt.Errorf("error merging profile %d into %d: %v", from, into, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error merging profile %d into %d: %v", from, into, err)
+	}
+
+	for i := range be.frontends {
+		fe := &be.frontends[i]
+		for j := range fe.Players {
+			if fe.Players[j].ProfileID == from {
+				fe.Players[j].ProfileID = into
+			}
+		}
+		if repointProfileRules(fe.Rules, from, into) {
+			if err := fe.MaterializeRules(fe.Rules); err != nil {
+				be.Logf(LogLevelNormal, "error writing rules for %q after profile merge: %v", fe.Name, err)
+			}
+		}
+	}
+	// global rules are managed by hand in the rules file, only the in-memory
+	// copy is updated here.
+	repointProfileRules(be.rules, from, into)
+	return nil
+}
+
+// repointProfileRules will replace references to one profile with another in
+// both the rules and their exceptions. Returns whether anything changed.
+func repointProfileRules(rules []*pb.Rule, from int64, into int64) bool {
+	changed := false
+	repoint := func(ids []int64) []int64 {
+		if !slices.Contains(ids, from) {
+			return ids
+		}
+		changed = true
+		out := []int64{}
+		for _, id := range ids {
+			if id == from {
+				id = into
+			}
+			if !slices.Contains(out, id) {
+				out = append(out, id)
+			}
+		}
+		return out
+	}
+	for _, r := range rules {
+		r.Profile = repoint(r.GetProfile())
+		for _, ex := range r.GetException() {
+			ex.Profile = repoint(ex.GetProfile())
+		}
+	}
+	return changed
+}
+
+// LoadProfile will fetch a profile and all its history from the database.
+// Merged profiles are followed to the profile they were merged into.
+func LoadProfile(id int64) (*Profile, error) {
+	pr := &Profile{}
+	qry := "SELECT id, name, created, merged_into FROM profile WHERE id = ?"
+	for range profileMaxMergeDepth {
+		err := db.Handle.QueryRow(qry, id).Scan(&pr.ID, &pr.Name, &pr.Created, &pr.MergedInto)
+		if err != nil {
+			return nil, fmt.Errorf("error loading profile %d: %v", id, err)
+		}
+		if pr.MergedInto == 0 {
+			break
+		}
+		id = pr.MergedInto
+	}
+	if pr.MergedInto != 0 {
+		return nil, fmt.Errorf("error loading profile %d: too many merges", id)
+	}
+
+	qry = `
+		SELECT kind, value, confidence, first_seen, last_seen, count
+		FROM profile_link WHERE profile = ?
+		ORDER BY last_seen DESC`
+	rows, err := db.Handle.Query(qry, pr.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading links for profile %d: %v", pr.ID, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var l ProfileLink
+		err := rows.Scan(&l.Kind, &l.Value, &l.Confidence, &l.FirstSeen, &l.LastSeen, &l.Count)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning links for profile %d: %v", pr.ID, err)
+		}
+		switch l.Kind {
+		case ProfileLinkName:
+			pr.Aliases = append(pr.Aliases, l)
+		case ProfileLinkAddress:
+			pr.Addresses = append(pr.Addresses, l)
+		case ProfileLinkCookie:
+			pr.Cookies = append(pr.Cookies, l)
+		case ProfileLinkClient:
+			pr.Clients = append(pr.Clients, l)
+		case ProfileLinkServer:
+			pr.Servers = append(pr.Servers, l)
+		}
+	}
+
+	qry = `
+		SELECT
+			COUNT(DISTINCT p.id),
+			COALESCE(SUM(s.frags), 0),
+			COALESCE(SUM(s.deaths), 0),
+			COALESCE(SUM(s.suicides), 0),
+			COALESCE(SUM(s.play_time), 0)
+		FROM player AS p
+		LEFT JOIN player_stat AS s ON s.player = p.id
+		WHERE p.profile = ?`
+	st := &pr.Stats
+	err = db.Handle.QueryRow(qry, pr.ID).Scan(&st.Sightings, &st.Frags, &st.Deaths, &st.Suicides, &st.PlayTime)
+	if err != nil {
+		return nil, fmt.Errorf("error loading stats for profile %d: %v", pr.ID, err)
+	}
+	pr.Weapons, err = ProfileWeaponStats(pr.ID, "")
+	if err != nil {
+		return nil, err
+	}
+	pr.Verified = profileLinked(pr.ID)
+	return pr, nil
+}
+
+// SeenOn checks if a profile has ever been seen on a particular frontend
+func (pr *Profile) SeenOn(server string) bool {
+	for _, s := range pr.Servers {
+		if s.Value == server {
+			return true
+		}
+	}
+	return false
+}
+
+// VisibleTo checks if a website user can look at a profile. Profiles hold
+// the addresses and cookies seen on every server, so only owners of a server
+// the profile played on (and backend admins) can.
+func (pr *Profile) VisibleTo(u *pb.User) bool {
+	if BackendAdmin(u.GetEmail()) {
+		return true
+	}
+	for _, fe := range MyFrontends(u) {
+		if pr.SeenOn(fe.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// FirstSeen is the earliest sighting of any of the profile's data
+func (pr *Profile) FirstSeen() int64 {
+	var first int64
+	for _, links := range [][]ProfileLink{pr.Aliases, pr.Addresses, pr.Cookies, pr.Clients, pr.Servers} {
+		for _, l := range links {
+			if first == 0 || l.FirstSeen < first {
+				first = l.FirstSeen
+			}
+		}
+	}
+	return first
+}
@@ -0,0 +1,266 @@
+package backend
+
+import (
+	"path"
+	"slices"
+	"testing"
+
+	"github.com/packetflinger/q2admind/database"
+	"github.com/packetflinger/q2admind/frontend"
+
+	pb "github.com/packetflinger/q2admind/proto"
+)
+
+// useTestDatabase will point the package database at a fresh, empty database
+// for the duration of a test.
+func useTestDatabase(t *testing.T) {
+	t.Helper()
+	d, err := database.Open(path.Join(t.TempDir(), "test.sqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := db
+	db = d
+	t.Cleanup(func() {
+		d.Handle.Close()
+		db = orig
+	})
+}
+
+// addTestPlayer will insert a player record like AddPlayer() does on connect
+func addTestPlayer(t *testing.T, fe *frontend.Frontend, p *frontend.Player) {
+	t.Helper()
+	fe.Data = &db
+	p.Frontend = fe
+	if err := fe.AddPlayer(p); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScoreProfiles(t *testing.T) {
+	tests := []struct {
+		desc       string
+		candidates []profileCandidate
+		wantID     int64
+		wantScore  float64
+	}{
+		{
+			desc:       "no candidates",
+			candidates: nil,
+			wantID:     0,
+			wantScore:  0,
+		},
+		{
+			desc: "cookie",
+			candidates: []profileCandidate{
+				{profile: 4, kind: ProfileLinkCookie},
+				{profile: 2, kind: ProfileLinkName},
+			},
+			wantID:    4,
+			wantScore: 1.0,
+		},
+		{
+			desc: "address and name",
+			candidates: []profileCandidate{
+				{profile: 3, kind: ProfileLinkAddress},
+				{profile: 3, kind: ProfileLinkName},
+				{profile: 5, kind: ProfileLinkAddress},
+			},
+			wantID:    3,
+			wantScore: 0.8,
+		},
+		{
+			desc: "duplicates only count once",
+			candidates: []profileCandidate{
+				{profile: 3, kind: ProfileLinkName},
+				{profile: 3, kind: ProfileLinkName},
+				{profile: 3, kind: ProfileLinkName},
+			},
+			wantID:    3,
+			wantScore: 0.3,
+		},
+		{
+			desc: "capped",
+			candidates: []profileCandidate{
+				{profile: 7, kind: ProfileLinkCookie},
+				{profile: 7, kind: ProfileLinkAddress},
+				{profile: 7, kind: ProfileLinkName},
+			},
+			wantID:    7,
+			wantScore: 1.0,
+		},
+		{
+			desc: "tie goes to oldest",
+			candidates: []profileCandidate{
+				{profile: 9, kind: ProfileLinkAddress},
+				{profile: 6, kind: ProfileLinkAddress},
+			},
+			wantID:    6,
+			wantScore: 0.5,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			id, score := scoreProfiles(tc.candidates)
+			if id != tc.wantID || score != tc.wantScore {
+				t.Errorf("scoreProfiles() = %d, %.2f, want %d, %.2f", id, score, tc.wantID, tc.wantScore)
+			}
+		})
+	}
+}
+
+func TestLinkPlayerProfile(t *testing.T) {
+	useTestDatabase(t)
+	fe := &frontend.Frontend{Name: "test1"}
+
+	first := &frontend.Player{Name: "Claire", IP: "192.0.2.4", Cookie: "aaaa", Version: "q2pro"}
+	addTestPlayer(t, fe, first)
+	if err := LinkPlayerProfile(first); err != nil {
+		t.Fatal(err)
+	}
+	if first.ProfileID == 0 {
+		t.Fatal("first sighting wasn't given a profile")
+	}
+
+	tests := []struct {
+		desc     string
+		player   *frontend.Player
+		wantSame bool
+	}{
+		{
+			desc:     "same cookie, different everything else",
+			player:   &frontend.Player{Name: "someone", IP: "198.51.100.9", Cookie: "aaaa"},
+			wantSame: true,
+		},
+		{
+			desc:     "same address and name",
+			player:   &frontend.Player{Name: "claire", IP: "192.0.2.4"},
+			wantSame: true,
+		},
+		{
+			desc:     "shared address, different cookie and name",
+			player:   &frontend.Player{Name: "leon", IP: "192.0.2.4", Cookie: "bbbb"},
+			wantSame: false,
+		},
+		{
+			desc:     "name only",
+			player:   &frontend.Player{Name: "Claire", IP: "203.0.113.50"},
+			wantSame: false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			addTestPlayer(t, fe, tc.player)
+			if err := LinkPlayerProfile(tc.player); err != nil {
+				t.Fatal(err)
+			}
+			if same := tc.player.ProfileID == first.ProfileID; same != tc.wantSame {
+				t.Errorf("linked to profile %d (first is %d), want same: %t", tc.player.ProfileID, first.ProfileID, tc.wantSame)
+			}
+		})
+	}
+
+	pr, err := LoadProfile(first.ProfileID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.Stats.Sightings != 3 {
+		t.Errorf("profile sightings = %d, want 3", pr.Stats.Sightings)
+	}
+	if len(pr.Aliases) != 2 {
+		t.Errorf("profile aliases = %v, want 2", pr.Aliases)
+	}
+	if len(pr.Addresses) != 2 {
+		t.Errorf("profile addresses = %v, want 2", pr.Addresses)
+	}
+	if !pr.SeenOn("test1") {
+		t.Errorf("profile not seen on test1: %v", pr.Servers)
+	}
+}
+
+func TestProfileVisibleTo(t *testing.T) {
+	saved := be.frontends
+	t.Cleanup(func() { be.frontends = saved })
+	be.frontends = []frontend.Frontend{
+		{Name: "test1", Owner: "leon@example.com"},
+		{Name: "test2", Owner: "ada@example.com"},
+	}
+	pr := &Profile{Servers: []ProfileLink{{Kind: ProfileLinkServer, Value: "test1"}}}
+	if !pr.VisibleTo(&pb.User{Email: "leon@example.com"}) {
+		t.Error("VisibleTo() owner of a server it played on = false")
+	}
+	if pr.VisibleTo(&pb.User{Email: "ada@example.com"}) {
+		t.Error("VisibleTo() owner of another server = true")
+	}
+	if pr.VisibleTo(nil) {
+		t.Error("VisibleTo(nil) = true")
+	}
+}
+
+func TestMergeProfiles(t *testing.T) {
+	useTestDatabase(t)
+	fe := &frontend.Frontend{Name: "test1"}
+
+	p1 := &frontend.Player{Name: "claire", IP: "192.0.2.4", Cookie: "aaaa"}
+	p2 := &frontend.Player{Name: "leon", IP: "198.51.100.9", Cookie: "bbbb"}
+	for _, p := range []*frontend.Player{p1, p2} {
+		addTestPlayer(t, fe, p)
+		if err := LinkPlayerProfile(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if p1.ProfileID == p2.ProfileID {
+		t.Fatal("unrelated players linked to the same profile")
+	}
+	if err := MergeProfiles(p2.ProfileID, p1.ProfileID); err != nil {
+		t.Fatal(err)
+	}
+	if err := MergeProfiles(p1.ProfileID, p1.ProfileID); err == nil {
+		t.Error("merging a profile into itself didn't fail")
+	}
+
+	// the old id should resolve to the merged profile
+	pr, err := LoadProfile(p2.ProfileID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.ID != p1.ProfileID {
+		t.Errorf("merged profile loaded as %d, want %d", pr.ID, p1.ProfileID)
+	}
+	if len(pr.Cookies) != 2 || len(pr.Aliases) != 2 || pr.Stats.Sightings != 2 {
+		t.Errorf("merged profile has %d cookies, %d aliases, %d sightings, want 2 of each", len(pr.Cookies), len(pr.Aliases), pr.Stats.Sightings)
+	}
+	if pr.Servers[0].Count != 2 {
+		t.Errorf("merged server count = %d, want 2", pr.Servers[0].Count)
+	}
+
+	// a new sighting with the merged cookie lands on the surviving profile
+	p3 := &frontend.Player{Name: "leon", IP: "203.0.113.50", Cookie: "bbbb"}
+	addTestPlayer(t, fe, p3)
+	if err := LinkPlayerProfile(p3); err != nil {
+		t.Fatal(err)
+	}
+	if p3.ProfileID != p1.ProfileID {
+		t.Errorf("sighting after merge linked to %d, want %d", p3.ProfileID, p1.ProfileID)
+	}
+}
+
+func TestRepointProfileRules(t *testing.T) {
+	rules := []*pb.Rule{
+		{Profile: []int64{5, 2}},
+		{Profile: []int64{9}, Exception: []*pb.Exception{{Profile: []int64{2}}}},
+		{Profile: []int64{3}},
+	}
+	if !repointProfileRules(rules, 2, 5) {
+		t.Fatal("repointProfileRules() = false, want true")
+	}
+	if got := rules[0].GetProfile(); !slices.Equal(got, []int64{5}) {
+		t.Errorf("rule 0 profiles = %v, want [5]", got)
+	}
+	if got := rules[1].GetException()[0].GetProfile(); !slices.Equal(got, []int64{5}) {
+		t.Errorf("rule 1 exception profiles = %v, want [5]", got)
+	}
+	if repointProfileRules(rules[2:], 2, 5) {
+		t.Error("repointProfileRules() changed an unrelated rule")
+	}
+}
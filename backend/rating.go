@@ -0,0 +1,251 @@
+// Skill ratings. Every frag is a small contest between the attacker and the
+// victim. The frags between each pair of players are collected over a match
+// and when it ends ratings are adjusted using the Elo system: each pairing
+// counts as a single game where the score is the share of frags each player
+// got against the other. Beating a higher rated player is worth more than
+// beating a lower rated one.
+//
+// Ratings are kept per profile and per pool. Frontends in the same pool
+// (usually the same mod) share ratings, since being good at one game mode
+// says little about another.
+package backend
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/packetflinger/q2admind/frontend"
+)
+
+const (
+	ratingDefault = 1500.0 // where everyone starts
+	ratingK       = 32.0   // most a rating can move against a single opponent
+)
+
+// recordDuel will count a frag toward the current match's ratings. Bots and
+// unprofiled players aren't rated.
+//
+// Called from ParseObituary()
+func recordDuel(fe *frontend.Frontend, attacker, victim *frontend.Player) {
+	if fe == nil || attacker == nil || victim == nil || fe.MatchStart == 0 {
+		return
+	}
+	if attacker.ProfileID == 0 || victim.ProfileID == 0 || attacker.ProfileID == victim.ProfileID {
+		return
+	}
+	if attacker.IsBot() || victim.IsBot() {
+		return
+	}
+	if fe.MatchFrags == nil {
+		fe.MatchFrags = make(map[frontend.Duel]int)
+	}
+	fe.MatchFrags[frontend.Duel{Attacker: attacker.ProfileID, Victim: victim.ProfileID}]++
+}
+
+// expectedScore is the share of frags a player rated a is expected to get
+// against a player rated b
+func expectedScore(a, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
+
+// eloDeltas works out how much each profile's rating changes from a match.
+// Ratings missing from the map are treated as new players. Returns the
+// change for each profile and the number of opponents they faced.
+func eloDeltas(ratings map[int64]float64, frags map[frontend.Duel]int) (map[int64]float64, map[int64]int) {
+	type pairing struct {
+		low, high int64 // profile ids, lowest first
+	}
+	wins := make(map[pairing][2]int)
+	for d, n := range frags {
+		if d.Attacker == d.Victim || n <= 0 {
+			continue
+		}
+		p := pairing{low: min(d.Attacker, d.Victim), high: max(d.Attacker, d.Victim)}
+		w := wins[p]
+		if d.Attacker == p.low {
+			w[0] += n
+		} else {
+			w[1] += n
+		}
+		wins[p] = w
+	}
+
+	rating := func(id int64) float64 {
+		if r, ok := ratings[id]; ok {
+			return r
+		}
+		return ratingDefault
+	}
+	deltas := make(map[int64]float64)
+	games := make(map[int64]int)
+	for p, w := range wins {
+		score := float64(w[0]) / float64(w[0]+w[1])
+		change := ratingK * (score - expectedScore(rating(p.low), rating(p.high)))
+		deltas[p.low] += change
+		deltas[p.high] -= change
+		games[p.low]++
+		games[p.high]++
+	}
+	return deltas, games
+}
+
+// LoadRating will fetch a profile's rating in a pool. Profiles that haven't
+// been rated yet get the default.
+func LoadRating(profile int64, pool string) (float64, error) {
+	rating := ratingDefault
+	if profile == 0 {
+		return rating, nil
+	}
+	qry := "SELECT rating FROM rating WHERE profile = ? AND pool = ?"
+	err := db.Handle.QueryRow(qry, profile, pool).Scan(&rating)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return ratingDefault, fmt.Errorf("error loading rating for profile %d in %q: %v", profile, pool, err)
+	}
+	return rating, nil
+}
+
+// UpdateRatings will apply the frags from a match to everyone's ratings in a
+// pool. Returns the new rating for each profile involved.
+func UpdateRatings(pool string, frags map[frontend.Duel]int) (map[int64]float64, error) {
+	ratings := make(map[int64]float64)
+	for d := range frags {
+		for _, id := range []int64{d.Attacker, d.Victim} {
+			if _, ok := ratings[id]; ok {
+				continue
+			}
+			r, err := LoadRating(id, pool)
+			if err != nil {
+				return nil, err
+			}
+			ratings[id] = r
+		}
+	}
+	deltas, games := eloDeltas(ratings, frags)
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error updating ratings in %q: %v", pool, err)
+	}
+	defer tx.Rollback()
+	qry := `
+		INSERT INTO rating (profile, pool, rating, games, updated)
+		VALUES (?,?,?,?,?)
+		ON CONFLICT (profile, pool) DO UPDATE SET
+			rating = excluded.rating,
+			games = games + excluded.games,
+			updated = excluded.updated`
+	now := time.Now().Unix()
+	for id, change := range deltas {
+		ratings[id] += change
+		if _, err := tx.Exec(qry, id, pool, ratings[id], games[id], now); err != nil {
+			return nil, fmt.Errorf("error updating rating for profile %d in %q: %v", id, pool, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error updating ratings in %q: %v", pool, err)
+	}
+	return ratings, nil
+}
+
+// rateMatch will update ratings from the frags in a match that just ended and
+// refresh the ratings of the players still connected.
+//
+// Called from EndMatch()
+func rateMatch(fe *frontend.Frontend, frags map[frontend.Duel]int) {
+	if len(frags) == 0 {
+		return
+	}
+	ratings, err := UpdateRatings(fe.Pool(), frags)
+	if err != nil {
+		be.Logln(LogLevelInfo, err)
+		return
+	}
+	for i := range fe.Players {
+		if r, ok := ratings[fe.Players[i].ProfileID]; ok {
+			fe.Players[i].Rating = r
+		}
+	}
+}
+
+// ratedPlayers are the players on a frontend who have a rating
+func ratedPlayers(fe *frontend.Frontend) []*frontend.Player {
+	var players []*frontend.Player
+	for i := range fe.Players {
+		p := &fe.Players[i]
+		if p.ConnectTime == 0 || p.ProfileID == 0 || p.IsBot() {
+			continue
+		}
+		players = append(players, p)
+	}
+	return players
+}
+
+// averageRating is the mean rating of everyone rated on a frontend, 0 if
+// nobody is.
+func averageRating(fe *frontend.Frontend) float64 {
+	players := ratedPlayers(fe)
+	if len(players) == 0 {
+		return 0
+	}
+	var total float64
+	for _, p := range players {
+		total += p.Rating
+	}
+	return total / float64(len(players))
+}
+
+// BalanceTeams will split players into two teams of close to equal total
+// rating. Players are handed out strongest first to whichever team is
+// weaker, as long as that doesn't make the team sizes uneven.
+func BalanceTeams(players []*frontend.Player) ([]*frontend.Player, []*frontend.Player) {
+	sorted := slices.Clone(players)
+	slices.SortStableFunc(sorted, func(a, b *frontend.Player) int {
+		switch {
+		case a.Rating > b.Rating:
+			return -1
+		case a.Rating < b.Rating:
+			return 1
+		}
+		return 0
+	})
+	var red, blue []*frontend.Player
+	var redTotal, blueTotal float64
+	size := (len(sorted) + 1) / 2
+	for _, p := range sorted {
+		if len(blue) >= size || (len(red) < size && redTotal <= blueTotal) {
+			red = append(red, p)
+			redTotal += p.Rating
+		} else {
+			blue = append(blue, p)
+			blueTotal += p.Rating
+		}
+	}
+	return red, blue
+}
+
+// suggestServer finds another server in the same rating pool whose players
+// are closest in skill to this player. Blank if there isn't one.
+func suggestServer(p *frontend.Player) string {
+	if p == nil || p.Frontend == nil || p.ProfileID == 0 {
+		return ""
+	}
+	best, bestDiff := "", math.MaxFloat64
+	for i := range be.frontends {
+		fe := &be.frontends[i]
+		if !fe.Trusted || fe.Name == p.Frontend.Name || fe.Pool() != p.Frontend.Pool() {
+			continue
+		}
+		avg := averageRating(fe)
+		if avg == 0 {
+			continue
+		}
+		if diff := math.Abs(avg - p.Rating); diff < bestDiff {
+			best, bestDiff = fe.Name, diff
+		}
+	}
+	return best
+}
@@ -0,0 +1,165 @@
+package backend
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/packetflinger/q2admind/frontend"
+)
+
+func TestEloDeltas(t *testing.T) {
+	tests := []struct {
+		desc    string
+		ratings map[int64]float64
+		frags   map[frontend.Duel]int
+		want    map[int64]float64
+	}{
+		{
+			desc:  "even split between new players",
+			frags: map[frontend.Duel]int{{Attacker: 1, Victim: 2}: 5, {Attacker: 2, Victim: 1}: 5},
+			want:  map[int64]float64{1: 0, 2: 0},
+		},
+		{
+			desc:  "shutout between new players",
+			frags: map[frontend.Duel]int{{Attacker: 1, Victim: 2}: 10},
+			want:  map[int64]float64{1: 16, 2: -16},
+		},
+		{
+			desc:    "favorite wins",
+			ratings: map[int64]float64{1: 1900, 2: 1500},
+			frags:   map[frontend.Duel]int{{Attacker: 1, Victim: 2}: 10},
+			want:    map[int64]float64{1: 2.91, 2: -2.91},
+		},
+		{
+			desc:    "underdog wins",
+			ratings: map[int64]float64{1: 1900, 2: 1500},
+			frags:   map[frontend.Duel]int{{Attacker: 2, Victim: 1}: 10},
+			want:    map[int64]float64{1: -29.09, 2: 29.09},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, games := eloDeltas(tc.ratings, tc.frags)
+			var sum float64
+			for id, want := range tc.want {
+				if math.Abs(got[id]-want) > 0.01 {
+					t.Errorf("eloDeltas()[%d] = %.2f, want %.2f", id, got[id], want)
+				}
+				if games[id] != 1 {
+					t.Errorf("eloDeltas() games[%d] = %d, want 1", id, games[id])
+				}
+				sum += got[id]
+			}
+			if math.Abs(sum) > 0.0001 {
+				t.Errorf("eloDeltas() changes sum to %f, want 0", sum)
+			}
+		})
+	}
+}
+
+func TestBalanceTeams(t *testing.T) {
+	var players []*frontend.Player
+	for _, r := range []float64{2000, 1800, 1600, 1500, 1400, 1200} {
+		players = append(players, &frontend.Player{Rating: r})
+	}
+	red, blue := BalanceTeams(players)
+	if len(red) != 3 || len(blue) != 3 {
+		t.Fatalf("BalanceTeams() sizes = %d/%d, want 3/3", len(red), len(blue))
+	}
+	var redTotal, blueTotal float64
+	for _, p := range red {
+		redTotal += p.Rating
+	}
+	for _, p := range blue {
+		blueTotal += p.Rating
+	}
+	if diff := math.Abs(redTotal - blueTotal); diff > 200 {
+		t.Errorf("BalanceTeams() totals = %.0f/%.0f, want within 200", redTotal, blueTotal)
+	}
+
+	red, blue = BalanceTeams(players[:3])
+	if len(red)+len(blue) != 3 || len(red)-len(blue) > 1 || len(blue)-len(red) > 1 {
+		t.Errorf("BalanceTeams() odd sizes = %d/%d", len(red), len(blue))
+	}
+}
+
+func TestRecordDuel(t *testing.T) {
+	fe := &frontend.Frontend{Name: "test1", MatchStart: time.Now().Unix()}
+	claire := &frontend.Player{ProfileID: 1, IP: "192.0.2.4"}
+	leon := &frontend.Player{ProfileID: 2, IP: "198.51.100.9"}
+	unknown := &frontend.Player{IP: "203.0.113.5"}
+	bot := &frontend.Player{ProfileID: 3, IP: "loopback"}
+
+	recordDuel(fe, claire, leon)
+	recordDuel(fe, claire, leon)
+	recordDuel(fe, leon, claire)
+	recordDuel(fe, claire, unknown)
+	recordDuel(fe, bot, claire)
+	recordDuel(fe, claire, claire)
+
+	want := map[frontend.Duel]int{
+		{Attacker: 1, Victim: 2}: 2,
+		{Attacker: 2, Victim: 1}: 1,
+	}
+	if len(fe.MatchFrags) != len(want) {
+		t.Fatalf("recordDuel() frags = %v, want %v", fe.MatchFrags, want)
+	}
+	for d, n := range want {
+		if fe.MatchFrags[d] != n {
+			t.Errorf("recordDuel() frags[%v] = %d, want %d", d, fe.MatchFrags[d], n)
+		}
+	}
+
+	fe.MatchStart = 0
+	fe.MatchFrags = nil
+	recordDuel(fe, claire, leon)
+	if len(fe.MatchFrags) != 0 {
+		t.Errorf("recordDuel() outside a match = %v, want nothing", fe.MatchFrags)
+	}
+}
+
+func TestUpdateRatings(t *testing.T) {
+	useTestDatabase(t)
+	r, err := LoadRating(1, "ffa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r != ratingDefault {
+		t.Fatalf("LoadRating() unrated = %.0f, want %.0f", r, ratingDefault)
+	}
+
+	frags := map[frontend.Duel]int{{Attacker: 1, Victim: 2}: 10}
+	for range 2 {
+		if _, err := UpdateRatings("ffa", frags); err != nil {
+			t.Fatal(err)
+		}
+	}
+	winner, err := LoadRating(1, "ffa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	loser, err := LoadRating(2, "ffa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if winner <= ratingDefault+ratingK/2 || loser >= ratingDefault-ratingK/2 {
+		t.Errorf("ratings after two wins = %.1f/%.1f, want winner > %.0f and loser < %.0f",
+			winner, loser, ratingDefault+ratingK/2, ratingDefault-ratingK/2)
+	}
+	var games int
+	if err := db.Handle.QueryRow("SELECT games FROM rating WHERE profile = 1 AND pool = 'ffa'").Scan(&games); err != nil {
+		t.Fatal(err)
+	}
+	if games != 2 {
+		t.Errorf("games = %d, want 2", games)
+	}
+
+	other, err := LoadRating(1, "ctf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other != ratingDefault {
+		t.Errorf("LoadRating() other pool = %.0f, want %.0f", other, ratingDefault)
+	}
+}
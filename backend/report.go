@@ -0,0 +1,351 @@
+// Player reports. Players can report someone misbehaving with the report
+// command in-game. A snapshot of what was going on at the time (who they both
+// are, recent chat and frags, the target's userinfo and the rules they match)
+// is saved with the report so a moderator can judge it later from the SSH
+// console or the website.
+//
+// Reports are open until a moderator claims them to look into, and are
+// closed by being resolved (action was taken) or dismissed.
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/packetflinger/q2admind/frontend"
+)
+
+// Report statuses
+const (
+	ReportOpen      = "open"
+	ReportClaimed   = "claimed"
+	ReportResolved  = "resolved"
+	ReportDismissed = "dismissed"
+)
+
+const (
+	reportCooldown     = 60 // seconds between reports from the same player
+	reportHourlyLimit  = 5  // reports per hour from the same address
+	reportReasonLength = 200
+)
+
+// ReportActions are what a moderator can do to a report and the status
+// each one moves it to
+var ReportActions = map[string]string{
+	"claim":   ReportClaimed,
+	"release": ReportOpen,
+	"resolve": ReportResolved,
+	"dismiss": ReportDismissed,
+}
+
+// the statuses a report can move to from each status
+var reportTransitions = map[string][]string{
+	ReportOpen:    {ReportClaimed, ReportResolved, ReportDismissed},
+	ReportClaimed: {ReportOpen, ReportResolved, ReportDismissed},
+}
+
+// ReportIdentity is who a player was when a report was made
+type ReportIdentity struct {
+	ClientID int    `json:"client"`
+	Name     string `json:"name"`
+	Profile  int64  `json:"profile"`
+	IP       string `json:"ip"`
+	Hostname string `json:"hostname"`
+	Cookie   string `json:"cookie"`
+	Version  string `json:"version"`
+}
+
+// ReportSnapshot is the state of the server when a report was made
+type ReportSnapshot struct {
+	Map      string         `json:"map"`
+	Reporter ReportIdentity `json:"reporter"`
+	Target   ReportIdentity `json:"target"`
+	Userinfo string         `json:"userinfo"` // the target's
+	Rules    []string       `json:"rules"`    // rules matching the target
+	Chat     []string       `json:"chat"`
+	Frags    []string       `json:"frags"`
+}
+
+// PlayerReport is one player reporting another
+type PlayerReport struct {
+	ID              int64
+	Server          string
+	Time            int64
+	Reporter        int64 // player table id for the reporter's sighting
+	ReporterProfile int64
+	ReporterName    string
+	ReporterIP      string
+	Target          int64 // player table id for the target's sighting
+	TargetProfile   int64
+	TargetName      string
+	Reason          string
+	Snapshot        ReportSnapshot
+	Status          string
+	Moderator       string // who claimed or closed it
+	Note            string // the moderator's
+	Updated         int64
+}
+
+// Closed reports have been resolved or dismissed
+func (r PlayerReport) Closed() bool {
+	return r.Status == ReportResolved || r.Status == ReportDismissed
+}
+
+func reportIdentity(p *frontend.Player) ReportIdentity {
+	return ReportIdentity{
+		ClientID: p.ClientID,
+		Name:     p.Name,
+		Profile:  p.ProfileID,
+		IP:       p.IP,
+		Hostname: p.Hostname,
+		Cookie:   p.Cookie,
+		Version:  p.Version,
+	}
+}
+
+// NewReport will build a report of target by reporter, including a snapshot
+// of the frontend they're both on.
+func NewReport(reporter, target *frontend.Player, reason string) *PlayerReport {
+	reason = strings.TrimSpace(reason)
+	if utf8.RuneCountInString(reason) > reportReasonLength {
+		n := 0
+		for i := range reason {
+			if n == reportReasonLength {
+				reason = reason[:i]
+				break
+			}
+			n++
+		}
+	}
+	r := &PlayerReport{
+		Time:            time.Now().Unix(),
+		Reporter:        reporter.Database_ID,
+		ReporterProfile: reporter.ProfileID,
+		ReporterName:    reporter.Name,
+		ReporterIP:      reporter.IP,
+		Target:          target.Database_ID,
+		TargetProfile:   target.ProfileID,
+		TargetName:      target.Name,
+		Reason:          reason,
+		Status:          ReportOpen,
+		Snapshot: ReportSnapshot{
+			Reporter: reportIdentity(reporter),
+			Target:   reportIdentity(target),
+			Userinfo: target.Userinfo,
+		},
+	}
+	for _, rule := range target.Rules {
+		desc := fmt.Sprintf("%s %s: %s", rule.GetUuid(), rule.GetType(), strings.Join(rule.GetDescription(), " "))
+		r.Snapshot.Rules = append(r.Snapshot.Rules, desc)
+	}
+	if fe := reporter.Frontend; fe != nil {
+		r.Server = fe.Name
+		r.Snapshot.Map = fe.CurrentMap
+		r.Snapshot.Chat = slices.Clone(fe.ChatHistory)
+		r.Snapshot.Frags = slices.Clone(fe.FragHistory)
+	}
+	return r
+}
+
+// reportAllowed checks if a player can make another report now. Players have
+// to wait a little between reports and each address only gets a few an hour
+// (so reconnecting doesn't help). The error is shown to the player.
+func reportAllowed(p *frontend.Player, now int64) error {
+	if wait := p.LastReport + reportCooldown - now; wait > 0 {
+		return fmt.Errorf("sent too recently, wait %d seconds", wait)
+	}
+	var count int
+	qry := "SELECT COUNT(*) FROM report WHERE reporter_ip = ? AND time > ?"
+	if err := db.Handle.QueryRow(qry, p.IP, now-3600).Scan(&count); err != nil {
+		be.Logf(LogLevelInfo, "error counting reports from %s: %v\n", p.IP, err)
+		return fmt.Errorf("try again later")
+	}
+	if count >= reportHourlyLimit {
+		return fmt.Errorf("too many reports, try again later")
+	}
+	return nil
+}
+
+// SaveReport will add a new report to the queue
+func SaveReport(r *PlayerReport) error {
+	snapshot, err := json.Marshal(r.Snapshot)
+	if err != nil {
+		return fmt.Errorf("error encoding report snapshot: %v", err)
+	}
+	r.Updated = r.Time
+	qry := `
+		INSERT INTO report (server, time, reporter, reporter_profile, reporter_name,
+			reporter_ip, target, target_profile, target_name, reason, snapshot,
+			status, moderator, note, updated)
+		VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`
+	res, err := db.Handle.Exec(qry, r.Server, r.Time, r.Reporter, r.ReporterProfile,
+		r.ReporterName, r.ReporterIP, r.Target, r.TargetProfile, r.TargetName,
+		r.Reason, string(snapshot), r.Status, r.Moderator, r.Note, r.Updated)
+	if err != nil {
+		return fmt.Errorf("error saving report of %s by %s: %v", r.TargetName, r.ReporterName, err)
+	}
+	r.ID, err = res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting id of report: %v", err)
+	}
+	return nil
+}
+
+const reportColumns = `
+	id, server, time, reporter, reporter_profile, reporter_name, reporter_ip,
+	target, target_profile, target_name, reason, snapshot, status, moderator,
+	note, updated`
+
+// rowScanner is either *sql.Row or *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanReport(row rowScanner) (PlayerReport, error) {
+	var r PlayerReport
+	var snapshot string
+	err := row.Scan(&r.ID, &r.Server, &r.Time, &r.Reporter, &r.ReporterProfile,
+		&r.ReporterName, &r.ReporterIP, &r.Target, &r.TargetProfile, &r.TargetName,
+		&r.Reason, &snapshot, &r.Status, &r.Moderator, &r.Note, &r.Updated)
+	if err != nil {
+		return r, err
+	}
+	if snapshot != "" {
+		if err := json.Unmarshal([]byte(snapshot), &r.Snapshot); err != nil {
+			return r, fmt.Errorf("error decoding snapshot of report %d: %v", r.ID, err)
+		}
+	}
+	return r, nil
+}
+
+// LoadReports will fetch the reports made on a frontend, newest first.
+// Closed reports are only included if all is set.
+func LoadReports(server string, all bool, limit int) ([]PlayerReport, error) {
+	var reports []PlayerReport
+	qry := "SELECT" + reportColumns + `
+		FROM report
+		WHERE server = ? AND (? OR status IN (?, ?))
+		ORDER BY time DESC, id DESC LIMIT ?`
+	rows, err := db.Handle.Query(qry, server, all, ReportOpen, ReportClaimed, limit)
+	if err != nil {
+		return reports, fmt.Errorf("error loading reports for %s: %v", server, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		r, err := scanReport(rows)
+		if err != nil {
+			return reports, fmt.Errorf("error scanning reports for %s: %v", server, err)
+		}
+		reports = append(reports, r)
+	}
+	return reports, nil
+}
+
+// LoadReport will fetch a single report
+func LoadReport(id int64) (*PlayerReport, error) {
+	qry := "SELECT" + reportColumns + " FROM report WHERE id = ?"
+	r, err := scanReport(db.Handle.QueryRow(qry, id))
+	if err != nil {
+		return nil, fmt.Errorf("error loading report %d: %v", id, err)
+	}
+	return &r, nil
+}
+
+// UpdateReport will move a report to a new status on behalf of a moderator.
+// Claiming an open report assigns it to them, setting it back to open
+// releases it. Closed reports can't be changed.
+func UpdateReport(id int64, status string, moderator string, note string) (*PlayerReport, error) {
+	r, err := LoadReport(id)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(reportTransitions[r.Status], status) {
+		return nil, fmt.Errorf("report %d is %s, it can't be %s", id, r.Status, status)
+	}
+	r.Status = status
+	r.Moderator = moderator
+	if status == ReportOpen {
+		r.Moderator = ""
+	}
+	if note != "" {
+		r.Note = note
+	}
+	r.Updated = time.Now().Unix()
+	qry := "UPDATE report SET status = ?, moderator = ?, note = ?, updated = ? WHERE id = ?"
+	if _, err := db.Handle.Exec(qry, r.Status, r.Moderator, r.Note, r.Updated, r.ID); err != nil {
+		return nil, fmt.Errorf("error updating report %d: %v", id, err)
+	}
+	return r, nil
+}
+
+// findTarget resolves the player another player's command is aimed at, by
+// client number or (part of) their name. If there isn't exactly one match the
+// player is told why and nil is returned.
+func findTarget(fe *frontend.Frontend, p *frontend.Player, who string) *frontend.Player {
+	found, err := fe.ResolvePlayers(who)
+	if err == nil && len(found) > 1 {
+		SayPlayer(fe, p, PRINT_HIGH, fmt.Sprintf("More than one player matches %q, use their number\n", who))
+		return nil
+	}
+	if err == nil && len(found) == 1 {
+		if target, err := fe.FindPlayer(found[0].ClientID); err == nil {
+			return target
+		}
+	}
+	SayPlayer(fe, p, PRINT_HIGH, fmt.Sprintf("No player matching %q\n", who))
+	return nil
+}
+
+// Report is called when a player issues the report command in-game. The
+// first argument is the client number or (part of) the name of the player
+// being reported, the rest is why. For example "report 3 wallhacking".
+func Report(fe *frontend.Frontend) {
+	if fe == nil {
+		return
+	}
+	client := (&fe.Message).ReadByte()
+	text := (&fe.Message).ReadString()
+	p, err := fe.FindPlayer(int(client))
+	if err != nil {
+		be.Logf(LogLevelInfo, "report error: %v\n", err)
+		return
+	}
+
+	who, reason, _ := strings.Cut(strings.TrimSpace(text), " ")
+	if who == "" || strings.TrimSpace(reason) == "" {
+		SayPlayer(fe, p, PRINT_HIGH, "Usage: report <player> <reason>\n")
+		return
+	}
+	target := findTarget(fe, p, who)
+	if target == nil {
+		return
+	}
+	if target.ClientID == p.ClientID {
+		SayPlayer(fe, p, PRINT_HIGH, "You can't report yourself\n")
+		return
+	}
+
+	now := time.Now().Unix()
+	if err := reportAllowed(p, now); err != nil {
+		SayPlayer(fe, p, PRINT_HIGH, fmt.Sprintf("Report not sent: %v\n", err))
+		return
+	}
+	r := NewReport(p, target, reason)
+	if err := SaveReport(r); err != nil {
+		be.Logln(LogLevelInfo, err)
+		SayPlayer(fe, p, PRINT_HIGH, "Unable to send report right now\n")
+		return
+	}
+	p.LastReport = now
+
+	msg := fmt.Sprintf("%-20s[%d] %-20q reported [%d] %q: %s (report #%d)", "REPORT:",
+		p.ClientID, p.Name, target.ClientID, target.Name, r.Reason, r.ID)
+	fe.Log.Printf("%s", msg)
+	fe.SSHPrintln(msg)
+	NotifyDiscord(fe, DiscordReport, target.Name, r.Reason)
+	SayPlayer(fe, p, PRINT_HIGH, fmt.Sprintf("Report #%d sent, thanks. A moderator will look into it.\n", r.ID))
+}
@@ -0,0 +1,168 @@
+package backend
+
+import (
+	"io"
+	"log"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/packetflinger/libq2/message"
+	"github.com/packetflinger/q2admind/frontend"
+
+	pb "github.com/packetflinger/q2admind/proto"
+)
+
+func testReport(t *testing.T) *PlayerReport {
+	t.Helper()
+	fe := &frontend.Frontend{
+		Name:        "test1",
+		CurrentMap:  "q2dm1",
+		ChatHistory: []string{"leon: lol", "claire: how did you see me?"},
+		FragHistory: []string{"leon > claire"},
+	}
+	reporter := &frontend.Player{ClientID: 0, Name: "claire", IP: "192.0.2.4", ProfileID: 1, Frontend: fe}
+	target := &frontend.Player{
+		ClientID:  1,
+		Name:      "leon",
+		IP:        "198.51.100.9",
+		ProfileID: 2,
+		Frontend:  fe,
+		Userinfo:  testUserinfo,
+		Rules:     []*pb.Rule{{Uuid: "abcd", Type: pb.RuleType_STIFLE, Description: []string{"spammer"}}},
+	}
+	return NewReport(reporter, target, "  wallhacking  ")
+}
+
+func TestNewReport(t *testing.T) {
+	r := testReport(t)
+	if r.Server != "test1" || r.Status != ReportOpen || r.Reason != "wallhacking" {
+		t.Errorf("NewReport() = %q/%q/%q, want test1/open/wallhacking", r.Server, r.Status, r.Reason)
+	}
+	if r.ReporterName != "claire" || r.TargetName != "leon" || r.TargetProfile != 2 {
+		t.Errorf("NewReport() reporter/target = %q/%q[%d]", r.ReporterName, r.TargetName, r.TargetProfile)
+	}
+	s := r.Snapshot
+	if s.Map != "q2dm1" || len(s.Chat) != 2 || len(s.Frags) != 1 || s.Userinfo != testUserinfo {
+		t.Errorf("NewReport() snapshot = %+v", s)
+	}
+	if len(s.Rules) != 1 || !strings.Contains(s.Rules[0], "spammer") {
+		t.Errorf("NewReport() rules = %q, want the target's stifle", s.Rules)
+	}
+
+	long := NewReport(&frontend.Player{}, &frontend.Player{}, strings.Repeat("x", 500))
+	if len(long.Reason) != reportReasonLength {
+		t.Errorf("NewReport() reason length = %d, want %d", len(long.Reason), reportReasonLength)
+	}
+	long = NewReport(&frontend.Player{}, &frontend.Player{}, strings.Repeat("ü", 500))
+	if !utf8.ValidString(long.Reason) || utf8.RuneCountInString(long.Reason) != reportReasonLength {
+		t.Errorf("NewReport() cut a multi-byte reason to %q", long.Reason)
+	}
+}
+
+func TestReportWorkflow(t *testing.T) {
+	useTestDatabase(t)
+	r := testReport(t)
+	if err := SaveReport(r); err != nil {
+		t.Fatal(err)
+	}
+	if r.ID == 0 {
+		t.Fatal("SaveReport() didn't set the id")
+	}
+	got, err := LoadReport(r.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.TargetName != "leon" || len(got.Snapshot.Chat) != 2 || got.Snapshot.Target.IP != "198.51.100.9" {
+		t.Errorf("LoadReport() = %+v, want what was saved", got)
+	}
+
+	steps := []struct {
+		status  string
+		wantErr bool
+	}{
+		{ReportClaimed, false},
+		{ReportOpen, false},
+		{ReportClaimed, false},
+		{ReportResolved, false},
+		{ReportDismissed, true},
+		{ReportOpen, true},
+	}
+	for _, step := range steps {
+		got, err := UpdateReport(r.ID, step.status, "mod@example.com", "")
+		if (err != nil) != step.wantErr {
+			t.Fatalf("UpdateReport(%s) error = %v, wantErr %t", step.status, err, step.wantErr)
+		}
+		if err == nil && got.Status != step.status {
+			t.Errorf("UpdateReport(%s) status = %s", step.status, got.Status)
+		}
+	}
+
+	open, err := LoadReports("test1", false, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(open) != 0 {
+		t.Errorf("LoadReports() open = %d, want 0", len(open))
+	}
+	all, err := LoadReports("test1", true, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 || all[0].Moderator != "mod@example.com" {
+		t.Errorf("LoadReports() all = %+v, want the resolved report", all)
+	}
+}
+
+func TestReportAllowed(t *testing.T) {
+	useTestDatabase(t)
+	now := time.Now().Unix()
+	p := &frontend.Player{IP: "192.0.2.4", LastReport: now - 10}
+	if err := reportAllowed(p, now); err == nil {
+		t.Error("reportAllowed() during cooldown = nil, want error")
+	}
+	p.LastReport = now - reportCooldown
+	for i := range reportHourlyLimit {
+		if err := reportAllowed(p, now); err != nil {
+			t.Fatalf("reportAllowed() report %d: %v", i, err)
+		}
+		r := testReport(t)
+		r.ReporterIP = p.IP
+		if err := SaveReport(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := reportAllowed(p, now); err == nil {
+		t.Error("reportAllowed() over the hourly limit = nil, want error")
+	}
+	other := &frontend.Player{IP: "198.51.100.9"}
+	if err := reportAllowed(other, now); err != nil {
+		t.Errorf("reportAllowed() other address: %v", err)
+	}
+}
+
+func TestReportSnapshotFrags(t *testing.T) {
+	fe := &frontend.Frontend{
+		Name:    "test1",
+		Log:     log.New(io.Discard, "", 0),
+		Players: []frontend.Player{{ClientID: 0, Name: "claire"}, {ClientID: 1, Name: "leon"}},
+	}
+	var out message.Buffer
+	for _, obit := range []string{"claire was railed by leon", "leon cratered"} {
+		out.WriteByte(CMDPrint)
+		out.WriteByte(PRINT_MEDIUM)
+		out.WriteString(obit + "\n")
+	}
+	fe.Message = message.NewBuffer(out.Data)
+	ParseMessage(fe)
+	for i := range fe.Players {
+		fe.Players[i].Frontend = fe
+	}
+
+	r := NewReport(&fe.Players[0], &fe.Players[1], "wallhacking")
+	want := []string{"leon > claire", "World/Self > leon"}
+	if strings.Join(r.Snapshot.Frags, ",") != strings.Join(want, ",") {
+		t.Errorf("report frags = %q, want %q", r.Snapshot.Frags, want)
+	}
+}
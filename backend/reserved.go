@@ -0,0 +1,269 @@
+// Owners can reserve names and clan tags on their frontends for particular
+// players, identified by their cookie, (cookie verified) profile or linked
+// website account. Anyone
+// else using one is warned and given a short grace period to change it or
+// prove who they are, then they're force-renamed or kicked. Someone who was
+// already renamed and goes back to a reserved name is kicked right away.
+//
+// Names match the whole player name, tags match anywhere in it unless they
+// use * wildcards. Both ignore case and Quake 2's high-bit (colored)
+// characters.
+package backend
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/packetflinger/q2admind/frontend"
+)
+
+// Kinds of reservations
+const (
+	ReservedName = "name"
+	ReservedTag  = "tag"
+)
+
+// What happens after the grace period
+const (
+	ReservedRename = "rename"
+	ReservedKick   = "kick"
+)
+
+const reservedGrace = 30 // seconds
+
+// Reservation is a name or tag a player can use on a frontend
+type Reservation struct {
+	ID      int64
+	Server  string // frontend name
+	Kind    string // name or tag
+	Pattern string
+	Cookie  string // the player's cookie...
+	Profile int64  // ...or their profile...
+	Account string // ...or their linked website account
+	Action  string // rename or kick
+	Owner   string // who reserved it
+	Created int64
+}
+
+// plainName strips the high bit Quake 2 uses for colored text and ignores
+// case. Unlike normalizeName punctuation is kept, it's often part of a tag.
+func plainName(name string) string {
+	b := []byte(name)
+	for i := range b {
+		b[i] &= 0x7f
+	}
+	return strings.ToLower(strings.TrimSpace(string(b)))
+}
+
+// Matches checks if a player name uses the reservation
+func (r Reservation) Matches(name string) bool {
+	name = plainName(name)
+	pattern := plainName(r.Pattern)
+	if pattern == "" {
+		return false
+	}
+	if r.Kind == ReservedName {
+		return name == pattern
+	}
+	if !strings.Contains(pattern, "*") {
+		return strings.Contains(name, pattern)
+	}
+	expr := strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+	return regexp.MustCompile("^" + expr + "$").MatchString(name)
+}
+
+// Allows checks if a player is who the reservation is for
+func (r Reservation) Allows(p *frontend.Player) bool {
+	if r.Cookie != "" && p.Cookie == r.Cookie {
+		return true
+	}
+	if r.Account != "" && strings.EqualFold(p.Account, r.Account) {
+		return true
+	}
+	return r.Profile != 0 && p.ProfileID == r.Profile && profileVerified(p)
+}
+
+// reservationViolated finds a reservation a player is using without being
+// allowed to. A name or tag reserved for several players (like a clan's tag)
+// can be used by any of them.
+func reservationViolated(p *frontend.Player, reservations []Reservation) *Reservation {
+	key := func(r Reservation) string {
+		return r.Kind + "/" + plainName(r.Pattern)
+	}
+	allowed := make(map[string]bool)
+	for _, r := range reservations {
+		if r.Matches(p.Name) && r.Allows(p) {
+			allowed[key(r)] = true
+		}
+	}
+	for i, r := range reservations {
+		if r.Matches(p.Name) && !allowed[key(r)] {
+			return &reservations[i]
+		}
+	}
+	return nil
+}
+
+// SetIdentity sets who a reservation is for, written as cookie:<value>,
+// profile:<id> or account:<email>
+func (r *Reservation) SetIdentity(in string) error {
+	kind, value, _ := strings.Cut(in, ":")
+	switch {
+	case kind == "cookie" && value != "":
+		r.Cookie = value
+		return nil
+	case kind == "account" && strings.Contains(value, "@"):
+		r.Account = strings.ToLower(value)
+		return nil
+	case kind == "profile":
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id <= 0 {
+			return fmt.Errorf("invalid profile %q", value)
+		}
+		r.Profile = id
+		return nil
+	}
+	return fmt.Errorf("identity %q isn't cookie:<value>, profile:<id> or account:<email>", in)
+}
+
+// SaveReservation adds a reserved name or tag
+func SaveReservation(r *Reservation) error {
+	if r.Kind != ReservedName && r.Kind != ReservedTag {
+		return fmt.Errorf("unknown reservation kind %q", r.Kind)
+	}
+	if r.Action != ReservedRename && r.Action != ReservedKick {
+		return fmt.Errorf("unknown reservation action %q", r.Action)
+	}
+	if plainName(r.Pattern) == "" {
+		return fmt.Errorf("empty reserved %s", r.Kind)
+	}
+	if r.Cookie == "" && r.Profile == 0 && r.Account == "" {
+		return fmt.Errorf("reserved %s %q isn't for anyone", r.Kind, r.Pattern)
+	}
+	if r.Created == 0 {
+		r.Created = time.Now().Unix()
+	}
+	qry := `
+		INSERT INTO reserved_name (server, kind, pattern, cookie, profile, account, action, owner, created)
+		VALUES (?,?,?,?,?,?,?,?,?)`
+	res, err := db.Handle.Exec(qry, r.Server, r.Kind, r.Pattern, r.Cookie, r.Profile, r.Account, r.Action, r.Owner, r.Created)
+	if err != nil {
+		return fmt.Errorf("error saving reserved %s: %v", r.Kind, err)
+	}
+	r.ID, err = res.LastInsertId()
+	return err
+}
+
+// LoadReservations fetches the reserved names and tags on a frontend
+func LoadReservations(server string) ([]Reservation, error) {
+	var reservations []Reservation
+	qry := `
+		SELECT id, server, kind, pattern, cookie, profile, account, action, owner, created
+		FROM reserved_name WHERE server = ? ORDER BY kind, pattern`
+	rows, err := db.Handle.Query(qry, server)
+	if err != nil {
+		return reservations, fmt.Errorf("error loading reserved names: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var r Reservation
+		err := rows.Scan(&r.ID, &r.Server, &r.Kind, &r.Pattern, &r.Cookie, &r.Profile, &r.Account, &r.Action, &r.Owner, &r.Created)
+		if err != nil {
+			return reservations, fmt.Errorf("error scanning reserved names: %v", err)
+		}
+		reservations = append(reservations, r)
+	}
+	return reservations, nil
+}
+
+// DeleteReservation removes a reserved name or tag from a frontend
+func DeleteReservation(server string, id int64) error {
+	res, err := db.Handle.Exec("DELETE FROM reserved_name WHERE server = ? AND id = ?", server, id)
+	if err != nil {
+		return fmt.Errorf("error removing reserved name: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("no reserved name %d on %s", id, server)
+	}
+	return nil
+}
+
+// CheckReservedName is run when a player connects, changes their name or
+// gets their cookie. Players using a name or tag reserved for someone else
+// are warned and enforcement is scheduled for the end of the grace period.
+//
+// Called from ParseConnect() and ParsePlayerUpdate()
+func CheckReservedName(p *frontend.Player) {
+	if p == nil || p.Frontend == nil {
+		return
+	}
+	fe := p.Frontend
+	reservations, err := LoadReservations(fe.Name)
+	if err != nil {
+		be.Logln(LogLevelInfo, err)
+		return
+	}
+	r := reservationViolated(p, reservations)
+	if r == nil {
+		p.NameDeadline = 0
+		return
+	}
+	if p.NameRenamed {
+		EnforceReservedName(p) // they were warned last time
+		return
+	}
+	if p.NameDeadline != 0 {
+		return // already counting down
+	}
+	deadline := time.Now().Unix() + reservedGrace
+	p.NameDeadline = deadline
+	SayPlayer(fe, p, PRINT_HIGH, fmt.Sprintf("The %s %q is reserved, change your name within %d seconds\n", r.Kind, r.Pattern, reservedGrace))
+	msg := fmt.Sprintf("%-20s[%d] %-20q using reserved %s %q", "RESERVED:", p.ClientID, p.Name, r.Kind, r.Pattern)
+	fe.Log.Printf("%s", msg)
+	fe.SSHPrintln(msg)
+
+	time.AfterFunc(reservedGrace*time.Second, func() {
+		cur, err := fe.FindPlayer(p.ClientID)
+		if err != nil || cur.ConnectTime != p.ConnectTime || cur.NameDeadline != deadline {
+			return // gone, or sorted out their name
+		}
+		if EnforceReservedName(cur) {
+			SendMessages(fe)
+		}
+	})
+}
+
+// EnforceReservedName renames or kicks a player still using a reserved name
+// or tag. Returns true if anything was done.
+func EnforceReservedName(p *frontend.Player) bool {
+	fe := p.Frontend
+	reservations, err := LoadReservations(fe.Name)
+	if err != nil {
+		be.Logln(LogLevelInfo, err)
+		return false
+	}
+	p.NameDeadline = 0
+	r := reservationViolated(p, reservations)
+	if r == nil {
+		return false
+	}
+	action := r.Action
+	if p.NameRenamed {
+		action = ReservedKick
+	}
+	switch action {
+	case ReservedKick:
+		KickPlayer(fe, p, fmt.Sprintf("The %s %q is reserved", r.Kind, r.Pattern))
+	default:
+		p.NameRenamed = true
+		StuffPlayer(fe, p, fmt.Sprintf("name \"player%d\"", p.ClientID))
+		SayPlayer(fe, p, PRINT_HIGH, fmt.Sprintf("You've been renamed, the %s %q is reserved\n", r.Kind, r.Pattern))
+	}
+	msg := fmt.Sprintf("%-20s[%d] %-20q %s for using reserved %s %q", "RESERVED:", p.ClientID, p.Name, action, r.Kind, r.Pattern)
+	fe.Log.Printf("%s", msg)
+	fe.SSHPrintln(msg)
+	return true
+}
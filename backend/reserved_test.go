@@ -0,0 +1,126 @@
+package backend
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"testing"
+
+	"github.com/packetflinger/q2admind/frontend"
+)
+
+func TestReservationMatches(t *testing.T) {
+	tests := []struct {
+		desc string
+		r    Reservation
+		name string
+		want bool
+	}{
+		{"name exact", Reservation{Kind: ReservedName, Pattern: "Claire"}, "claire", true},
+		{"name high bit", Reservation{Kind: ReservedName, Pattern: "claire"}, string([]byte{'c' | 0x80, 'l', 'a', 'i', 'r', 'e'}), true},
+		{"name is whole", Reservation{Kind: ReservedName, Pattern: "claire"}, "claire2", false},
+		{"tag anywhere", Reservation{Kind: ReservedTag, Pattern: "[NJ]"}, "leon[nj]", true},
+		{"tag wildcard", Reservation{Kind: ReservedTag, Pattern: "[NJ]*"}, "leon[nj]", false},
+		{"tag wildcard prefix", Reservation{Kind: ReservedTag, Pattern: "[NJ]*"}, "[nj]leon", true},
+		{"empty", Reservation{Kind: ReservedTag, Pattern: " "}, "leon", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := tc.r.Matches(tc.name); got != tc.want {
+				t.Errorf("Matches(%q) = %t, want %t", tc.name, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestReservationViolated(t *testing.T) {
+	reservations := []Reservation{
+		{ID: 1, Kind: ReservedTag, Pattern: "[NJ]", Cookie: "aaaa"},
+		{ID: 2, Kind: ReservedTag, Pattern: "[nj]", Profile: 7},
+		{ID: 3, Kind: ReservedName, Pattern: "claire", Cookie: "aaaa"},
+		{ID: 4, Kind: ReservedName, Pattern: "leon", Account: "leon@example.com"},
+	}
+	tests := []struct {
+		desc string
+		p    *frontend.Player
+		want int64 // id of the violated reservation, 0 for none
+	}{
+		{"unreserved", &frontend.Player{Name: "ada"}, 0},
+		{"impostor", &frontend.Player{Name: "[NJ]leon", Cookie: "bbbb"}, 1},
+		{"by cookie", &frontend.Player{Name: "[NJ]leon", Cookie: "aaaa"}, 0},
+		{"by verified profile", &frontend.Player{Name: "[NJ]leon", Cookie: "cccc", ProfileID: 7, ProfileScore: 1}, 0},
+		{"unverified profile", &frontend.Player{Name: "[NJ]leon", ProfileID: 7, ProfileScore: 0.6}, 1},
+		{"tag ok, name not", &frontend.Player{Name: "claire", Cookie: "cccc", ProfileID: 7, ProfileScore: 1}, 3},
+		{"by account", &frontend.Player{Name: "Leon", Account: "Leon@example.com"}, 0},
+		{"other account", &frontend.Player{Name: "leon", Account: "ada@example.com"}, 4},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := reservationViolated(tc.p, reservations)
+			if (got == nil && tc.want != 0) || (got != nil && got.ID != tc.want) {
+				t.Errorf("reservationViolated() = %+v, want reservation %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestReservationSetIdentity(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Reservation
+	}{
+		{"cookie:abcd", Reservation{Cookie: "abcd"}},
+		{"profile:12", Reservation{Profile: 12}},
+		{"account:Claire@Example.com", Reservation{Account: "claire@example.com"}},
+	}
+	for _, tc := range tests {
+		var r Reservation
+		if err := r.SetIdentity(tc.in); err != nil || r != tc.want {
+			t.Errorf("SetIdentity(%q) = %+v, %v, want %+v", tc.in, r, err, tc.want)
+		}
+	}
+	for _, bad := range []string{"cookie:", "profile:x", "profile:-1", "account:claire", "abcd"} {
+		var r Reservation
+		if err := r.SetIdentity(bad); err == nil {
+			t.Errorf("SetIdentity(%q) = nil, want error", bad)
+		}
+	}
+}
+
+func TestEnforceReservedName(t *testing.T) {
+	useTestDatabase(t)
+	fe := &frontend.Frontend{Name: "test1", Log: log.New(io.Discard, "", 0)}
+	r := Reservation{Server: "test1", Kind: ReservedName, Pattern: "claire", Cookie: "aaaa", Action: ReservedRename}
+	if err := SaveReservation(&r); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveReservation(&Reservation{Server: "test1", Kind: ReservedName, Pattern: "leon", Action: ReservedKick}); err == nil {
+		t.Error("SaveReservation() for nobody = nil, want error")
+	}
+
+	p := &frontend.Player{ClientID: 3, Name: "claire", Cookie: "bbbb", Frontend: fe}
+	if !EnforceReservedName(p) || !p.NameRenamed {
+		t.Fatal("EnforceReservedName() didn't rename the impostor")
+	}
+	if !bytes.Contains(fe.MessageOut.Data, []byte(`name "player3"`)) {
+		t.Errorf("EnforceReservedName() sent %q, want a forced rename", fe.MessageOut.Data)
+	}
+
+	// back to the reserved name after being renamed
+	fe.MessageOut.Reset()
+	if !EnforceReservedName(p) || !bytes.Contains(fe.MessageOut.Data, []byte("kick 3")) {
+		t.Errorf("EnforceReservedName() second time sent %q, want a kick", fe.MessageOut.Data)
+	}
+
+	owner := &frontend.Player{ClientID: 4, Name: "claire", Cookie: "aaaa", Frontend: fe}
+	if EnforceReservedName(owner) {
+		t.Error("EnforceReservedName() acted on who the name is reserved for")
+	}
+
+	if err := DeleteReservation("test1", r.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := LoadReservations("test1"); len(got) != 0 {
+		t.Errorf("LoadReservations() after delete = %+v", got)
+	}
+}
@@ -0,0 +1,249 @@
+// Each frontend can have any number of named map rotations ("small",
+// "ctf night", etc), one of them active. The active rotation decides what's
+// played next when a match ends, and where it's at in the list is saved so
+// it carries on after a restart.
+//
+// Maps are written one per line the way maprotator reads them:
+//
+//	name [minimum players] [maximum players] [dmflags]
+package backend
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/packetflinger/q2admind/frontend"
+	"github.com/packetflinger/q2admind/maprotator"
+
+	pb "github.com/packetflinger/q2admind/proto"
+)
+
+var rotationName = regexp.MustCompile(`^[A-Za-z0-9_\-]+$`)
+
+// Rotation is a frontend's named list of maps
+type Rotation struct {
+	ID         int64
+	Server     string
+	Name       string
+	Maps       []string
+	Reverse    bool
+	Active     bool
+	Position   int32 // index of the map last played
+	Iterations int32
+	Updated    int64
+}
+
+// ParseRotationMaps reads a list of maps, one per line or separated by
+// commas
+func ParseRotationMaps(in string) ([]string, error) {
+	var maps []string
+	for _, line := range strings.FieldsFunc(in, func(r rune) bool { return r == '\n' || r == ',' }) {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) > 4 || !adminMapName.MatchString(fields[0]) {
+			return nil, fmt.Errorf("invalid map %q, want: name [min] [max] [dmflags]", strings.TrimSpace(line))
+		}
+		for _, f := range fields[1:] {
+			if n, err := strconv.Atoi(f); err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid map %q, want: name [min] [max] [dmflags]", strings.TrimSpace(line))
+			}
+		}
+		maps = append(maps, strings.Join(fields, " "))
+	}
+	if len(maps) == 0 {
+		return nil, fmt.Errorf("no maps")
+	}
+	return maps, nil
+}
+
+// MapList builds the rotator for a rotation, picking up where it left off
+func (r *Rotation) MapList() *maprotator.MapList {
+	list := maprotator.NewMapRotation(r.Name, r.Maps)
+	if list == nil {
+		return nil
+	}
+	if r.Reverse {
+		list.Direction = pb.MapRotationDirection_MapRotationDirectionReverse
+	}
+	if r.Position >= 0 && r.Position < list.GetSize() {
+		list.Index = r.Position
+	}
+	list.Iterations = r.Iterations
+	return list
+}
+
+// Current checks if map i is the one last played by the active rotation
+func (r Rotation) Current(i int) bool {
+	return r.Active && int(r.Position) == i
+}
+
+// MoveMap moves map number from (1-based) to number to
+func (r *Rotation) MoveMap(from int, to int) error {
+	if from < 1 || from > len(r.Maps) || to < 1 || to > len(r.Maps) {
+		return fmt.Errorf("maps are numbered 1-%d", len(r.Maps))
+	}
+	m := r.Maps[from-1]
+	r.Maps = append(r.Maps[:from-1], r.Maps[from:]...)
+	r.Maps = append(r.Maps[:to-1], append([]string{m}, r.Maps[to-1:]...)...)
+	return nil
+}
+
+// Shuffle puts the maps in a random order
+func (r *Rotation) Shuffle() {
+	list := r.MapList()
+	if list == nil {
+		return
+	}
+	*list = list.Shuffle()
+	r.Maps = list.Lines()
+}
+
+func scanRotation(row interface{ Scan(...any) error }) (*Rotation, error) {
+	var r Rotation
+	var maps string
+	err := row.Scan(&r.ID, &r.Server, &r.Name, &maps, &r.Reverse, &r.Active, &r.Position, &r.Iterations, &r.Updated)
+	if err != nil {
+		return nil, err
+	}
+	if maps != "" {
+		r.Maps = strings.Split(maps, "\n")
+	}
+	return &r, nil
+}
+
+// LoadRotations fetches a frontend's rotations by name
+func LoadRotations(server string) ([]Rotation, error) {
+	var rotations []Rotation
+	qry := `
+		SELECT id, server, name, maps, reverse, active, position, iterations, updated
+		FROM map_rotation WHERE server = ? ORDER BY name`
+	rows, err := db.Handle.Query(qry, server)
+	if err != nil {
+		return rotations, fmt.Errorf("error loading map rotations: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		r, err := scanRotation(rows)
+		if err != nil {
+			return rotations, fmt.Errorf("error scanning map rotations: %v", err)
+		}
+		rotations = append(rotations, *r)
+	}
+	return rotations, nil
+}
+
+// LoadRotation fetches one of a frontend's rotations
+func LoadRotation(server string, name string) (*Rotation, error) {
+	qry := `
+		SELECT id, server, name, maps, reverse, active, position, iterations, updated
+		FROM map_rotation WHERE server = ? AND name = ?`
+	r, err := scanRotation(db.Handle.QueryRow(qry, server, name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("no rotation %q", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading rotation %q: %v", name, err)
+	}
+	return r, nil
+}
+
+// SaveRotation adds a rotation or updates its maps and direction. If it's
+// the frontend's active rotation, the change takes effect right away.
+func SaveRotation(fe *frontend.Frontend, r *Rotation) error {
+	if !rotationName.MatchString(r.Name) {
+		return fmt.Errorf("invalid rotation name %q, use letters, numbers, - and _", r.Name)
+	}
+	if len(r.Maps) == 0 {
+		return fmt.Errorf("rotation %q has no maps", r.Name)
+	}
+	if int(r.Position) >= len(r.Maps) {
+		r.Position = 0
+	}
+	r.Server = fe.Name
+	r.Updated = time.Now().Unix()
+	qry := `
+		INSERT INTO map_rotation (server, name, maps, reverse, position, updated)
+		VALUES (?,?,?,?,?,?)
+		ON CONFLICT(server, name) DO UPDATE SET
+			maps = excluded.maps, reverse = excluded.reverse,
+			position = excluded.position, updated = excluded.updated`
+	_, err := db.Handle.Exec(qry, r.Server, r.Name, strings.Join(r.Maps, "\n"), r.Reverse, r.Position, r.Updated)
+	if err != nil {
+		return fmt.Errorf("error saving rotation %q: %v", r.Name, err)
+	}
+	if fe.Maplist != nil && fe.Maplist.GetName() == r.Name {
+		saved, err := LoadRotation(fe.Name, r.Name)
+		if err != nil {
+			return err
+		}
+		fe.Maplist = saved.MapList()
+	}
+	return nil
+}
+
+// DeleteRotation removes a rotation. If it was active the frontend stops
+// rotating maps.
+func DeleteRotation(fe *frontend.Frontend, name string) error {
+	res, err := db.Handle.Exec("DELETE FROM map_rotation WHERE server = ? AND name = ?", fe.Name, name)
+	if err != nil {
+		return fmt.Errorf("error removing rotation %q: %v", name, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("no rotation %q", name)
+	}
+	if fe.Maplist != nil && fe.Maplist.GetName() == name {
+		fe.Maplist = nil
+	}
+	return nil
+}
+
+// ActivateRotation makes a rotation the one a frontend uses from the next
+// map change on
+func ActivateRotation(fe *frontend.Frontend, name string) error {
+	r, err := LoadRotation(fe.Name, name)
+	if err != nil {
+		return err
+	}
+	_, err = db.Handle.Exec("UPDATE map_rotation SET active = (id = ?) WHERE server = ?", r.ID, fe.Name)
+	if err != nil {
+		return fmt.Errorf("error activating rotation %q: %v", name, err)
+	}
+	r.Active = true
+	fe.Maplist = r.MapList()
+	return nil
+}
+
+// LoadActiveRotation fetches the rotation a frontend is using, nil if none
+func LoadActiveRotation(server string) (*maprotator.MapList, error) {
+	qry := `
+		SELECT id, server, name, maps, reverse, active, position, iterations, updated
+		FROM map_rotation WHERE server = ? AND active = 1`
+	r, err := scanRotation(db.Handle.QueryRow(qry, server))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading active rotation for %q: %v", server, err)
+	}
+	return r.MapList(), nil
+}
+
+// SaveRotationPosition remembers where the active rotation is at
+func SaveRotationPosition(fe *frontend.Frontend) error {
+	if fe.Maplist == nil {
+		return nil
+	}
+	qry := "UPDATE map_rotation SET position = ?, iterations = ? WHERE server = ? AND name = ?"
+	_, err := db.Handle.Exec(qry, fe.Maplist.GetIndex(), fe.Maplist.GetIterations(), fe.Name, fe.Maplist.GetName())
+	if err != nil {
+		return fmt.Errorf("error saving rotation position for %q: %v", fe.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package backend
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/packetflinger/q2admind/frontend"
+)
+
+func TestParseRotationMaps(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{name: "lines", in: "q2dm1 0 8\n  q2dm2\n\nq2dm8   2 0 1040\n", want: []string{"q2dm1 0 8", "q2dm2", "q2dm8 2 0 1040"}},
+		{name: "commas", in: "q2dm1,q2dm2 4", want: []string{"q2dm1", "q2dm2 4"}},
+		{name: "empty", in: " \n ", wantErr: true},
+		{name: "bad name", in: "q2dm1\n../q2dm2", wantErr: true},
+		{name: "bad number", in: "q2dm1 a", wantErr: true},
+		{name: "too many", in: "q2dm1 0 8 0 1", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseRotationMaps(tc.in)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseRotationMaps(%q) error = %v, wantErr %v", tc.in, err, tc.wantErr)
+			}
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("ParseRotationMaps(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMoveMap(t *testing.T) {
+	r := Rotation{Maps: []string{"a", "b", "c", "d"}}
+	if err := r.MoveMap(4, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.MoveMap(2, 3); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(r.Maps, ","); got != "d,b,a,c" {
+		t.Errorf("MoveMap() = %q, want d,b,a,c", got)
+	}
+	for _, bad := range [][2]int{{0, 1}, {1, 5}} {
+		if err := r.MoveMap(bad[0], bad[1]); err == nil {
+			t.Errorf("MoveMap(%d, %d) = nil, want error", bad[0], bad[1])
+		}
+	}
+}
+
+func TestRotations(t *testing.T) {
+	useTestDatabase(t)
+	fe := &frontend.Frontend{Name: "test1"}
+	for _, r := range []*Rotation{
+		{Name: "small", Maps: []string{"q2dm1 0 8", "q2dm2 0 8"}},
+		{Name: "big", Maps: []string{"q2dm8 8", "q2dm3 8", "q2dm5 8"}, Reverse: true},
+	} {
+		if err := SaveRotation(fe, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := SaveRotation(fe, &Rotation{Name: "ctf night", Maps: []string{"q2ctf1"}}); err == nil {
+		t.Error("SaveRotation() with a space in the name = nil, want error")
+	}
+
+	if err := ActivateRotation(fe, "big"); err != nil {
+		t.Fatal(err)
+	}
+	if fe.Maplist.GetName() != "big" || fe.Maplist.GetSize() != 3 {
+		t.Fatalf("fe.Maplist after activate = %v", fe.Maplist)
+	}
+	fe.Maplist.Next(10)
+	if err := SaveRotationPosition(fe); err != nil {
+		t.Fatal(err)
+	}
+	list, err := LoadActiveRotation("test1")
+	if err != nil || list.GetName() != "big" || list.GetIndex() != fe.Maplist.GetIndex() {
+		t.Errorf("LoadActiveRotation() = %v, %v", list, err)
+	}
+
+	// editing the active rotation takes effect right away
+	if err := SaveRotation(fe, &Rotation{Name: "big", Maps: []string{"q2dm8 8"}}); err != nil {
+		t.Fatal(err)
+	}
+	if fe.Maplist.GetSize() != 1 {
+		t.Errorf("fe.Maplist after edit has %d maps, want 1", fe.Maplist.GetSize())
+	}
+
+	if err := ActivateRotation(fe, "small"); err != nil {
+		t.Fatal(err)
+	}
+	rotations, err := LoadRotations("test1")
+	if err != nil || len(rotations) != 2 || rotations[0].Name != "big" || rotations[0].Active || !rotations[1].Active {
+		t.Errorf("LoadRotations() = %+v, %v", rotations, err)
+	}
+
+	if err := DeleteRotation(fe, "small"); err != nil {
+		t.Fatal(err)
+	}
+	if fe.Maplist != nil {
+		t.Errorf("fe.Maplist after deleting the active rotation = %v", fe.Maplist)
+	}
+	if list, _ := LoadActiveRotation("test1"); list != nil {
+		t.Errorf("LoadActiveRotation() after delete = %v", list)
+	}
+	if err := DeleteRotation(fe, "small"); err == nil {
+		t.Error("DeleteRotation() twice = nil, want error")
+	}
+}
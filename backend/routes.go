@@ -15,9 +15,23 @@ type WebRoutes struct {
 	AuthDiscord      string
 	ConnectedServers string
 	Dashboard        string
+	LinkCode         string
+	Unlink           string
 	Index            string
 	Groups           string
+	Leaderboard      string
+	MatchList        string
+	MatchView        string
+	MatchExport      string
+	MapStats         string
 	Privacy          string
+	ReportList       string
+	ReportView       string
+	ReportUpdate     string
+	RotationList     string
+	RotationUpdate   string
+	ScheduleList     string
+	ScheduleUpdate   string
 	ServerAdd        string
 	ServerRemove     string
 	Servers          string
@@ -26,10 +40,16 @@ type WebRoutes struct {
 	ServerChangeUUID string
 	Terms            string
 	PlayerView       string
+	PlayerBan        string
+	PlayerNote       string
+	NoteDelete       string
 	ServerConsole    string
+	ServerConsoleSay string
 	Search           string
 	SearchServer     string
 	PlayerSearchView string
+	ProfileView      string
+	ProfileMerge     string
 	RuleList         string
 	RuleView         string
 	RuleEdit         string
@@ -54,10 +74,25 @@ func LoadWebsiteRoutes() *mux.Router {
 	apiRoute.APIKeyList = "/api/v1/ListAPIKeys/{UUID}/key/{APIKEY}"
 
 	Routes.PlayerView = "/sv/{ServerUUID}/{ServerName}/player/{ClientNum}"
+	Routes.PlayerBan = "/sv/{ServerUUID}/{ServerName}/player/{ClientNum}/ban"
+	Routes.PlayerNote = "/sv/{ServerUUID}/{ServerName}/player/{ClientNum}/note"
+	Routes.NoteDelete = "/note/{NoteID}/delete"
 	Routes.RuleList = "/sv/{ServerUUID}/{ServerName}/rules"
 	Routes.ServerKeys = "/sv/{ServerUUID}/{ServerName}/manage-keys"
+	Routes.MatchList = "/sv/{ServerUUID}/{ServerName}/matches"
+	Routes.MatchView = "/sv/{ServerUUID}/{ServerName}/match/{MatchID}"
+	Routes.MatchExport = "/sv/{ServerUUID}/{ServerName}/match/{MatchID}/json"
+	Routes.MapStats = "/sv/{ServerUUID}/{ServerName}/maps"
+	Routes.ReportList = "/sv/{ServerUUID}/{ServerName}/reports"
+	Routes.ReportView = "/sv/{ServerUUID}/{ServerName}/report/{ReportID}"
+	Routes.ReportUpdate = "/sv/{ServerUUID}/{ServerName}/report/{ReportID}/update"
+	Routes.RotationList = "/sv/{ServerUUID}/{ServerName}/rotations"
+	Routes.RotationUpdate = "/sv/{ServerUUID}/{ServerName}/rotations/update"
+	Routes.ScheduleList = "/sv/{ServerUUID}/{ServerName}/schedule"
+	Routes.ScheduleUpdate = "/sv/{ServerUUID}/{ServerName}/schedule/update"
 	Routes.ServerEdit = "/sv/{ServerUUID}/{ServerName}/edit"
 	Routes.ServerConsole = "/sv/{ServerUUID}/{ServerName}/console"
+	Routes.ServerConsoleSay = "/sv/{ServerUUID}/{ServerName}/console/say"
 	Routes.ServerChangeUUID = "/sv/{ServerUUID}/{ServerName}/change-uuid"
 	Routes.ServerView = "/sv/{ServerUUID}/{ServerName}"
 	Routes.ServerRemove = "/sv/{ServerUUID}/{ServerName}/delete"
@@ -67,10 +102,15 @@ func LoadWebsiteRoutes() *mux.Router {
 	Routes.AuthDiscord = "/auth/discord"
 	Routes.AuthGoogle = "/auth/google"
 	Routes.Dashboard = "/dashboard"
+	Routes.LinkCode = "/link-code"
+	Routes.Unlink = "/unlink/{LinkID}"
 	Routes.Groups = "/my-groups"
+	Routes.Leaderboard = "/leaderboard"
 	Routes.Servers = "/my-servers"
 	Routes.PlayerSearchView = "/player/{lookup}"
 	Routes.Privacy = "/privacy-policy"
+	Routes.ProfileView = "/profile/{ProfileID}"
+	Routes.ProfileMerge = "/profile/{ProfileID}/merge"
 	Routes.RuleView = "/rules/{uuid}/view"
 	Routes.RuleEdit = "/rules/{uuid}/edit"
 	Routes.RuleAdd = "/rules/create"
@@ -92,25 +132,45 @@ func LoadWebsiteRoutes() *mux.Router {
 	r.HandleFunc(Routes.AuthDiscord, ProcessDiscordLogin)
 	r.HandleFunc(Routes.AuthGoogle, ProcessGoogleLogin)
 	r.HandleFunc(Routes.Dashboard, WebsiteHandlerDashboard)
+	r.HandleFunc(Routes.LinkCode, LinkCodeHandler).Methods("POST")
+	r.HandleFunc(Routes.Unlink, UnlinkHandler).Methods("POST")
 	r.HandleFunc(Routes.ServerRemove, WebDelServer)
 	r.HandleFunc(Routes.ServerView, WebsiteHandlerServerView)
 	r.HandleFunc(Routes.ConnectedServers, WebsiteAPIGetConnectedServers)
 	r.HandleFunc(Routes.Groups, GroupsHandler)
+	r.HandleFunc(Routes.Leaderboard, LeaderboardHandler)
 	r.HandleFunc(Routes.Privacy, PrivacyHandler)
 	r.HandleFunc(Routes.Servers, ServersHandler)
 	r.HandleFunc(Routes.Terms, TermsHandler)
 	r.HandleFunc(Routes.ServerEdit, WebEditServer).Methods("POST")
 	r.HandleFunc(Routes.ServerChangeUUID, ChangeUUIDHandler)
 	r.HandleFunc(Routes.PlayerView, PlayerViewHandler)
+	r.HandleFunc(Routes.PlayerBan, PlayerBanHandler).Methods("POST")
+	r.HandleFunc(Routes.PlayerNote, PlayerNoteHandler).Methods("POST")
+	r.HandleFunc(Routes.NoteDelete, NoteDeleteHandler).Methods("POST")
 	r.HandleFunc(Routes.ServerConsole, ServerConsoleHandler)
+	r.HandleFunc(Routes.ServerConsoleSay, ServerConsoleSayHandler).Methods("POST")
 	r.HandleFunc(Routes.Search, SearchHandler)
 	r.HandleFunc(Routes.SearchServer, SearchHandler)
 	r.HandleFunc(Routes.PlayerSearchView, PlayerSearchViewHandler)
+	r.HandleFunc(Routes.ProfileView, ProfileViewHandler)
+	r.HandleFunc(Routes.ProfileMerge, ProfileMergeHandler).Methods("POST")
 	r.HandleFunc(Routes.RuleView, RuleViewHandler)
 	r.HandleFunc(Routes.RuleEdit, RuleEditHandler)
 	r.HandleFunc(Routes.RuleList, RuleListHandler)
 	r.HandleFunc(Routes.RuleAdd, RuleAddHandler)
 	r.HandleFunc(Routes.ServerKeys, ServerKeysHandler)
+	r.HandleFunc(Routes.MatchList, MatchListHandler)
+	r.HandleFunc(Routes.MatchView, MatchViewHandler)
+	r.HandleFunc(Routes.MatchExport, MatchExportHandler)
+	r.HandleFunc(Routes.MapStats, MapStatsHandler)
+	r.HandleFunc(Routes.ReportList, ReportListHandler)
+	r.HandleFunc(Routes.ReportView, ReportViewHandler)
+	r.HandleFunc(Routes.ReportUpdate, ReportUpdateHandler).Methods("POST")
+	r.HandleFunc(Routes.RotationList, RotationListHandler)
+	r.HandleFunc(Routes.RotationUpdate, RotationUpdateHandler).Methods("POST")
+	r.HandleFunc(Routes.ScheduleList, ScheduleListHandler)
+	r.HandleFunc(Routes.ScheduleUpdate, ScheduleUpdateHandler).Methods("POST")
 
 	r.PathPrefix(Routes.Static).Handler(http.FileServer(http.Dir("./api/website")))
 	r.PathPrefix(Routes.Static2).Handler(http.FileServer(http.Dir("./api/website")))
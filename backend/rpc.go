@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/packetflinger/q2admind/crypto"
+	"github.com/packetflinger/q2admind/frontend"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
@@ -237,3 +238,255 @@ func (s *RPCServer) FetchStatus(ctx context.Context, req *pb.StatusRequest) (*pb
 	}
 	return &pb.StatusResponse{Uuid: fe.UUID}, nil
 }
+
+// FetchLeaderboard returns the top players for a server (or all of them) for
+// the requested period and weapon.
+func (s *RPCServer) FetchLeaderboard(ctx context.Context, req *pb.LeaderboardRequest) (*pb.LeaderboardResponse, error) {
+	valid, ident, err := checkRPCAuthorization(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, fmt.Errorf("unauthorized")
+	}
+	be.Logf(LogLevelInfo, "FetchLeaderboard called for %q", ident)
+	if name := req.GetServer(); name != "" {
+		if _, err := be.FindFrontendByName(name); err != nil {
+			return nil, fmt.Errorf("frontend not found")
+		}
+	}
+	lb, err := LoadLeaderboard(req.GetServer(), req.GetPeriod(), req.GetWeapon(), int(req.GetLimit()))
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.LeaderboardResponse{
+		Server: lb.Server,
+		Period: lb.Period,
+		Weapon: lb.Weapon,
+	}
+	for _, e := range lb.Entries {
+		resp.Entry = append(resp.Entry, &pb.LeaderboardEntry{
+			Rank:     int32(e.Rank),
+			Profile:  e.Profile,
+			Name:     e.Name,
+			Frags:    int32(e.Frags),
+			Deaths:   int32(e.Deaths),
+			Suicides: int32(e.Suicides),
+			Kdr:      float32(e.KDR),
+			PlayTime: e.PlayTime,
+		})
+	}
+	return resp, nil
+}
+
+// ExportPlayerData returns everything stored about a player as JSON. Only
+// backend admins can ask.
+func (s *RPCServer) ExportPlayerData(ctx context.Context, req *pb.PlayerDataRequest) (*pb.PlayerDataExport, error) {
+	valid, ident, err := checkRPCAuthorization(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !valid || !BackendAdmin(ident) {
+		return nil, fmt.Errorf("unauthorized")
+	}
+	be.Logf(LogLevelInfo, "ExportPlayerData called for %q", ident)
+	out, err := ExportPlayerData(req.GetIdentity(), "rpc:"+ident)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.PlayerDataExport{Identity: req.GetIdentity(), Json: string(out)}, nil
+}
+
+// ErasePlayerData removes or anonymizes everything stored about a player,
+// found by their profile or cookie. Only backend admins can ask.
+func (s *RPCServer) ErasePlayerData(ctx context.Context, req *pb.PlayerDataRequest) (*pb.PlayerDataErased, error) {
+	valid, ident, err := checkRPCAuthorization(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !valid || !BackendAdmin(ident) {
+		return nil, fmt.Errorf("unauthorized")
+	}
+	be.Logf(LogLevelInfo, "ErasePlayerData called for %q", ident)
+	rows, err := ErasePlayerData(req.GetIdentity(), req.GetAnonymize(), "rpc:"+ident)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.PlayerDataErased{Identity: req.GetIdentity(), Rows: rows}, nil
+}
+
+// FetchMapStats ranks the maps played on a server. Only the owner, users
+// with access to the server and backend admins can ask.
+func (s *RPCServer) FetchMapStats(ctx context.Context, req *pb.MapStatsRequest) (*pb.MapStatsResponse, error) {
+	valid, ident, err := checkRPCAuthorization(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, fmt.Errorf("unauthorized")
+	}
+	be.Logf(LogLevelInfo, "FetchMapStats called for %q", ident)
+	fe, err := be.FindFrontendByName(req.GetServer())
+	if err != nil {
+		return nil, fmt.Errorf("frontend not found")
+	}
+	_, user := fe.WebUsers[ident]
+	if !strings.EqualFold(fe.Owner, ident) && !user && !BackendAdmin(ident) {
+		return nil, fmt.Errorf("unauthorized")
+	}
+	stats, err := LoadMapStats(fe, int(req.GetDays()))
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.MapStatsResponse{Server: fe.Name, Days: req.GetDays()}
+	for i, m := range stats {
+		resp.Entry = append(resp.Entry, &pb.MapStatsEntry{
+			Rank:       int32(i + 1),
+			Map:        m.Map,
+			Plays:      int32(m.Plays),
+			PlayTime:   m.PlayTime,
+			AvgPlayers: float32(m.AvgPlayers),
+			Peak:       int32(m.Peak),
+			Joined:     int32(m.Joined),
+			Left:       int32(m.Left),
+			Offered:    int32(m.Offered),
+			Votes:      int32(m.Votes),
+			Won:        int32(m.Won),
+			Skipped:    int32(m.Skipped),
+			InRotation: m.InRotation,
+		})
+	}
+	return resp, nil
+}
+
+// jobEntry is how a scheduled job is sent over RPC, with its recent runs
+func jobEntry(j *Job) *pb.JobEntry {
+	e := &pb.JobEntry{
+		Name:       j.Name,
+		Target:     j.Target(),
+		Spec:       j.Spec,
+		Timezone:   j.Timezone,
+		Action:     j.Action,
+		Args:       j.Args,
+		Enabled:    j.Enabled,
+		Author:     j.Author,
+		Created:    j.Created,
+		LastRun:    j.LastRun,
+		LastStatus: j.LastStatus,
+		NextRun:    j.NextRun(),
+	}
+	runs, err := LoadJobRuns(j.ID, jobRunHistory)
+	if err != nil {
+		be.Logln(LogLevelInfo, err)
+	}
+	for _, r := range runs {
+		e.Run = append(e.Run, &pb.JobRunEntry{Server: r.Server, Time: r.Time, Success: r.Success, Output: r.Output})
+	}
+	return e
+}
+
+// rpcJobFrontend finds the server a job request is for and checks the
+// caller can see its jobs, or change them
+func rpcJobFrontend(ctx context.Context, server string, manage bool) (*frontend.Frontend, string, error) {
+	valid, ident, err := checkRPCAuthorization(ctx)
+	if err != nil {
+		return nil, ident, err
+	}
+	if !valid {
+		return nil, ident, fmt.Errorf("unauthorized")
+	}
+	fe, err := be.FindFrontendByName(server)
+	if err != nil {
+		return nil, ident, fmt.Errorf("frontend not found")
+	}
+	if (manage && !jobManager(fe, ident)) || !jobAccess(fe, ident) {
+		return nil, ident, fmt.Errorf("unauthorized")
+	}
+	return fe, ident, nil
+}
+
+// ListJobs returns the jobs scheduled on a server. Only the owner, users
+// with access to the server and backend admins can ask.
+func (s *RPCServer) ListJobs(ctx context.Context, req *pb.JobsRequest) (*pb.JobsResponse, error) {
+	fe, ident, err := rpcJobFrontend(ctx, req.GetServer(), false)
+	if err != nil {
+		return nil, err
+	}
+	be.Logf(LogLevelInfo, "ListJobs called for %q", ident)
+	jobs, err := LoadJobs(fe)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.JobsResponse{Server: fe.Name}
+	for i := range jobs {
+		resp.Job = append(resp.Job, jobEntry(&jobs[i]))
+	}
+	return resp, nil
+}
+
+// SaveJob adds or changes a job on a server or its chat group. Only the
+// owner and backend admins can.
+func (s *RPCServer) SaveJob(ctx context.Context, req *pb.JobRequest) (*pb.JobEntry, error) {
+	fe, ident, err := rpcJobFrontend(ctx, req.GetServer(), true)
+	if err != nil {
+		return nil, err
+	}
+	be.Logf(LogLevelInfo, "SaveJob called for %q", ident)
+	j := &Job{
+		Owner:    fe.Owner,
+		Server:   fe.Name,
+		Name:     req.GetName(),
+		Spec:     req.GetSpec(),
+		Timezone: req.GetTimezone(),
+		Action:   req.GetAction(),
+		Args:     req.GetArgs(),
+		Enabled:  !req.GetDisabled(),
+		Author:   "rpc:" + ident,
+	}
+	if req.GetGroup() {
+		if fe.ChatGroup == "" {
+			return nil, fmt.Errorf("%s isn't in a chat group", fe.Name)
+		}
+		j.Server, j.Group = "", fe.ChatGroup
+	}
+	if err := SaveJob(j); err != nil {
+		return nil, err
+	}
+	return jobEntry(j), nil
+}
+
+// DeleteJob removes one of a server's jobs. Only the owner and backend
+// admins can.
+func (s *RPCServer) DeleteJob(ctx context.Context, req *pb.JobRequest) (*pb.JobEntry, error) {
+	fe, ident, err := rpcJobFrontend(ctx, req.GetServer(), true)
+	if err != nil {
+		return nil, err
+	}
+	be.Logf(LogLevelInfo, "DeleteJob called for %q", ident)
+	j, err := LoadJob(fe, req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if err := DeleteJob(j); err != nil {
+		return nil, err
+	}
+	return &pb.JobEntry{Name: j.Name, Target: j.Target()}, nil
+}
+
+// RunJob runs one of a server's jobs now. Only the owner and backend admins
+// can.
+func (s *RPCServer) RunJob(ctx context.Context, req *pb.JobRequest) (*pb.JobEntry, error) {
+	fe, ident, err := rpcJobFrontend(ctx, req.GetServer(), true)
+	if err != nil {
+		return nil, err
+	}
+	be.Logf(LogLevelInfo, "RunJob called for %q", ident)
+	j, err := LoadJob(fe, req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if err := RunJob(j); err != nil {
+		be.Logln(LogLevelInfo, err)
+	}
+	return jobEntry(j), nil
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/netip"
 	"os"
 	"regexp"
 	"slices"
@@ -11,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/packetflinger/q2admind/frontend"
 	"google.golang.org/protobuf/encoding/prototext"
 
@@ -69,11 +71,7 @@ func CheckRule(p *frontend.Player, r *pb.Rule, t time.Time) bool {
 	if len(r.GetAddress()) > 0 {
 		need++
 		for _, address := range r.GetAddress() {
-			_, network, err := net.ParseCIDR(address)
-			if err != nil {
-				continue
-			}
-			if network.Contains(net.ParseIP(p.IP)) {
+			if AddressMatches(address, p.IP) {
 				have++
 				match = true
 				break
@@ -127,6 +125,41 @@ func CheckRule(p *frontend.Player, r *pb.Rule, t time.Time) bool {
 		}
 	}
 
+	if len(r.GetCookie()) > 0 {
+		need++
+		if p.Cookie != "" && slices.Contains(r.GetCookie(), p.Cookie) {
+			have++
+			match = true
+		}
+	}
+
+	if len(r.GetProfile()) > 0 {
+		need++
+		if p.ProfileID > 0 && slices.Contains(r.GetProfile(), p.ProfileID) {
+			have++
+			match = true
+		}
+	}
+
+	if len(r.GetCountry()) > 0 {
+		need++
+		for _, country := range r.GetCountry() {
+			if p.Country != "" && strings.EqualFold(country, p.Country) {
+				have++
+				match = true
+				break
+			}
+		}
+	}
+
+	if len(r.GetAsn()) > 0 {
+		need++
+		if p.ASN > 0 && slices.Contains(r.GetAsn(), p.ASN) {
+			have++
+			match = true
+		}
+	}
+
 	if r.GetVpn() {
 		need++
 		if p.VPN {
@@ -300,11 +333,7 @@ func RuleExceptionMatch(ex *pb.Exception, p *frontend.Player) bool {
 
 	if len(ex.GetAddress()) > 0 {
 		for _, address := range ex.GetAddress() {
-			_, network, err := net.ParseCIDR(address)
-			if err != nil {
-				continue
-			}
-			if network.Contains(net.ParseIP(p.IP)) {
+			if AddressMatches(address, p.IP) {
 				return true
 			}
 		}
@@ -334,6 +363,26 @@ func RuleExceptionMatch(ex *pb.Exception, p *frontend.Player) bool {
 		}
 	}
 
+	if p.Cookie != "" && slices.Contains(ex.GetCookie(), p.Cookie) {
+		return true
+	}
+
+	if p.ProfileID > 0 && slices.Contains(ex.GetProfile(), p.ProfileID) {
+		return true
+	}
+
+	if len(ex.GetCountry()) > 0 && p.Country != "" {
+		for _, country := range ex.GetCountry() {
+			if strings.EqualFold(country, p.Country) {
+				return true
+			}
+		}
+	}
+
+	if p.ASN > 0 && slices.Contains(ex.GetAsn(), p.ASN) {
+		return true
+	}
+
 	if len(ex.GetUserInfo()) > 0 {
 		for _, uipair := range ex.GetUserInfo() {
 			match, err := regexp.MatchString(uipair.Value, p.UserinfoMap[uipair.Property])
@@ -348,6 +397,32 @@ func RuleExceptionMatch(ex *pb.Exception, p *frontend.Player) bool {
 	return false
 }
 
+// AddressMatches will decide if a player's IP falls within a rule address.
+// The rule address can be a network in CIDR notation (192.0.2.0/24,
+// 2001:db8::/32) or a single host address. IPv4 and IPv6 never match each
+// other, except for IPv4-mapped IPv6 addresses which are treated as IPv4.
+//
+// Called from CheckRule() and RuleExceptionMatch()
+func AddressMatches(address string, ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap().WithZone("")
+	if !strings.Contains(address, "/") {
+		host, err := netip.ParseAddr(address)
+		if err != nil {
+			return false
+		}
+		return host.Unmap() == addr
+	}
+	network, err := netip.ParsePrefix(address)
+	if err != nil {
+		return false
+	}
+	return network.Masked().Contains(addr)
+}
+
 // durationToSeconds converts a string representation of a duration of time into
 // the appropriate number of seconds.
 //
@@ -483,9 +558,18 @@ func ApplyMatchedRules(p *frontend.Player, rules []*pb.Rule) {
 	for _, rule := range rules {
 		if rule.GetType() == pb.RuleType_BAN {
 			KickPlayer(fe, p, strings.Join(rule.Message, "\n"))
+			NotifyDiscord(fe, DiscordBan, p.Name, strings.Join(rule.GetDescription(), " "))
+			err := RecordOffense(p, OffenseBan, rule.GetUuid(), strings.Join(rule.GetDescription(), " "), rule.GetExpirationTime())
+			if err != nil {
+				be.Logln(LogLevelInfo, err)
+			}
 			break // don't bother with the rest
 		}
 		if rule.GetType() == pb.RuleType_MUTE {
+			err := RecordOffense(p, OffenseMute, rule.GetUuid(), strings.Join(rule.GetDescription(), " "), rule.GetExpirationTime())
+			if err != nil {
+				be.Logln(LogLevelInfo, err)
+			}
 			p.Muted = true
 			SayPlayer(fe, p, PRINT_CHAT, strings.Join(rule.GetMessage(), " "))
 			MutePlayer(fe, p, -1)
@@ -520,3 +604,100 @@ func RuleDetailLine(rule *pb.Rule) (string, error) {
 	}
 	return string(out), nil
 }
+
+// PlayerBanRules will build ban rules targeting a specific player. The rules
+// capture what identifies them: their cookie (if they have one) and their
+// exact address. Since rule criteria are greedy (all have to match), each
+// identifier gets its own rule so changing just one of them (a new IP via VPN
+// for example) doesn't get them around it. Their name is only kept in the
+// description, banning a name on its own would catch anyone else using it.
+//
+// The `who` arg is the identity of the person issuing the ban and `reason` is
+// what's shown to the player when they're kicked.
+func PlayerBanRules(p *frontend.Player, who string, reason string) ([]*pb.Rule, error) {
+	var rules []*pb.Rule
+	if p == nil {
+		return rules, errors.New("PlayerBanRules(): null player")
+	}
+	if reason == "" {
+		reason = "You're banned"
+	}
+	now := time.Now().Unix()
+	desc := fmt.Sprintf("%q banned by %s", p.Name, who)
+	newRule := func() *pb.Rule {
+		return &pb.Rule{
+			Uuid:         uuid.NewString(),
+			Type:         pb.RuleType_BAN,
+			Message:      []string{reason},
+			Description:  []string{desc},
+			CreationTime: now,
+		}
+	}
+	if p.Cookie != "" {
+		r := newRule()
+		r.Cookie = []string{p.Cookie}
+		rules = append(rules, r)
+	}
+	if ip := net.ParseIP(p.IP); ip != nil {
+		bits := 128
+		if ip.To4() != nil {
+			bits = 32
+		}
+		r := newRule()
+		r.Address = []string{fmt.Sprintf("%s/%d", ip.String(), bits)}
+		rules = append(rules, r)
+	}
+	if len(rules) == 0 {
+		return rules, fmt.Errorf("nothing to identify player %d with", p.ClientID)
+	}
+	return rules, nil
+}
+
+// BanPlayer will create ban rules for a player, add them to the frontend's
+// rules, save them to disk and kick the player.
+//
+// Called from the "ban" SSH command and the website's player view
+func BanPlayer(fe *frontend.Frontend, p *frontend.Player, who string, reason string) ([]*pb.Rule, error) {
+	if fe == nil || p == nil {
+		return nil, errors.New("BanPlayer(): null frontend or player")
+	}
+	rules, err := PlayerBanRules(p, who, reason)
+	if err != nil {
+		return nil, err
+	}
+	return applyBanRules(fe, p, rules)
+}
+
+// TempBanPlayer is BanPlayer but the rules expire after a number of seconds.
+//
+// Called from the in-game "ban" admin command
+func TempBanPlayer(fe *frontend.Frontend, p *frontend.Player, who string, reason string, seconds int64) ([]*pb.Rule, error) {
+	if fe == nil || p == nil {
+		return nil, errors.New("TempBanPlayer(): null frontend or player")
+	}
+	if seconds <= 0 {
+		return nil, fmt.Errorf("invalid ban length: %d", seconds)
+	}
+	rules, err := PlayerBanRules(p, who, reason)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rules {
+		r.ExpirationTime = r.GetCreationTime() + seconds
+	}
+	return applyBanRules(fe, p, rules)
+}
+
+// applyBanRules adds a player's new ban rules to the frontend, saves them and
+// kicks the player
+func applyBanRules(fe *frontend.Frontend, p *frontend.Player, rules []*pb.Rule) ([]*pb.Rule, error) {
+	fe.Rules = append(fe.Rules, rules...)
+	fe.ScopeRules("client", rules)
+	err := fe.MaterializeRules(fe.Rules)
+	if err != nil {
+		return rules, fmt.Errorf("error saving ban rules: %v", err)
+	}
+	p.Rules = append(p.Rules, rules...)
+	ApplyMatchedRules(p, rules[:1])
+	return rules, nil
+}
@@ -243,6 +243,66 @@ func TestRuleExceptionMatch(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			desc: "test11_country",
+			exception: &pb.Exception{
+				Country: []string{"se", "fi"},
+			},
+			player: &frontend.Player{
+				Country: "FI",
+			},
+			want: true,
+		},
+		{
+			desc: "test13_cookie",
+			exception: &pb.Exception{
+				Cookie: []string{"a1b2c3d4e5f6a1b2c3d4e5f6"},
+			},
+			player: &frontend.Player{
+				Cookie: "a1b2c3d4e5f6a1b2c3d4e5f6",
+			},
+			want: true,
+		},
+		{
+			desc: "test12_asn",
+			exception: &pb.Exception{
+				Asn: []uint32{64496},
+			},
+			player: &frontend.Player{
+				ASN: 64511,
+			},
+			want: false,
+		},
+		{
+			desc: "test14_ipv6_network",
+			exception: &pb.Exception{
+				Address: []string{"2001:db8:1::/48"},
+			},
+			player: &frontend.Player{
+				IP: "2001:db8:1:2::55",
+			},
+			want: true,
+		},
+		{
+			desc: "test14_ipv4_rule_ipv6_player",
+			exception: &pb.Exception{
+				Address: []string{"0.0.0.0/0"},
+			},
+			player: &frontend.Player{
+				IP: "2001:db8:1:2::55",
+			},
+			want: false,
+		},
+		{
+			desc: "test15_profile",
+			exception: &pb.Exception{
+				Profile: []int64{12, 40},
+			},
+			player: &frontend.Player{
+				ProfileID: 40,
+			},
+			want: true,
+		},
 	}
 
 	for _, tc := range tests {
@@ -512,6 +572,146 @@ func TestCheckRule(t *testing.T) {
 			when: time.Date(2024, time.October, 5, 16, 0, 0, 0, time.UTC),
 			want: true,
 		},
+		{
+			desc: "test12_country",
+			rule: &pb.Rule{
+				Country: []string{"US", "CA"},
+			},
+			player: &frontend.Player{
+				Country: "us",
+			},
+			when: time.Now(),
+			want: true,
+		},
+		{
+			desc: "test12_country_unknown",
+			rule: &pb.Rule{
+				Country: []string{"US"},
+			},
+			player: &frontend.Player{},
+			when:   time.Now(),
+			want:   false,
+		},
+		{
+			desc: "test13_asn",
+			rule: &pb.Rule{
+				Asn: []uint32{64496, 64500},
+			},
+			player: &frontend.Player{
+				ASN: 64500,
+			},
+			when: time.Now(),
+			want: true,
+		},
+		{
+			desc: "test14_cookie",
+			rule: &pb.Rule{
+				Cookie: []string{"0123456789abcdef01234567"},
+			},
+			player: &frontend.Player{
+				Cookie: "0123456789abcdef01234567",
+				IP:     "192.0.2.55",
+			},
+			when: time.Now(),
+			want: true,
+		},
+		{
+			desc: "test14_cookie_missing",
+			rule: &pb.Rule{
+				Cookie: []string{"0123456789abcdef01234567"},
+			},
+			player: &frontend.Player{},
+			when:   time.Now(),
+			want:   false,
+		},
+		{
+			desc: "test13_asn_country",
+			rule: &pb.Rule{
+				Country: []string{"NL"},
+				Asn:     []uint32{64496},
+			},
+			player: &frontend.Player{
+				Country: "DE",
+				ASN:     64496,
+			},
+			when: time.Now(),
+			want: false,
+		},
+		{
+			desc: "test15_ipv6_network",
+			rule: &pb.Rule{
+				Address: []string{"2001:db8:1:2::/64"},
+			},
+			player: &frontend.Player{
+				IP: "2001:db8:1:2:aaaa::1",
+			},
+			when: time.Now(),
+			want: true,
+		},
+		{
+			desc: "test15_ipv6_outside_network",
+			rule: &pb.Rule{
+				Address: []string{"2001:db8:1:2::/64"},
+			},
+			player: &frontend.Player{
+				IP: "2001:db8:1:3::1",
+			},
+			when: time.Now(),
+			want: false,
+		},
+		{
+			desc: "test15_ipv6_host",
+			rule: &pb.Rule{
+				Address: []string{"2001:db8::4"},
+			},
+			player: &frontend.Player{
+				IP: "2001:db8:0:0::4",
+			},
+			when: time.Now(),
+			want: true,
+		},
+		{
+			desc: "test15_ipv4_mapped",
+			rule: &pb.Rule{
+				Address: []string{"192.0.2.0/24"},
+			},
+			player: &frontend.Player{
+				IP: "::ffff:192.0.2.9",
+			},
+			when: time.Now(),
+			want: true,
+		},
+		{
+			desc: "test15_ipv6_rule_ipv4_player",
+			rule: &pb.Rule{
+				Address: []string{"::/0"},
+			},
+			player: &frontend.Player{
+				IP: "192.0.2.9",
+			},
+			when: time.Now(),
+			want: false,
+		},
+		{
+			desc: "test16_profile",
+			rule: &pb.Rule{
+				Profile: []int64{7},
+			},
+			player: &frontend.Player{
+				ProfileID: 7,
+			},
+			when: time.Now(),
+			want: true,
+		},
+		{
+			desc: "test16_profile_unlinked",
+			rule: &pb.Rule{
+				Profile: []int64{7},
+			},
+			player: &frontend.Player{},
+			when:   time.Now(),
+			want:   false,
+		},
 	}
 
 	for _, tc := range tests {
@@ -618,3 +818,55 @@ func TestStringToTime(t *testing.T) {
 		})
 	}
 }
+
+func TestPlayerBanRules(t *testing.T) {
+	tests := []struct {
+		desc   string
+		player *frontend.Player
+		want   int // how many rules
+	}{
+		{
+			desc: "everything",
+			player: &frontend.Player{
+				Name:   "claire[x]",
+				IP:     "192.0.2.4",
+				Cookie: "0123456789abcdef01234567",
+			},
+			want: 2,
+		},
+		{
+			desc: "no cookie",
+			player: &frontend.Player{
+				Name: "claire",
+				IP:   "2001:db8::1",
+			},
+			want: 1,
+		},
+		{
+			desc:   "name only",
+			player: &frontend.Player{Name: "player"},
+			want:   0,
+		},
+		{
+			desc:   "nothing",
+			player: &frontend.Player{},
+			want:   0,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			rules, _ := PlayerBanRules(tc.player, "test@example.com", "")
+			if len(rules) != tc.want {
+				t.Fatalf("PlayerBanRules() got %d rules, want %d", len(rules), tc.want)
+			}
+			for _, r := range rules {
+				if r.GetType() != pb.RuleType_BAN {
+					t.Errorf("rule type = %v, want BAN", r.GetType())
+				}
+				if !CheckRule(tc.player, r, time.Now()) {
+					t.Errorf("rule %v doesn't match the player it was made from", r)
+				}
+			}
+		})
+	}
+}
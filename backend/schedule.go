@@ -0,0 +1,594 @@
+// Scheduled jobs let owners run things on their frontends at set times: a
+// console command, a message to everyone, switching map rotations, turning
+// rules on or off, or restarting the match. Jobs run on a single frontend or
+// on every one of the owner's frontends in a chat group.
+//
+// Schedules are written like cron, in the job's timezone (the backend's if
+// none is given):
+//
+//	minute hour day-of-month month day-of-week
+//
+// Each field is "*", a number, a range (1-5) or a list (1,15) and can have a
+// step (*/15, 8-18/2). Months and weekdays can be named (jan, mon). If both
+// day fields are set, either one matching is enough, like cron.
+// @hourly, @daily, @weekly, @monthly and @yearly are shortcuts.
+//
+// Jobs and a history of their runs are kept in the database. Runs missed
+// while the backend was down aren't made up.
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/packetflinger/q2admind/frontend"
+)
+
+// Things a scheduled job can do
+const (
+	JobCommand     = "command"      // args are a console command
+	JobSay         = "say"          // args are a message to every player
+	JobRotation    = "rotation"     // args name the map rotation to use
+	JobEnableRule  = "enable-rule"  // args are the start of a rule's UUID
+	JobDisableRule = "disable-rule" // args are the start of a rule's UUID
+	JobRestart     = "restart"      // reload the current map
+)
+
+// JobActions is every action a job can have
+var JobActions = []string{JobCommand, JobSay, JobRotation, JobEnableRule, JobDisableRule, JobRestart}
+
+const jobRunHistory = 20 // runs shown per job
+
+var cronShortcuts = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+}
+
+var cronMonths = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronWeekdays = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// CronSpec is a parsed schedule, each field a bitmask of the values that
+// match
+type CronSpec struct {
+	minute  uint64
+	hour    uint64
+	dom     uint64
+	month   uint64
+	dow     uint64
+	anyDOM  bool
+	anyDOW  bool
+	Pattern string
+}
+
+// ParseCron reads a 5 field cron schedule or one of the @ shortcuts
+func ParseCron(spec string) (*CronSpec, error) {
+	pattern := strings.Join(strings.Fields(strings.ToLower(spec)), " ")
+	fields := strings.Fields(pattern)
+	if len(fields) == 1 && strings.HasPrefix(fields[0], "@") {
+		expanded, ok := cronShortcuts[fields[0]]
+		if !ok {
+			return nil, fmt.Errorf("unknown schedule %q", fields[0])
+		}
+		fields = strings.Fields(expanded)
+	}
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q, want: minute hour day month weekday", spec)
+	}
+	c := &CronSpec{Pattern: pattern}
+	var err error
+	if c.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("invalid minute: %v", err)
+	}
+	if c.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("invalid hour: %v", err)
+	}
+	if c.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("invalid day of month: %v", err)
+	}
+	if c.month, err = parseCronField(fields[3], 1, 12, cronMonths); err != nil {
+		return nil, fmt.Errorf("invalid month: %v", err)
+	}
+	if c.dow, err = parseCronField(fields[4], 0, 7, cronWeekdays); err != nil {
+		return nil, fmt.Errorf("invalid day of week: %v", err)
+	}
+	if c.dow&(1<<7) != 0 { // 7 is sunday too
+		c.dow |= 1
+	}
+	c.anyDOM = fields[2] == "*"
+	c.anyDOW = fields[4] == "*"
+	return c, nil
+}
+
+// parseCronField turns a field like "1-10/2,15" into a bitmask
+func parseCronField(field string, min int, max int, names map[string]int) (uint64, error) {
+	value := func(s string) (int, error) {
+		if n, ok := names[s]; ok {
+			return n, nil
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < min || n > max {
+			return 0, fmt.Errorf("%q isn't %d-%d", s, min, max)
+		}
+		return n, nil
+	}
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, stepped := strings.Cut(part, "/")
+		step := 1
+		if stepped {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepStr)
+			}
+			step = n
+		}
+		lo, hi := min, max
+		if rng != "*" {
+			first, last, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = value(first); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = value(last); err != nil {
+					return 0, err
+				}
+			} else if stepped {
+				hi = max
+			}
+			if hi < lo {
+				return 0, fmt.Errorf("backwards range %q", rng)
+			}
+		}
+		for i := lo; i <= hi; i += step {
+			bits |= 1 << i
+		}
+	}
+	return bits, nil
+}
+
+// Matches checks if the schedule includes the minute t is in
+func (c *CronSpec) Matches(t time.Time) bool {
+	return c.minute&(1<<t.Minute()) != 0 && c.hour&(1<<t.Hour()) != 0 && c.dayMatches(t)
+}
+
+// dayMatches checks the month and both day fields. Like cron, when both
+// day fields are restricted either can match.
+func (c *CronSpec) dayMatches(t time.Time) bool {
+	if c.month&(1<<int(t.Month())) == 0 {
+		return false
+	}
+	dom := c.dom&(1<<t.Day()) != 0
+	dow := c.dow&(1<<int(t.Weekday())) != 0
+	if c.anyDOM || c.anyDOW {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// Next is the first minute after t the schedule matches, in t's timezone.
+// It's the zero time if it never does (like February 30th).
+func (c *CronSpec) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		y, m, d := t.Date()
+		switch {
+		case !c.dayMatches(t):
+			t = time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+		case c.hour&(1<<t.Hour()) == 0:
+			next := time.Date(y, m, d, t.Hour()+1, 0, 0, 0, loc)
+			if !next.After(t) { // an hour repeated when clocks go back
+				next = t.Add(time.Hour).Truncate(time.Hour)
+			}
+			t = next
+		case c.minute&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// Job is something to do on a schedule
+type Job struct {
+	ID         int64
+	Owner      string // owner of the frontends it runs on
+	Server     string // frontend it runs on, or
+	Group      string // chat group it runs in
+	Name       string
+	Spec       string
+	Timezone   string // IANA name, blank for the backend's
+	Action     string
+	Args       string
+	Enabled    bool
+	Author     string // who made it
+	Created    int64
+	LastRun    int64
+	LastStatus string
+	Runs       []JobRun // recent runs, when loaded
+}
+
+// JobRun is the result of a job running on a frontend
+type JobRun struct {
+	ID      int64
+	Job     int64
+	Server  string
+	Time    int64
+	Success bool
+	Output  string
+}
+
+// Target is where the job runs
+func (j Job) Target() string {
+	if j.Group != "" {
+		return "group:" + j.Group
+	}
+	return j.Server
+}
+
+// Location is the job's timezone
+func (j Job) Location() *time.Location {
+	if loc, err := time.LoadLocation(j.Timezone); err == nil && j.Timezone != "" {
+		return loc
+	}
+	return time.Local
+}
+
+// Next is when the job runs after t, the zero time if it won't
+func (j Job) Next(t time.Time) time.Time {
+	c, err := ParseCron(j.Spec)
+	if !j.Enabled || err != nil {
+		return time.Time{}
+	}
+	return c.Next(t.In(j.Location()))
+}
+
+// NextRun is when the job runs next, for showing
+func (j Job) NextRun() int64 {
+	next := j.Next(time.Now())
+	if next.IsZero() {
+		return 0
+	}
+	return next.Unix()
+}
+
+// NextRunTime is when the job runs next in its timezone, for showing
+func (j Job) NextRunTime() string {
+	next := j.NextRun()
+	if next == 0 {
+		return "never"
+	}
+	return time.Unix(next, 0).In(j.Location()).Format("2006-01-02 15:04 MST")
+}
+
+// Validate checks a job can be saved
+func (j *Job) Validate() error {
+	if !rotationName.MatchString(j.Name) {
+		return fmt.Errorf("invalid job name %q, use letters, numbers, - and _", j.Name)
+	}
+	if (j.Server == "") == (j.Group == "") {
+		return fmt.Errorf("job %q needs a server or a chat group", j.Name)
+	}
+	c, err := ParseCron(j.Spec)
+	if err != nil {
+		return err
+	}
+	j.Spec = c.Pattern
+	if _, err := time.LoadLocation(j.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", j.Timezone)
+	}
+	j.Args = strings.TrimSpace(j.Args)
+	if !slices.Contains(JobActions, j.Action) {
+		return fmt.Errorf("unknown action %q, want one of: %s", j.Action, strings.Join(JobActions, ", "))
+	}
+	if j.Action != JobRestart && j.Args == "" {
+		return fmt.Errorf("%s jobs need something to %s", j.Action, j.Action)
+	}
+	if (j.Action == JobEnableRule || j.Action == JobDisableRule) && len(j.Args) < 4 {
+		return fmt.Errorf("rule ID %q has too few characters", j.Args)
+	}
+	return nil
+}
+
+// ParseJobArgs splits what's typed after a job's name into its schedule,
+// action and arguments:
+//
+//	@daily say good morning
+//	0 20 * * fri rotation ctf
+func ParseJobArgs(argv []string) (spec string, action string, args string, err error) {
+	n := 5
+	if len(argv) > 0 && strings.HasPrefix(argv[0], "@") {
+		n = 1
+	}
+	if len(argv) <= n {
+		return "", "", "", fmt.Errorf("want: <schedule> <action> [args]")
+	}
+	return strings.Join(argv[:n], " "), argv[n], strings.Join(argv[n+1:], " "), nil
+}
+
+const jobColumns = `id, owner, server, grp, name, spec, timezone, action, args, enabled, author, created, last_run, last_status`
+
+func scanJob(row interface{ Scan(...any) error }) (*Job, error) {
+	var j Job
+	err := row.Scan(&j.ID, &j.Owner, &j.Server, &j.Group, &j.Name, &j.Spec, &j.Timezone, &j.Action, &j.Args,
+		&j.Enabled, &j.Author, &j.Created, &j.LastRun, &j.LastStatus)
+	if err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+func queryJobs(qry string, args ...any) ([]Job, error) {
+	var jobs []Job
+	rows, err := db.Handle.Query(qry, args...)
+	if err != nil {
+		return jobs, fmt.Errorf("error loading scheduled jobs: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		j, err := scanJob(rows)
+		if err != nil {
+			return jobs, fmt.Errorf("error scanning scheduled jobs: %v", err)
+		}
+		jobs = append(jobs, *j)
+	}
+	return jobs, nil
+}
+
+// LoadJobs fetches the jobs that run on a frontend, its own and its chat
+// group's
+func LoadJobs(fe *frontend.Frontend) ([]Job, error) {
+	qry := "SELECT " + jobColumns + `
+		FROM scheduled_job
+		WHERE server = ? OR (grp != '' AND grp = ? AND owner = ? COLLATE NOCASE)
+		ORDER BY grp, name`
+	return queryJobs(qry, fe.Name, fe.ChatGroup, fe.Owner)
+}
+
+// LoadJob finds one of a frontend's jobs by name, its own before its
+// group's
+func LoadJob(fe *frontend.Frontend, name string) (*Job, error) {
+	jobs, err := LoadJobs(fe)
+	if err != nil {
+		return nil, err
+	}
+	for _, j := range jobs {
+		if j.Name == name {
+			return &j, nil
+		}
+	}
+	return nil, fmt.Errorf("no job %q", name)
+}
+
+// SaveJob adds a job or updates its schedule and action
+func SaveJob(j *Job) error {
+	if err := j.Validate(); err != nil {
+		return err
+	}
+	if j.Created == 0 {
+		j.Created = time.Now().Unix()
+	}
+	qry := `
+		INSERT INTO scheduled_job (owner, server, grp, name, spec, timezone, action, args, enabled, author, created)
+		VALUES (?,?,?,?,?,?,?,?,?,?,?)
+		ON CONFLICT(owner, server, grp, name) DO UPDATE SET
+			spec = excluded.spec, timezone = excluded.timezone, action = excluded.action,
+			args = excluded.args, enabled = excluded.enabled`
+	_, err := db.Handle.Exec(qry, j.Owner, j.Server, j.Group, j.Name, j.Spec, j.Timezone, j.Action, j.Args, j.Enabled, j.Author, j.Created)
+	if err != nil {
+		return fmt.Errorf("error saving job %q: %v", j.Name, err)
+	}
+	qry = "SELECT " + jobColumns + " FROM scheduled_job WHERE owner = ? AND server = ? AND grp = ? AND name = ?"
+	saved, err := scanJob(db.Handle.QueryRow(qry, j.Owner, j.Server, j.Group, j.Name))
+	if err != nil {
+		return fmt.Errorf("error loading job %q: %v", j.Name, err)
+	}
+	*j = *saved
+	return nil
+}
+
+// DeleteJob removes a job and its history
+func DeleteJob(j *Job) error {
+	res, err := db.Handle.Exec("DELETE FROM scheduled_job WHERE id = ?", j.ID)
+	if err != nil {
+		return fmt.Errorf("error removing job %q: %v", j.Name, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("no job %q", j.Name)
+	}
+	if _, err := db.Handle.Exec("DELETE FROM scheduled_run WHERE job = ?", j.ID); err != nil {
+		return fmt.Errorf("error removing history for job %q: %v", j.Name, err)
+	}
+	return nil
+}
+
+// LoadJobRuns fetches a job's most recent runs, newest first
+func LoadJobRuns(job int64, limit int) ([]JobRun, error) {
+	var runs []JobRun
+	qry := `
+		SELECT id, job, server, time, success, output
+		FROM scheduled_run WHERE job = ? ORDER BY time DESC, id DESC LIMIT ?`
+	rows, err := db.Handle.Query(qry, job, limit)
+	if err != nil {
+		return runs, fmt.Errorf("error loading job history: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var r JobRun
+		if err := rows.Scan(&r.ID, &r.Job, &r.Server, &r.Time, &r.Success, &r.Output); err != nil {
+			return runs, fmt.Errorf("error scanning job history: %v", err)
+		}
+		runs = append(runs, r)
+	}
+	return runs, nil
+}
+
+// jobFrontends is where a job runs
+func jobFrontends(j *Job) []*frontend.Frontend {
+	var fes []*frontend.Frontend
+	for i := range be.frontends {
+		fe := &be.frontends[i]
+		if j.Group != "" {
+			if fe.ChatGroup == j.Group && strings.EqualFold(fe.Owner, j.Owner) {
+				fes = append(fes, fe)
+			}
+		} else if fe.Name == j.Server {
+			fes = append(fes, fe)
+		}
+	}
+	return fes
+}
+
+// runJobOn does a job's action on one frontend
+func runJobOn(fe *frontend.Frontend, j *Job) (string, error) {
+	if !fe.Connected {
+		return "", fmt.Errorf("%s is offline", fe.Name)
+	}
+	var out string
+	switch j.Action {
+	case JobCommand:
+		ConsoleCommand(fe, j.Args)
+		out = "ran " + j.Args
+	case JobSay:
+		SayEveryone(fe, PRINT_CHAT, j.Args)
+		out = "said " + j.Args
+	case JobRotation:
+		if err := ActivateRotation(fe, j.Args); err != nil {
+			return "", err
+		}
+		out = "using rotation " + j.Args
+	case JobEnableRule, JobDisableRule:
+		disable := j.Action == JobDisableRule
+		var changed []string
+		for _, r := range fe.Rules {
+			if strings.HasPrefix(r.GetUuid(), j.Args) {
+				r.Disabled = disable
+				changed = append(changed, r.GetUuid())
+			}
+		}
+		if len(changed) == 0 {
+			return "", fmt.Errorf("no rule %q on %s", j.Args, fe.Name)
+		}
+		if err := fe.MaterializeRules(fe.Rules); err != nil {
+			return "", err
+		}
+		out = fmt.Sprintf("%sd rule %s", strings.TrimSuffix(j.Action, "-rule"), strings.Join(changed, ", "))
+	case JobRestart:
+		if fe.CurrentMap == "" {
+			return "", fmt.Errorf("%s has no map loaded", fe.Name)
+		}
+		ConsoleCommand(fe, "gamemap "+fe.CurrentMap)
+		out = "restarted " + fe.CurrentMap
+	default:
+		return "", fmt.Errorf("unknown action %q", j.Action)
+	}
+	SendMessages(fe)
+	return out, nil
+}
+
+// RunJob does a job now on every frontend it's for and saves how it went
+func RunJob(j *Job) error {
+	fes := jobFrontends(j)
+	if len(fes) == 0 {
+		return recordJobRun(j, j.Target(), fmt.Errorf("no frontends to run on"), "")
+	}
+	var errs []error
+	for _, fe := range fes {
+		out, err := runJobOn(fe, j)
+		if err == nil && fe.Log != nil {
+			logMsg := fmt.Sprintf("%-20s %s: %s", "SCHEDULE:", j.Name, out)
+			fe.Log.Println(logMsg)
+			fe.SSHPrintln(logMsg)
+		}
+		errs = append(errs, recordJobRun(j, fe.Name, err, out))
+	}
+	return errors.Join(errs...)
+}
+
+// recordJobRun saves a run in the job's history. The run's error is
+// returned along with any from saving it.
+func recordJobRun(j *Job, server string, runErr error, out string) error {
+	status := "ok"
+	if runErr != nil {
+		status = "failed"
+		out = runErr.Error()
+	}
+	j.LastRun = time.Now().Unix()
+	j.LastStatus = fmt.Sprintf("%s on %s: %s", status, server, out)
+	qry := "INSERT INTO scheduled_run (job, server, time, success, output) VALUES (?,?,?,?,?)"
+	_, err := db.Handle.Exec(qry, j.ID, server, j.LastRun, runErr == nil, out)
+	if err == nil {
+		qry = "UPDATE scheduled_job SET last_run = ?, last_status = ? WHERE id = ?"
+		_, err = db.Handle.Exec(qry, j.LastRun, j.LastStatus, j.ID)
+	}
+	if err != nil {
+		err = fmt.Errorf("error saving run of job %q: %v", j.Name, err)
+	}
+	return errors.Join(runErr, err)
+}
+
+// RunDueJobs runs every enabled job scheduled after from and up to to. A job
+// runs once even if it was due more than once in that time.
+func RunDueJobs(from time.Time, to time.Time) ([]Job, error) {
+	jobs, err := queryJobs("SELECT " + jobColumns + " FROM scheduled_job WHERE enabled = 1 ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	var ran []Job
+	for _, j := range jobs {
+		next := j.Next(from)
+		if next.IsZero() || next.After(to) {
+			continue
+		}
+		if err := RunJob(&j); err != nil {
+			be.Logf(LogLevelInfo, "scheduled job %q on %s: %v\n", j.Name, j.Target(), err)
+		}
+		ran = append(ran, j)
+	}
+	return ran, nil
+}
+
+// startScheduler wakes up at the start of every minute to run the jobs due.
+//
+// Called from Startup() in a goroutine
+func (s *Backend) startScheduler() {
+	last := time.Now().Truncate(time.Minute)
+	for {
+		time.Sleep(time.Until(last.Add(time.Minute)))
+		now := time.Now().Truncate(time.Minute)
+		if _, err := RunDueJobs(last, now); err != nil {
+			s.Logln(LogLevelInfo, err)
+		}
+		last = now
+	}
+}
+
+// jobAccess checks if someone can see a frontend's jobs
+func jobAccess(fe *frontend.Frontend, ident string) bool {
+	_, user := fe.WebUsers[ident]
+	return strings.EqualFold(fe.Owner, ident) || user || BackendAdmin(ident)
+}
+
+// jobManager checks if someone can change a frontend's jobs
+func jobManager(fe *frontend.Frontend, ident string) bool {
+	return strings.EqualFold(fe.Owner, ident) || BackendAdmin(ident)
+}
@@ -0,0 +1,286 @@
+package backend
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/packetflinger/q2admind/frontend"
+
+	pb "github.com/packetflinger/q2admind/proto"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    string
+		wantErr bool
+	}{
+		{spec: "*/15 * * * *", want: "*/15 * * * *"},
+		{spec: " 0  20 * * FRI", want: "0 20 * * fri"},
+		{spec: "0 8-18/2 1,15 jan-jun 1-5", want: "0 8-18/2 1,15 jan-jun 1-5"},
+		{spec: "@Daily", want: "@daily"},
+		{spec: "@sometimes", wantErr: true},
+		{spec: "* * * *", wantErr: true},
+		{spec: "60 * * * *", wantErr: true},
+		{spec: "0 5-1 * * *", wantErr: true},
+		{spec: "*/0 * * * *", wantErr: true},
+		{spec: "0 0 0 * *", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.spec, func(t *testing.T) {
+			got, err := ParseCron(tc.spec)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseCron(%q) error = %v, wantErr %v", tc.spec, err, tc.wantErr)
+			}
+			if err == nil && got.Pattern != tc.want {
+				t.Errorf("ParseCron(%q).Pattern = %q, want %q", tc.spec, got.Pattern, tc.want)
+			}
+		})
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{
+			name: "every 15 minutes",
+			spec: "*/15 * * * *",
+			from: time.Date(2026, 10, 16, 20, 7, 30, 0, time.UTC),
+			want: time.Date(2026, 10, 16, 20, 15, 0, 0, time.UTC),
+		},
+		{
+			name: "not the same minute",
+			spec: "30 20 * * *",
+			from: time.Date(2026, 10, 16, 20, 30, 0, 0, time.UTC),
+			want: time.Date(2026, 10, 17, 20, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "friday nights",
+			spec: "0 20 * * fri",
+			from: time.Date(2026, 10, 17, 12, 0, 0, 0, ny), // saturday
+			want: time.Date(2026, 10, 23, 20, 0, 0, 0, ny),
+		},
+		{
+			name: "day of month or weekday",
+			spec: "0 0 1 * mon",
+			from: time.Date(2026, 10, 27, 0, 0, 0, 0, time.UTC), // tuesday
+			want: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "sunday as 7",
+			spec: "0 12 * * 7",
+			from: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "across daylight saving",
+			spec: "0 9 * * *",
+			from: time.Date(2026, 10, 31, 10, 0, 0, 0, ny),
+			want: time.Date(2026, 11, 1, 9, 0, 0, 0, ny),
+		},
+		{
+			name: "never",
+			spec: "0 0 30 feb *",
+			from: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := ParseCron(tc.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.Next(tc.from); !got.Equal(tc.want) {
+				t.Errorf("Next(%v) = %v, want %v", tc.from, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseJobArgs(t *testing.T) {
+	tests := []struct {
+		argv    string
+		spec    string
+		action  string
+		args    string
+		wantErr bool
+	}{
+		{argv: "@daily say good morning", spec: "@daily", action: "say", args: "good morning"},
+		{argv: "0 20 * * fri rotation ctf", spec: "0 20 * * fri", action: "rotation", args: "ctf"},
+		{argv: "0 4 * * * restart", spec: "0 4 * * *", action: "restart"},
+		{argv: "0 4 * * *", wantErr: true},
+		{argv: "", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.argv, func(t *testing.T) {
+			spec, action, args, err := ParseJobArgs(strings.Fields(tc.argv))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseJobArgs() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if spec != tc.spec || action != tc.action || args != tc.args {
+				t.Errorf("ParseJobArgs() = %q, %q, %q, want %q, %q, %q", spec, action, args, tc.spec, tc.action, tc.args)
+			}
+		})
+	}
+}
+
+func TestJobValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		job  Job
+	}{
+		{name: "bad name", job: Job{Name: "a b", Server: "test1", Spec: "@daily", Action: JobRestart}},
+		{name: "no target", job: Job{Name: "a", Spec: "@daily", Action: JobRestart}},
+		{name: "bad schedule", job: Job{Name: "a", Server: "test1", Spec: "daily", Action: JobRestart}},
+		{name: "bad timezone", job: Job{Name: "a", Server: "test1", Spec: "@daily", Timezone: "Mars/Olympus", Action: JobRestart}},
+		{name: "bad action", job: Job{Name: "a", Server: "test1", Spec: "@daily", Action: "reboot"}},
+		{name: "nothing to say", job: Job{Name: "a", Server: "test1", Spec: "@daily", Action: JobSay}},
+		{name: "short rule", job: Job{Name: "a", Server: "test1", Spec: "@daily", Action: JobDisableRule, Args: "ab"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.job.Validate(); err == nil {
+				t.Error("Validate() = nil, want error")
+			}
+		})
+	}
+}
+
+// scheduleTestFrontends sets up frontends for jobs to run on. The first is
+// connected, with what's sent to it read from the channel.
+func scheduleTestFrontends(t *testing.T) <-chan []byte {
+	t.Helper()
+	useTestDatabase(t)
+	ours, theirs := net.Pipe()
+	t.Cleanup(func() { ours.Close(); theirs.Close() })
+	sent := make(chan []byte, 10)
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, err := theirs.Read(buf)
+			if err != nil {
+				return
+			}
+			sent <- bytes.Clone(buf[:n])
+		}
+	}()
+	saved := be.frontends
+	t.Cleanup(func() { be.frontends = saved })
+	be.frontends = []frontend.Frontend{
+		{Name: "test1", Owner: "leon@example.com", ChatGroup: "tdm", Connected: true, CurrentMap: "q2dm1"},
+		{Name: "test2", Owner: "Leon@example.com", ChatGroup: "tdm"},
+		{Name: "test3", Owner: "ada@example.com", ChatGroup: "tdm", Connected: true},
+	}
+	fe := &be.frontends[0]
+	fe.Connection = &ours
+	fe.Server = &be
+	fe.Log = log.New(io.Discard, "", 0)
+	fe.Path = t.TempDir()
+	fe.Rules = []*pb.Rule{{Uuid: "8c5e7a10-97c2-4a3e-9cbb-0d6f6b4d2a61"}, {Uuid: "1f0b4c55-1b40-4c10-a9a5-5f4a54f1e0b2"}}
+	return sent
+}
+
+func TestJobs(t *testing.T) {
+	sent := scheduleTestFrontends(t)
+	fe := &be.frontends[0]
+
+	restart := &Job{Owner: fe.Owner, Server: fe.Name, Name: "restart", Spec: "30 20 * * *", Timezone: "America/New_York", Action: JobRestart, Enabled: true}
+	hello := &Job{Owner: fe.Owner, Group: "tdm", Name: "hello", Spec: "@hourly", Action: JobSay, Args: "hi", Enabled: true}
+	rule := &Job{Owner: fe.Owner, Server: fe.Name, Name: "night", Spec: "0 21 * * *", Timezone: "America/New_York", Action: JobDisableRule, Args: "8c5e", Enabled: true}
+	off := &Job{Owner: fe.Owner, Server: fe.Name, Name: "off", Spec: "* * * * *", Action: JobRestart}
+	other := &Job{Owner: "ada@example.com", Group: "tdm", Name: "theirs", Spec: "* * * * *", Action: JobRestart, Enabled: true}
+	for _, j := range []*Job{restart, hello, rule, off, other} {
+		if err := SaveJob(j); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	jobs, err := LoadJobs(fe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, j := range jobs {
+		names = append(names, j.Name)
+	}
+	if strings.Join(names, ",") != "night,off,restart,hello" {
+		t.Errorf("LoadJobs() = %v, want the frontend's jobs then its group's", names)
+	}
+
+	ny, _ := time.LoadLocation("America/New_York")
+	from := time.Date(2026, 10, 16, 20, 29, 0, 0, ny)
+	ran, err := RunDueJobs(from, from.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ran) != 2 || ran[0].Name != "restart" || ran[1].Name != "theirs" {
+		t.Fatalf("RunDueJobs() at 20:30 ran %+v, want restart and theirs", ran)
+	}
+	select {
+	case msg := <-sent:
+		if !bytes.Contains(msg, []byte("gamemap q2dm1")) {
+			t.Errorf("restart sent %q, want gamemap q2dm1", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("restart didn't send anything")
+	}
+
+	from = time.Date(2026, 10, 16, 20, 59, 0, 0, ny)
+	ran, err = RunDueJobs(from, from.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ran) != 3 {
+		t.Fatalf("RunDueJobs() at 21:00 ran %d jobs, want 3", len(ran))
+	}
+	if !fe.Rules[0].GetDisabled() || fe.Rules[1].GetDisabled() {
+		t.Errorf("rules after the night job = %v", fe.Rules)
+	}
+
+	runs, err := LoadJobRuns(hello.ID, jobRunHistory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("hello ran %d times, want on test1 and test2", len(runs))
+	}
+	for _, r := range runs {
+		if want := r.Server == "test1"; r.Success != want {
+			t.Errorf("hello on %s success = %t, want %t (%s)", r.Server, r.Success, want, r.Output)
+		}
+	}
+	saved, _ := LoadJob(fe, "hello")
+	if saved.LastRun == 0 || !strings.Contains(saved.LastStatus, " on test") {
+		t.Errorf("hello last status = %q", saved.LastStatus)
+	}
+
+	rule.Enabled = false
+	if err := SaveJob(rule); err != nil {
+		t.Fatal(err)
+	}
+	if rule.NextRunTime() != "never" {
+		t.Errorf("disabled job next runs %s", rule.NextRunTime())
+	}
+	if err := DeleteJob(hello); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadJob(fe, "hello"); err == nil {
+		t.Error("LoadJob() after DeleteJob() = nil, want error")
+	}
+	if runs, _ := LoadJobRuns(hello.ID, jobRunHistory); len(runs) != 0 {
+		t.Errorf("%d runs left after DeleteJob()", len(runs))
+	}
+}
@@ -68,7 +68,7 @@ const (
 `
 	statusTemplate = `
 {{ printf "Frontend Status" | underline }}:
-Frontend:      {{ .IPAddress }}:{{ .Port }}
+Frontend:      {{ .Address }}
 Peer:          {{ .Connection.RemoteAddr.String | magenta }}
 Current map:   {{ .CurrentMap }}
 Previous map:  {{ .PreviousMap }}
@@ -77,11 +77,11 @@ Invites Used:  {{ .Invites.UseCount }}
 Teleports:     {{ .TeleportCount }}
 
 {{ if .PlayerCount }}
-num score name            vpn address
---- ----- --------------- --- ------------------------------
+num score rating name            vpn address
+--- ----- ------ --------------- --- ------------------------------
 {{ range .Players}}
 {{- if .IP -}}
-{{ printf "%3d" .ClientID}} {{ printf "%5d" .Frags}} {{ printf "%-15s" .Name }}  {{ .VPN | checkMark | red }}  {{ .IP -}}
+{{ printf "%3d" .ClientID}} {{ printf "%5d" .Frags}} {{ if .ProfileID }}{{ printf "%6.0f" .Rating }}{{ else }}{{ printf "%6s" "-" }}{{ end }} {{ printf "%-15s" .Name }}  {{ .VPN | checkMark | red }}  {{ .IP -}}
 {{- end -}}
 {{ end }}
 {{ else }}
@@ -91,10 +91,10 @@ No UDP clients
 
 	searchTemplate = `
 Search results for "{{ .Query }}"
-name             server              seen  address
----------------  ---------------  -------  -------------------------
+name             server              seen  profile  notes  address
+---------------  ---------------  -------  -------  -----  -------------------------
 {{ range .Results -}}
-{{ printf "%-15s" .Name }}  {{ printf "%-15s" .Server}}  {{ printf "%7s" .Ago }}  {{ .IP }}
+{{ printf "%-15s" .Name }}  {{ printf "%-15s" .Server}}  {{ printf "%7s" .Ago }}  {{ if .Profile }}{{ printf "%7d" .Profile }}{{ else }}{{ printf "%7s" "-" }}{{ end }}  {{ if .Notes }}{{ printf "%5d" .Notes }}{{ else }}{{ printf "%5s" "-" }}{{ end }}  {{ .IP }}
 {{ end }}
 `
 	rulesTemplate = `
@@ -112,6 +112,10 @@ name             server              seen  address
   DNS:      {{ .Hostname }}
   Version:  {{ .Version }}
   VPN:      {{ .VPN }}
+  Country:  {{ .Country }}
+  Network:  {{ if .ASN }}AS{{ .ASN }} {{ .ASNOrg }}{{ end }}
+  Profile:  {{ if .ProfileID }}#{{ .ProfileID }} ({{ printf "%.0f" (percent .ProfileScore) }}% confidence){{ end }}
+  Rating:   {{ if .ProfileID }}{{ printf "%.0f" .Rating }} ({{ .Frontend.Pool }}){{ end }}
 
 {{ printf "Userinfo Data" | underline}}:
 {{ range $k, $v := .UserinfoMap -}}
@@ -125,6 +129,83 @@ id        type     description
 {{ range .Rules -}}
 {{ slice .GetUuid 0 8}}  {{ printf "%-7s" .GetType }}  {{ join .GetDescription " " | truncate 53 }}
 {{ end }}
+{{ template "notes" .Notes }}`
+
+	notesTemplate = `{{ define "notes" }}{{ printf "Notes" | underline }}:
+{{ range . -}}
+{{ printf "%5d" .ID }}  {{ .Created | ago }} by {{ .Author }}{{ if not .Server }} (global){{ end }} on {{ .Target }}
+       {{ .Text }}
+{{ else -}}
+No notes
+{{ end }}{{ end }}`
+
+	profileTemplate = `
+{{ printf "Profile #%d" .ID | underline }}:
+  Name:       "{{ .Name }}"
+  First seen: {{ .FirstSeen | ago }}
+  Sightings:  {{ .Stats.Sightings }}
+  Play time:  {{ .Stats.PlayTime | duration }}
+  Frags:      {{ .Stats.Frags }}
+  Deaths:     {{ .Stats.Deaths }}
+  Suicides:   {{ .Stats.Suicides }}
+{{ template "links" (list "Aliases" .Aliases) }}
+{{- template "links" (list "Addresses" .Addresses) }}
+{{- template "links" (list "Cookies" .Cookies) }}
+{{- template "links" (list "Clients" .Clients) }}
+{{- template "links" (list "Servers" .Servers) }}
+{{- define "links" }}
+{{ index . 0 | underline }}:
+  conf  count      seen  value
+  ----  -----  --------  ---------------------------------------------
+{{ range index . 1 -}}
+  {{ printf "%3.0f%%" (percent .Confidence) }}  {{ printf "%5d" .Count }}  {{ printf "%8s" (.LastSeen | ago) }}  {{ .Value }}
+{{ end -}}
+{{ end -}}
+`
+
+	reportsTemplate = `
+{{ printf "Player reports" | underline }}:
+   id  status     when      reporter         target           reason
+-----  ---------  --------  ---------------  ---------------  ------------------------------
+{{ range . -}}
+{{ printf "%5d" .ID }}  {{ printf "%-9s" .Status }}  {{ printf "%-8s" (.Time | ago) }}  {{ printf "%-15.15s" .ReporterName }}  {{ printf "%-15.15s" .TargetName }}  {{ .Reason | truncate 30 }}
+{{ else -}}
+No reports
+{{ end -}}
+`
+
+	reservedTemplate = `
+{{ printf "Reserved names and tags" | underline }}:
+   id  kind  pattern               for                   after
+-----  ----  --------------------  --------------------  ------
+{{ range . -}}
+{{ printf "%5d" .ID }}  {{ printf "%-4s" .Kind }}  {{ printf "%-20.20s" .Pattern }}  {{ if .Cookie }}{{ printf "cookie:%-13.13s" .Cookie }}{{ else if .Account }}{{ printf "account:%-12.12s" .Account }}{{ else }}{{ printf "profile:%-12d" .Profile }}{{ end }}  {{ .Action }}
+{{ else -}}
+Nothing reserved
+{{ end -}}
+`
+
+	reportTemplate = `
+{{ printf "Report #%d" .ID | underline }}:
+  Status:    {{ .Status }}{{ if .Moderator }} ({{ .Moderator }}){{ end }}
+  Made:      {{ .Time | ago }} on {{ .Snapshot.Map }}
+  Reporter: "{{ .ReporterName }}" [{{ .Snapshot.Reporter.ClientID }}] {{ .ReporterIP }}{{ if .ReporterProfile }} profile #{{ .ReporterProfile }}{{ end }}
+  Target:   "{{ .TargetName }}" [{{ .Snapshot.Target.ClientID }}] {{ .Snapshot.Target.IP }}{{ if .TargetProfile }} profile #{{ .TargetProfile }}{{ end }}
+  Version:   {{ .Snapshot.Target.Version }}
+  Reason:    {{ .Reason }}
+  Note:      {{ .Note }}
+
+{{ printf "Rules matching target" | underline }}:
+{{ range .Snapshot.Rules }}  {{ . }}
+{{ end }}
+{{ printf "Recent chat" | underline }}:
+{{ range .Snapshot.Chat }}  {{ . }}
+{{ end }}
+{{ printf "Recent frags" | underline }}:
+{{ range .Snapshot.Frags }}  {{ . }}
+{{ end }}
+{{ printf "Target userinfo" | underline }}:
+  {{ .Snapshot.Userinfo }}
 `
 
 	serversTemplate = `
@@ -246,14 +327,22 @@ func sessionHandler(s ssh.Session) {
 		"connected": connectionIndicator,
 		"now":       time.Now().Unix,
 		"ago":       util.TimeAgo,
+		"percent":   percent,
+		"duration":  func(secs int64) string { return (time.Duration(secs) * time.Second).String() },
+		"list":      func(items ...any) []any { return items },
 	}
 
 	helpTmpl := template.Must(template.New("helpout").Funcs(funcmap).Parse(helpTemplate))
 	statusTmpl := template.Must(template.New("statusout").Funcs(funcmap).Parse(statusTemplate))
 	searchTmpl := template.Must(template.New("searchout").Funcs(funcmap).Parse(searchTemplate))
 	rulesTmpl := template.Must(template.New("rulesout").Funcs(funcmap).Parse(rulesTemplate))
-	whoisTmpl := template.Must(template.New("whoisout").Funcs(funcmap).Parse(whoisTemplate))
+	whoisTmpl := template.Must(template.Must(template.New("whoisout").Funcs(funcmap).Parse(whoisTemplate)).Parse(notesTemplate))
+	notesTmpl := template.Must(template.New("notesout").Funcs(funcmap).Parse(notesTemplate + `{{ template "notes" . }}`))
 	srvTmpl := template.Must(template.New("srvout").Funcs(funcmap).Parse(serversTemplate))
+	profileTmpl := template.Must(template.New("profileout").Funcs(funcmap).Parse(profileTemplate))
+	reportsTmpl := template.Must(template.New("reportsout").Funcs(funcmap).Parse(reportsTemplate))
+	reportTmpl := template.Must(template.New("reportout").Funcs(funcmap).Parse(reportTemplate))
+	reservedTmpl := template.Must(template.New("reservedout").Funcs(funcmap).Parse(reservedTemplate))
 
 	defer be.Logf(LogLevelInfo, "SSH user %q [%s] disconnected\n", s.User(), s.RemoteAddr().String())
 	defer s.Close()
@@ -364,15 +453,48 @@ func sessionHandler(s ssh.Session) {
 					{Cmd: "", Desc: ""},
 					{Cmd: "rcon <cmd>", Desc: "execute <cmd> on the remote server"},
 					{Cmd: "status", Desc: "display basic server status info"},
+					{Cmd: "balance", Desc: "suggest teams of even skill"},
 					{Cmd: "search <string>", Desc: "search player records (names, hosts, userinfo, etc)"},
 					{Cmd: "stuff <#> <cmd>", Desc: "force client # to do a command"},
 					{Cmd: "whois <#>", Desc: "show player info for client #"},
+					{Cmd: "profile <#>", Desc: "show the identity profile for client #"},
+					{Cmd: "profile show <id>", Desc: "show identity profile <id>"},
+					{Cmd: "profile merge <id> <id>", Desc: "merge the first profile into the second"},
+					{Cmd: "reports [all]", Desc: "list open player reports, or all of them"},
+					{Cmd: "report <id>", Desc: "show player report <id>"},
+					{Cmd: "report claim <id>", Desc: "take player report <id> to look into"},
+					{Cmd: "report release <id>", Desc: "put a claimed report back in the queue"},
+					{Cmd: "report resolve <id> [note]", Desc: "close player report <id>, action taken"},
+					{Cmd: "report dismiss <id> [note]", Desc: "close player report <id>, no action"},
+					{Cmd: "reserved", Desc: "list reserved names and clan tags"},
+					{Cmd: "reserve name|tag <who> [kick] <pattern>", Desc: "reserve for cookie:<value>, profile:<id> or account:<email>"},
+					{Cmd: "unreserve <id>", Desc: "remove reserved name or tag <id>"},
+					{Cmd: "adminlog", Desc: "show recent in-game admin actions"},
+					{Cmd: "notes <#|who>", Desc: "show notes on client # or profile:, cookie:, ip:"},
+					{Cmd: "note [global] <#|who> <text>", Desc: "leave a note on a player"},
+					{Cmd: "unnote <id>", Desc: "remove note <id>"},
+					{Cmd: "vpn [overrides | <ip>]", Desc: "show cached VPN lookups"},
+					{Cmd: "vpn allow|deny|clear <ip>", Desc: "never/always treat <ip> as a VPN, or let providers decide"},
+					{Cmd: "vpn purge [<ip>|all]", Desc: "forget stale (or all) cached VPN lookups"},
+					{Cmd: "rotations", Desc: "list map rotations, * is active"},
+					{Cmd: "rotation show <name>", Desc: "show the maps in a rotation"},
+					{Cmd: "rotation create|edit <name> <maps>", Desc: "set maps, comma separated: name [min] [max] [dmflags]"},
+					{Cmd: "rotation move <name> <#> <#>", Desc: "move a map to another spot"},
+					{Cmd: "rotation shuffle|reverse <name>", Desc: "shuffle the maps or flip the direction"},
+					{Cmd: "rotation use|delete <name>", Desc: "make a rotation active, or remove it"},
+					{Cmd: "schedules", Desc: "list scheduled jobs and when they run next"},
+					{Cmd: "schedule add|group <name> <when> <action> [args]", Desc: "run on this server or its chat group, <when> is cron or @daily etc"},
+					{Cmd: "schedule tz <name> <zone>", Desc: "set a job's timezone, like America/New_York"},
+					{Cmd: "schedule enable|disable|delete|run <name>", Desc: "turn a job on or off, remove it, or run it now"},
+					{Cmd: "schedule history <name>", Desc: "show a job's recent runs"},
 					{Cmd: "", Desc: ""},
 					{Cmd: "say", Desc: "broadcasts <text> to all players"},
 					{Cmd: "consolesay", Desc: "send print to server from console"},
 					{Cmd: "sayplayer <id> <msg>", Desc: "say something to player #id"},
+					{Cmd: "gsay [msg]", Desc: "talk on the chat bridge, show recent messages"},
 					{Cmd: "", Desc: ""},
 					{Cmd: "kick <#> [msg]", Desc: "kick player # with msg"},
+					{Cmd: "ban <#> [msg]", Desc: "ban player # by cookie and ip"},
 					{Cmd: "mute <#> <secs>", Desc: "mute player # for secs seconds"},
 					{Cmd: "stifle <#> <secs>", Desc: "stifle player # for secs seconds"},
 					{Cmd: "", Desc: ""},
@@ -404,12 +526,545 @@ func sessionHandler(s ssh.Session) {
 				sshterm.Printf("whois: client_id %q not in use\n", c.argv[0])
 				continue
 			}
+			notes, err := PlayerNotes(activeFE, s.User(), &p)
+			if err != nil {
+				sshterm.Printf("whois: %v\n", err)
+			}
+			data := struct {
+				frontend.Player
+				Notes []Note
+			}{p, notes}
 			var msg bytes.Buffer
-			if err := whoisTmpl.Execute(&msg, p); err != nil {
+			if err := whoisTmpl.Execute(&msg, data); err != nil {
 				log.Println("error executing whois template:", err)
 			}
 			sshterm.Println(msg.String())
 
+		} else if c.command == "profile" {
+			if c.argc == 0 {
+				sshterm.Println("Usage: profile <#> | show <id> | merge <from_id> <into_id>")
+				continue
+			}
+			var id int64
+			if c.argv[0] == "merge" {
+				if c.argc < 3 {
+					sshterm.Println("Usage: profile merge <from_id> <into_id>")
+					continue
+				}
+				from, err1 := strconv.ParseInt(c.argv[1], 10, 64)
+				into, err2 := strconv.ParseInt(c.argv[2], 10, 64)
+				if err1 != nil || err2 != nil {
+					sshterm.Println("profile merge: invalid profile id")
+					continue
+				}
+				fromProfile, err1 := LoadProfile(from)
+				intoProfile, err2 := LoadProfile(into)
+				if err1 != nil || err2 != nil {
+					sshterm.Println("profile merge: unknown profile id")
+					continue
+				}
+				// only allow merging players that have been on this server
+				if !fromProfile.SeenOn(activeFE.Name) || !intoProfile.SeenOn(activeFE.Name) {
+					sshterm.Printf("profile merge: both profiles need to have played on %s\n", activeFE.Name)
+					continue
+				}
+				if err := MergeProfiles(fromProfile.ID, intoProfile.ID); err != nil {
+					sshterm.Printf("profile merge: %v\n", err)
+					continue
+				}
+				be.Logf(LogLevelInfo, "SSH user %q merged profile %d into %d\n", s.User(), fromProfile.ID, intoProfile.ID)
+				sshterm.Printf("Profile %d merged into %d\n", fromProfile.ID, intoProfile.ID)
+				continue
+			} else if c.argv[0] == "show" {
+				if c.argc < 2 {
+					sshterm.Println("Usage: profile show <id>")
+					continue
+				}
+				id, err = strconv.ParseInt(c.argv[1], 10, 64)
+				if err != nil {
+					sshterm.Printf("profile: invalid profile id %q\n", c.argv[1])
+					continue
+				}
+			} else {
+				pid, err := strconv.Atoi(c.argv[0])
+				if err != nil || !activeFE.PlayerSlotInUse(pid) {
+					sshterm.Printf("profile: invalid client_id %q\n", c.argv[0])
+					continue
+				}
+				id = activeFE.Players[pid].ProfileID
+				if id == 0 {
+					sshterm.Printf("profile: client_id %q not linked to a profile yet\n", c.argv[0])
+					continue
+				}
+			}
+			pr, err := LoadProfile(id)
+			if err != nil {
+				sshterm.Printf("profile: %v\n", err)
+				continue
+			}
+			// by id only shows profiles that have played here
+			if c.argv[0] == "show" && !pr.SeenOn(activeFE.Name) && !BackendAdmin(s.User()) {
+				sshterm.Printf("profile: no profile %d\n", id)
+				continue
+			}
+			var msg bytes.Buffer
+			if err := profileTmpl.Execute(&msg, pr); err != nil {
+				log.Println("error executing profile template:", err)
+			}
+			sshterm.Println(msg.String())
+
+		} else if c.command == "reports" {
+			reports, err := LoadReports(activeFE.Name, c.argc > 0 && c.argv[0] == "all", 50)
+			if err != nil {
+				sshterm.Printf("reports: %v\n", err)
+				continue
+			}
+			var msg bytes.Buffer
+			if err := reportsTmpl.Execute(&msg, reports); err != nil {
+				log.Println("error executing reports template:", err)
+			}
+			sshterm.Println(msg.String())
+
+		} else if c.command == "report" {
+			if c.argc == 0 {
+				sshterm.Println("Usage: report <id> | claim <id> | release <id> | resolve <id> [note] | dismiss <id> [note]")
+				continue
+			}
+			action, arg := "", c.argv[0]
+			if c.argc > 1 {
+				action, arg = c.argv[0], c.argv[1]
+			}
+			id, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil {
+				sshterm.Printf("report: invalid report id %q\n", arg)
+				continue
+			}
+			r, err := LoadReport(id)
+			if err != nil || r.Server != activeFE.Name {
+				sshterm.Printf("report: no report %d on %s\n", id, activeFE.Name)
+				continue
+			}
+			if action != "" {
+				status, ok := ReportActions[action]
+				if !ok {
+					sshterm.Printf("report: unknown action %q\n", action)
+					continue
+				}
+				note := ""
+				if c.argc > 2 {
+					note = strings.Join(c.argv[2:], " ")
+				}
+				r, err = UpdateReport(id, status, s.User(), note)
+				if err != nil {
+					sshterm.Printf("report: %v\n", err)
+					continue
+				}
+				be.Logf(LogLevelInfo, "SSH user %q set report %d to %s\n", s.User(), r.ID, r.Status)
+				sshterm.Printf("Report %d is now %s\n", r.ID, r.Status)
+				continue
+			}
+			var msg bytes.Buffer
+			if err := reportTmpl.Execute(&msg, r); err != nil {
+				log.Println("error executing report template:", err)
+			}
+			sshterm.Println(msg.String())
+
+		} else if c.command == "reserved" {
+			reservations, err := LoadReservations(activeFE.Name)
+			if err != nil {
+				sshterm.Printf("reserved: %v\n", err)
+				continue
+			}
+			var msg bytes.Buffer
+			if err := reservedTmpl.Execute(&msg, reservations); err != nil {
+				log.Println("error executing reserved template:", err)
+			}
+			sshterm.Println(msg.String())
+
+		} else if c.command == "reserve" {
+			if c.argc < 3 {
+				sshterm.Println("Usage: reserve name|tag <cookie:value|profile:id|account:email> [kick] <pattern>")
+				continue
+			}
+			r := Reservation{
+				Server: activeFE.Name,
+				Kind:   c.argv[0],
+				Action: ReservedRename,
+				Owner:  s.User(),
+			}
+			if err := r.SetIdentity(c.argv[1]); err != nil {
+				sshterm.Printf("reserve: %v\n", err)
+				continue
+			}
+			pattern := c.argv[2:]
+			if pattern[0] == ReservedKick && len(pattern) > 1 {
+				r.Action, pattern = ReservedKick, pattern[1:]
+			}
+			r.Pattern = strings.Join(pattern, " ")
+			if err := SaveReservation(&r); err != nil {
+				sshterm.Printf("reserve: %v\n", err)
+				continue
+			}
+			be.Logf(LogLevelInfo, "SSH user %q reserved %s %q on %s\n", s.User(), r.Kind, r.Pattern, activeFE.Name)
+			sshterm.Printf("Reserved %s %q (%d)\n", r.Kind, r.Pattern, r.ID)
+
+		} else if c.command == "unreserve" {
+			if c.argc == 0 {
+				sshterm.Println("Usage: unreserve <id>")
+				continue
+			}
+			id, err := strconv.ParseInt(c.argv[0], 10, 64)
+			if err != nil {
+				sshterm.Printf("unreserve: invalid id %q\n", c.argv[0])
+				continue
+			}
+			if err := DeleteReservation(activeFE.Name, id); err != nil {
+				sshterm.Printf("unreserve: %v\n", err)
+				continue
+			}
+			be.Logf(LogLevelInfo, "SSH user %q removed reservation %d on %s\n", s.User(), id, activeFE.Name)
+			sshterm.Printf("Reservation %d removed\n", id)
+
+		} else if c.command == "adminlog" {
+			actions, err := LoadAdminLog(activeFE.Name, 25)
+			if err != nil {
+				sshterm.Printf("adminlog: %v\n", err)
+				continue
+			}
+			if len(actions) == 0 {
+				sshterm.Println("No admin actions")
+				continue
+			}
+			for _, a := range actions {
+				sshterm.Printf("%s  %-30s %-6s %-20q %s\n", time.Unix(a.Time, 0).Format(time.DateTime), a.Actor, a.Action, a.Target, a.Detail)
+			}
+
+		} else if c.command == "notes" || c.command == "note" {
+			global := c.command == "note" && c.argc > 0 && c.argv[0] == "global"
+			args := c.argv
+			if global {
+				args = args[1:]
+			}
+			if len(args) == 0 || (c.command == "note" && len(args) < 2) {
+				sshterm.Println("Usage: notes <#|who> or note [global] <#|who> <text>, who is profile:<id>, cookie:<value> or ip:<address>")
+				continue
+			}
+			n := Note{Server: activeFE.Name, Author: s.User(), Text: strings.Join(args[1:], " ")}
+			if global {
+				n.Server = ""
+			}
+			var p *frontend.Player
+			if id, err := strconv.Atoi(args[0]); err == nil {
+				if !activeFE.PlayerSlotInUse(id) {
+					sshterm.Printf("%s: client_id %q not in use\n", c.command, args[0])
+					continue
+				}
+				p = &activeFE.Players[id]
+				n.SetPlayer(p)
+			} else if err := n.SetTarget(args[0]); err != nil {
+				sshterm.Printf("%s: %v\n", c.command, err)
+				continue
+			}
+			if !noteAccess(activeFE, s.User()) {
+				sshterm.Printf("%s: you can't see notes on %s\n", c.command, activeFE.Name)
+				continue
+			}
+			if c.command == "notes" {
+				notes, err := VisibleNotes(activeFE, s.User(), n.Profile, n.Cookie, n.IP)
+				if p != nil {
+					notes, err = PlayerNotes(activeFE, s.User(), p)
+				}
+				if err != nil {
+					sshterm.Printf("notes: %v\n", err)
+					continue
+				}
+				var msg bytes.Buffer
+				if err := notesTmpl.Execute(&msg, notes); err != nil {
+					log.Println("error executing notes template:", err)
+				}
+				sshterm.Println(msg.String())
+				continue
+			}
+			if err := SaveNote(&n); err != nil {
+				sshterm.Printf("note: %v\n", err)
+				continue
+			}
+			be.Logf(LogLevelInfo, "SSH user %q added note %d on %s\n", s.User(), n.ID, n.Target())
+			sshterm.Printf("Note %d added to %s\n", n.ID, n.Target())
+
+		} else if c.command == "unnote" {
+			if c.argc != 1 {
+				sshterm.Println("Usage: unnote <id>")
+				continue
+			}
+			id, err := strconv.ParseInt(c.argv[0], 10, 64)
+			if err != nil {
+				sshterm.Printf("unnote: invalid id %q\n", c.argv[0])
+				continue
+			}
+			if err := DeleteNote(id, s.User()); err != nil {
+				sshterm.Printf("unnote: %v\n", err)
+				continue
+			}
+			be.Logf(LogLevelInfo, "SSH user %q removed note %d\n", s.User(), id)
+			sshterm.Printf("Note %d removed\n", id)
+
+		} else if c.command == "vpn" {
+			if !BackendAdmin(s.User()) {
+				sshterm.Println("vpn: only backend admins can manage the VPN cache")
+				continue
+			}
+			sub := ""
+			if c.argc > 0 {
+				sub = c.argv[0]
+			}
+			switch {
+			case sub == "" || sub == "overrides":
+				records, err := LoadVPNRecords(sub == "overrides", 25)
+				if err != nil {
+					sshterm.Printf("vpn: %v\n", err)
+					continue
+				}
+				if len(records) == 0 {
+					sshterm.Println("Nothing cached")
+					continue
+				}
+				for _, r := range records {
+					sshterm.Printf("%-40s %-5t %-10s %-6s %6d  %s\n", r.IP, r.IsVPN(), r.Provider, r.Override, r.Hits, time.Unix(r.Expires, 0).Format(time.DateTime))
+				}
+			case (sub == "allow" || sub == "deny" || sub == "clear") && c.argc == 2:
+				override := map[string]string{"allow": VPNOverrideClean, "deny": VPNOverrideVPN, "clear": VPNOverrideNone}[sub]
+				if err := SetVPNOverride(c.argv[1], override, s.User()); err != nil {
+					sshterm.Printf("vpn: %v\n", err)
+					continue
+				}
+				be.Logf(LogLevelInfo, "SSH user %q set vpn override %q for %s\n", s.User(), override, c.argv[1])
+				sshterm.Printf("VPN override for %s: %s\n", c.argv[1], sub)
+			case sub == "purge" && c.argc <= 2:
+				target := ""
+				if c.argc == 2 {
+					target = c.argv[1]
+				}
+				n, err := PurgeVPNCache(target)
+				if err != nil {
+					sshterm.Printf("vpn: %v\n", err)
+					continue
+				}
+				be.Logf(LogLevelInfo, "SSH user %q purged %d vpn cache entries (%q)\n", s.User(), n, target)
+				sshterm.Printf("Purged %d cached lookups\n", n)
+			case c.argc == 1:
+				r, err := LoadVPNRecord(sub)
+				if err != nil {
+					sshterm.Printf("vpn: %v\n", err)
+					continue
+				}
+				if r == nil {
+					sshterm.Printf("%s isn't cached\n", sub)
+					continue
+				}
+				sshterm.Printf("%s vpn: %t, answered by %q, asked %d times, stale %s\n", r.IP, r.VPN, r.Provider, r.Hits, time.Unix(r.Expires, 0).Format(time.DateTime))
+				if r.Override != VPNOverrideNone {
+					sshterm.Printf("overridden as %q by %s\n", r.Override, r.Author)
+				}
+			default:
+				sshterm.Println("Usage: vpn [overrides | <ip> | allow|deny|clear <ip> | purge [<ip>|all]]")
+			}
+
+		} else if c.command == "rotations" {
+			rotations, err := LoadRotations(activeFE.Name)
+			if err != nil {
+				sshterm.Printf("rotations: %v\n", err)
+				continue
+			}
+			if len(rotations) == 0 {
+				sshterm.Println("No map rotations")
+				continue
+			}
+			for _, r := range rotations {
+				active, dir := " ", "forward"
+				if r.Active {
+					active = "*"
+				}
+				if r.Reverse {
+					dir = "reverse"
+				}
+				sshterm.Printf("%s %-20s %3d maps  %-7s  %d rotations\n", active, r.Name, len(r.Maps), dir, r.Iterations)
+			}
+
+		} else if c.command == "rotation" {
+			if c.argc < 2 {
+				sshterm.Println("Usage: rotation show|use|shuffle|reverse|delete <name>, rotation create|edit <name> <maps>, rotation move <name> <#> <#>")
+				continue
+			}
+			sub, name := c.argv[0], c.argv[1]
+			if sub == "create" || sub == "edit" {
+				if c.argc < 3 {
+					sshterm.Printf("Usage: rotation %s <name> <map> [min] [max] [dmflags], <map>...\n", sub)
+					continue
+				}
+				maps, err := ParseRotationMaps(strings.Join(c.argv[2:], " "))
+				if err != nil {
+					sshterm.Printf("rotation: %v\n", err)
+					continue
+				}
+				r := &Rotation{Name: name}
+				if sub == "edit" {
+					if r, err = LoadRotation(activeFE.Name, name); err != nil {
+						sshterm.Printf("rotation: %v\n", err)
+						continue
+					}
+				} else if _, err := LoadRotation(activeFE.Name, name); err == nil {
+					sshterm.Printf("rotation: %q already exists, edit it instead\n", name)
+					continue
+				}
+				r.Maps = maps
+				if err := SaveRotation(activeFE, r); err != nil {
+					sshterm.Printf("rotation: %v\n", err)
+					continue
+				}
+				be.Logf(LogLevelInfo, "SSH user %q saved rotation %q on %s\n", s.User(), name, activeFE.Name)
+				sshterm.Printf("Rotation %q saved, %d maps\n", name, len(maps))
+				continue
+			}
+			r, err := LoadRotation(activeFE.Name, name)
+			if err != nil {
+				sshterm.Printf("rotation: %v\n", err)
+				continue
+			}
+			switch sub {
+			case "show":
+				for i, m := range r.Maps {
+					at := " "
+					if r.Active && int32(i) == r.Position {
+						at = ">"
+					}
+					sshterm.Printf("%s %2d  %s\n", at, i+1, m)
+				}
+				continue
+			case "use":
+				err = ActivateRotation(activeFE, name)
+			case "delete":
+				err = DeleteRotation(activeFE, name)
+			case "shuffle", "reverse", "move":
+				if sub == "shuffle" {
+					r.Shuffle()
+				} else if sub == "reverse" {
+					r.Reverse = !r.Reverse
+				} else {
+					var from, to int
+					if c.argc == 4 {
+						from, _ = strconv.Atoi(c.argv[2])
+						to, _ = strconv.Atoi(c.argv[3])
+					}
+					if err := r.MoveMap(from, to); err != nil {
+						sshterm.Printf("rotation: %v\n", err)
+						continue
+					}
+				}
+				err = SaveRotation(activeFE, r)
+			default:
+				sshterm.Printf("rotation: unknown command %q\n", sub)
+				continue
+			}
+			if err != nil {
+				sshterm.Printf("rotation: %v\n", err)
+				continue
+			}
+			be.Logf(LogLevelInfo, "SSH user %q did rotation %s %q on %s\n", s.User(), sub, name, activeFE.Name)
+			sshterm.Printf("Rotation %q: %s done\n", name, sub)
+
+		} else if c.command == "schedules" {
+			jobs, err := LoadJobs(activeFE)
+			if err != nil {
+				sshterm.Printf("schedules: %v\n", err)
+				continue
+			}
+			if len(jobs) == 0 {
+				sshterm.Println("No scheduled jobs")
+				continue
+			}
+			for _, j := range jobs {
+				sshterm.Printf("%-16s %-16s %-20s %s %s\n", j.Name, j.Target(), j.Spec, j.Action, j.Args)
+				sshterm.Printf("%16s next: %s  last: %s\n", "", j.NextRunTime(), j.LastStatus)
+			}
+
+		} else if c.command == "schedule" {
+			if c.argc < 2 {
+				sshterm.Println("Usage: schedule add|group <name> <when> <action> [args], schedule tz <name> <zone>, schedule enable|disable|delete|run|history <name>")
+				continue
+			}
+			sub, name := c.argv[0], c.argv[1]
+			if sub == "add" || sub == "group" {
+				spec, action, args, err := ParseJobArgs(c.argv[2:])
+				if err != nil {
+					sshterm.Printf("Usage: schedule %s <name> <when> <action> [args], %v\n", sub, err)
+					continue
+				}
+				j := &Job{Owner: activeFE.Owner, Server: activeFE.Name, Name: name, Spec: spec, Action: action, Args: args, Enabled: true, Author: s.User()}
+				if sub == "group" {
+					if activeFE.ChatGroup == "" {
+						sshterm.Printf("schedule: %s isn't in a chat group\n", activeFE.Name)
+						continue
+					}
+					j.Server, j.Group = "", activeFE.ChatGroup
+				}
+				if old, err := LoadJob(activeFE, name); err == nil && old.Target() == j.Target() {
+					j.Timezone, j.Enabled = old.Timezone, old.Enabled
+				}
+				if err := SaveJob(j); err != nil {
+					sshterm.Printf("schedule: %v\n", err)
+					continue
+				}
+				be.Logf(LogLevelInfo, "SSH user %q saved job %q on %s\n", s.User(), name, j.Target())
+				sshterm.Printf("Job %q saved, next run %s\n", name, j.NextRunTime())
+				continue
+			}
+			j, err := LoadJob(activeFE, name)
+			if err != nil {
+				sshterm.Printf("schedule: %v\n", err)
+				continue
+			}
+			switch sub {
+			case "history":
+				runs, err := LoadJobRuns(j.ID, jobRunHistory)
+				if err != nil {
+					sshterm.Printf("schedule: %v\n", err)
+					continue
+				}
+				for _, r := range runs {
+					status := "ok"
+					if !r.Success {
+						status = "failed"
+					}
+					sshterm.Printf("%s  %-16s %-6s %s\n", time.Unix(r.Time, 0).Format("2006-01-02 15:04"), r.Server, status, r.Output)
+				}
+				continue
+			case "run":
+				err = RunJob(j)
+			case "delete":
+				err = DeleteJob(j)
+			case "enable", "disable", "tz":
+				if sub == "tz" {
+					if c.argc != 3 {
+						sshterm.Println("Usage: schedule tz <name> <zone>")
+						continue
+					}
+					j.Timezone = c.argv[2]
+				} else {
+					j.Enabled = sub == "enable"
+				}
+				err = SaveJob(j)
+			default:
+				sshterm.Printf("schedule: unknown command %q\n", sub)
+				continue
+			}
+			if err != nil {
+				sshterm.Printf("schedule: %v\n", err)
+				continue
+			}
+			be.Logf(LogLevelInfo, "SSH user %q did schedule %s %q on %s\n", s.User(), sub, name, j.Target())
+			sshterm.Printf("Job %q: %s done\n", name, sub)
+
 		} else if c.command == "stuff" {
 			if len(c.args) == 0 {
 				sshterm.Println("Usage: stuff <id> <command>")
@@ -447,6 +1102,45 @@ func sessionHandler(s ssh.Session) {
 			}
 			sshterm.Println(msg.String())
 
+		} else if c.command == "balance" {
+			red, blue := BalanceTeams(ratedPlayers(fe))
+			if len(red) == 0 {
+				sshterm.Println("nobody rated to balance")
+				continue
+			}
+			for _, team := range []struct {
+				name    string
+				players []*frontend.Player
+			}{{"red", red}, {"blue", blue}} {
+				var total float64
+				for _, p := range team.players {
+					total += p.Rating
+				}
+				sshterm.Printf("%s (avg %.0f):\n", team.name, total/float64(max(1, len(team.players))))
+				for _, p := range team.players {
+					sshterm.Printf("  [%d] %-15s %6.0f\n", p.ClientID, p.Name, p.Rating)
+				}
+			}
+
+		} else if c.command == "gsay" {
+			if activeFE.ChatGroup == "" {
+				sshterm.Printf("gsay: %s isn't in a chat group\n", activeFE.Name)
+				continue
+			}
+			if c.argc == 0 {
+				for _, m := range BridgeHistory(activeFE.Owner, activeFE.ChatGroup) {
+					sshterm.Printf("%s %s\n", time.Unix(m.Time, 0).Format(time.TimeOnly), m.String())
+				}
+				continue
+			}
+			RelayChat(BridgeMessage{
+				Owner:  activeFE.Owner,
+				Group:  activeFE.ChatGroup,
+				Origin: BridgeOriginConsole,
+				Name:   s.User(),
+				Text:   c.args,
+			})
+
 		} else if c.command == "consolesay" {
 			if len(c.args) == 0 {
 				sshterm.Println("Usage: consolesay <message>")
@@ -498,6 +1192,33 @@ func sessionHandler(s ssh.Session) {
 			}
 			KickPlayer(fe, p, strings.Join(c.argv[1:], " "))
 
+		} else if c.command == "ban" {
+			if len(c.args) == 0 {
+				sshterm.Println("Usage: ban <id> [message]")
+				continue
+			}
+			id, err := strconv.Atoi(c.argv[0])
+			if err != nil {
+				sshterm.Printf("ban: invalid client_id %q\n", c.argv[0])
+				continue
+			}
+			if id < 0 || id >= len(activeFE.Players) {
+				sshterm.Printf("ban: invalid client_id %q\n", c.argv[0])
+				continue
+			}
+			p := &activeFE.Players[id]
+			if p.ConnectTime == 0 {
+				sshterm.Printf("ban: client_id %q not in use\n", c.argv[0])
+				continue
+			}
+			rules, err := BanPlayer(fe, p, s.User(), strings.Join(c.argv[1:], " "))
+			if err != nil {
+				sshterm.Printf("ban: %v\n", err)
+			}
+			for _, r := range rules {
+				sshterm.Printf("Rule %q added\n", r.GetUuid())
+			}
+
 		} else if c.command == "mute" {
 			if len(c.args) == 0 { // list all mutes
 				sshterm.Println("Active mutes:")
@@ -536,6 +1257,14 @@ func sessionHandler(s ssh.Session) {
 				continue
 			}
 			MutePlayer(fe, p, secs)
+			var expires int64
+			if secs > 0 {
+				expires = time.Now().Unix() + int64(secs)
+			}
+			err = RecordOffense(p, OffenseMute, "", "ssh: "+s.User(), expires)
+			if err != nil {
+				be.Logln(LogLevelInfo, err)
+			}
 
 		} else if c.command == "stifle" || c.command == "stifled" || c.command == "stifles" {
 			if len(c.args) == 0 { // list all mutes
@@ -607,6 +1336,7 @@ func sessionHandler(s ssh.Session) {
 				sshterm.Printf("database.Search(%q): %v\n", c.args, err)
 				continue
 			}
+			annotateSearch(activeFE, s.User(), res)
 
 			var msg bytes.Buffer
 			so := SearchResultsOutput{
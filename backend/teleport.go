@@ -22,13 +22,15 @@ const (
 	teleportTemplate = `
 Teleporting between servers
 Active servers:
-  name                  map          players
-  --------------------- ------------ ----------------------------------------
-  {{ if .ActiveServers }}{{ range .ActiveServers }}{{ printf "%-21s" .GetName}} {{printf "%-12s" .GetMap }} {{ .GetPlayers}}{{ end }}{{ end }} 
+  name                  map          rating players
+  --------------------- ------------ ------ ---------------------------------
+  {{ if .ActiveServers }}{{ range .ActiveServers }}{{ printf "%-21s" .GetName}} {{printf "%-12s" .GetMap }} {{ if .GetRating }}{{ printf "%6d" .GetRating }}{{ else }}{{ printf "%6s" "-" }}{{ end }} {{ .GetPlayers}}{{ end }}{{ end }} 
 
 Empty servers:
   {{ .GetEmptyServers }}
-`
+{{ if .GetSuggested }}
+Closest to your skill: {{ .GetSuggested }}
+{{ end }}`
 )
 
 // Build the proto to render using the output template.
@@ -41,7 +43,7 @@ func (s *Backend) teleportDestinations() (*pb.TeleportReply, error) {
 		}
 		var dest pb.TeleportDestination
 		dest.Name = fe.Name
-		dest.Address = fmt.Sprintf("%s:%d", fe.IPAddress, fe.Port)
+		dest.Address = fe.Address()
 		dest.Map = fe.CurrentMap
 
 		var players []string
@@ -51,6 +53,7 @@ func (s *Backend) teleportDestinations() (*pb.TeleportReply, error) {
 			}
 		}
 		dest.Players = strings.Join(players, ", ")
+		dest.Rating = int32(averageRating(&fe))
 		if dest.Players != "" {
 			reply.ActiveServers = append(reply.ActiveServers, &dest)
 		} else {
@@ -91,6 +94,7 @@ func Teleport(fe *frontend.Frontend) {
 			sv.Logf(LogLevelInfo, "error collecting teleport destinations: %v\n", err)
 			return
 		}
+		dests.Suggested = suggestServer(p)
 		var rendered bytes.Buffer
 		if err := teleTmpl.Execute(&rendered, dests); err != nil {
 			sv.Logf(LogLevelInfo, "error executing teleport template: %v\n", err)
@@ -101,10 +105,10 @@ func Teleport(fe *frontend.Frontend) {
 
 	for i, f := range sv.frontends {
 		if strings.EqualFold(f.Name, target) {
-			notice := fmt.Sprintf("Teleporting %s to %s [%s:%d]\n", p.Name, f.Name, f.IPAddress, f.Port)
+			notice := fmt.Sprintf("Teleporting %s to %s [%s]\n", p.Name, f.Name, f.Address())
 			SayEveryone(fe, PRINT_CHAT, notice)
 
-			cmd := fmt.Sprintf("connect %s:%d\n", f.IPAddress, f.Port)
+			cmd := fmt.Sprintf("connect %s\n", f.Address())
 			StuffPlayer(fe, p, cmd)
 			p.LastTeleport = time.Now().Unix()
 			p.Teleports++
@@ -0,0 +1,481 @@
+// Map voting. When a match ends players pick the next map from the few
+// coming up in the active rotation with "vote <number>", and the one with
+// the most votes is played when time's up (the rotation's own pick on a tie
+// or if nobody votes).
+//
+// If the frontend allows it, players can also call a vote mid-match:
+//
+//	vote skip         change to the next map in the rotation now
+//	vote next <map>   play a map from the rotation when this match ends
+//
+// and everyone else answers with "vote yes" or "vote no". A called vote
+// passes once enough of the players who could vote when it started (the
+// quorum) say yes and more say yes than no. Only one vote runs at a time,
+// there's a cooldown between called votes, and players who just connected
+// can't vote.
+package backend
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/packetflinger/q2admind/frontend"
+	"github.com/packetflinger/q2admind/maprotator"
+
+	pb "github.com/packetflinger/q2admind/proto"
+)
+
+// Kinds of votes
+const (
+	VoteMap  = "map"  // end of match, pick from the candidates
+	VoteSkip = "skip" // called, change maps now
+	VoteNext = "next" // called, play a map next
+)
+
+const (
+	voteDefaultCandidates = 3
+	voteDefaultDuration   = 20  // seconds
+	voteDefaultQuorum     = 50  // percent
+	voteDefaultCooldown   = 300 // seconds
+	voteDefaultMinTime    = 60  // seconds
+	voteMapDelay          = 5   // seconds between a match ending and changing maps
+)
+
+// MapVote is a vote running on a frontend
+type MapVote struct {
+	Kind     string
+	Caller   string      // who called it, empty at the end of a match
+	Maps     []*pb.Map   // the candidates, or the map a next vote is for
+	Ballots  map[int]int // client ID to the candidate picked (from 0), or 1 for yes and 0 for no
+	Eligible int         // players who could vote when it started
+	Ends     int64
+	timer    *time.Timer
+}
+
+// votes running on each frontend, when votes were last called, the maps
+// next votes passed for and map changes waiting to happen
+var mapVotes = struct {
+	sync.Mutex
+	running  map[string]*MapVote
+	called   map[string]int64
+	queued   map[string]*pb.Map
+	changing map[string]*time.Timer
+}{
+	running:  make(map[string]*MapVote),
+	called:   make(map[string]int64),
+	queued:   make(map[string]*pb.Map),
+	changing: make(map[string]*time.Timer),
+}
+
+// voteSetting is a frontend's vote setting, or the default if it's not set
+func voteSetting(value int32, def int) int {
+	if value > 0 {
+		return int(value)
+	}
+	return def
+}
+
+// ParseVoteConfig builds a frontend's vote settings as they're sent from the
+// website. Numbers that are missing or invalid use the default.
+func ParseVoteConfig(enabled bool, call bool, candidates, duration, quorum, cooldown, minTime string) *pb.VoteConfig {
+	number := func(in string, limit int) int32 {
+		n, err := strconv.Atoi(strings.TrimSpace(in))
+		if err != nil || n < 0 || n > limit {
+			return 0
+		}
+		return int32(n)
+	}
+	return &pb.VoteConfig{
+		Enabled:    enabled,
+		Call:       call,
+		Candidates: number(candidates, 9),
+		Duration:   number(duration, 300),
+		Quorum:     number(quorum, 100),
+		Cooldown:   number(cooldown, 86400),
+		MinTime:    number(minTime, 3600),
+	}
+}
+
+// voteEligible checks if a player has been around long enough to vote
+func voteEligible(p *frontend.Player, now int64) bool {
+	if p == nil || p.ConnectTime == 0 || p.IsBot() {
+		return false
+	}
+	return now-p.ConnectTime >= int64(voteSetting(p.Frontend.Vote.GetMinTime(), voteDefaultMinTime))
+}
+
+// eligibleVoters counts the players on a frontend who can vote
+func eligibleVoters(fe *frontend.Frontend, now int64) int {
+	count := 0
+	for i := range fe.Players {
+		p := &fe.Players[i]
+		if p.Frontend == nil {
+			p.Frontend = fe
+		}
+		if voteEligible(p, now) {
+			count++
+		}
+	}
+	return count
+}
+
+// Tally counts the votes for each candidate, or yes and no for a called
+// vote
+func (v *MapVote) Tally() []int {
+	size := len(v.Maps)
+	if v.Kind != VoteMap {
+		size = 2
+	}
+	counts := make([]int, size)
+	for _, b := range v.Ballots {
+		if b >= 0 && b < size {
+			counts[b]++
+		}
+	}
+	return counts
+}
+
+// Winner is the candidate with the most votes, the earliest in the rotation
+// on a tie
+func (v *MapVote) Winner() *pb.Map {
+	if len(v.Maps) == 0 {
+		return nil
+	}
+	counts, best := v.Tally(), 0
+	for i, n := range counts {
+		if n > counts[best] {
+			best = i
+		}
+	}
+	return v.Maps[best]
+}
+
+// Passed checks if enough players have said yes to a called vote, quorum
+// is the percent of eligible players needed
+func (v *MapVote) Passed(quorum int) bool {
+	counts := v.Tally()
+	no, yes := counts[0], counts[1]
+	return yes >= v.Needed(quorum) && yes > no
+}
+
+// Needed is how many yes votes a called vote needs to pass
+func (v *MapVote) Needed(quorum int) int {
+	return max((v.Eligible*quorum+99)/100, 1)
+}
+
+// String describes the vote for players
+func (v *MapVote) String() string {
+	switch v.Kind {
+	case VoteSkip:
+		return fmt.Sprintf("%s called a vote to skip this map", v.Caller)
+	case VoteNext:
+		return fmt.Sprintf("%s called a vote to play %s next", v.Caller, v.Maps[0].GetName())
+	}
+	var maps []string
+	for i, m := range v.Maps {
+		maps = append(maps, fmt.Sprintf("%d) %s", i+1, m.GetName()))
+	}
+	return "Vote for the next map: " + strings.Join(maps, "  ")
+}
+
+// results describes how the vote went for players
+func (v *MapVote) results(quorum int) string {
+	counts := v.Tally()
+	if v.Kind == VoteMap {
+		var maps []string
+		for i, m := range v.Maps {
+			maps = append(maps, fmt.Sprintf("%s %d", m.GetName(), counts[i]))
+		}
+		return fmt.Sprintf("Vote results: %s, next map is %s", strings.Join(maps, ", "), v.Winner().GetName())
+	}
+	result := "failed"
+	if v.Passed(quorum) {
+		result = "passed"
+	}
+	return fmt.Sprintf("Vote %s: %d yes, %d no, %d needed", result, counts[1], counts[0], v.Needed(quorum))
+}
+
+// startVote makes v the frontend's running vote and closes it when time's
+// up. mapVotes has to be locked.
+func startVote(fe *frontend.Frontend, v *MapVote) {
+	duration := voteSetting(fe.Vote.GetDuration(), voteDefaultDuration)
+	v.Ends = time.Now().Unix() + int64(duration)
+	mapVotes.running[fe.Name] = v
+	v.timer = time.AfterFunc(time.Duration(duration)*time.Second, func() {
+		if CloseVote(fe, v) && fe.Connected {
+			SendMessages(fe)
+		}
+	})
+	SayEveryone(fe, PRINT_HIGH, fmt.Sprintf("%s (%d seconds)\n", v, duration))
+	if v.Kind == VoteMap {
+		SayEveryone(fe, PRINT_HIGH, "Type \"vote <number>\" to pick one\n")
+	} else {
+		SayEveryone(fe, PRINT_HIGH, "Type \"vote yes\" or \"vote no\"\n")
+	}
+	msg := fmt.Sprintf("%-20s %s", "VOTE:", v)
+	fe.Log.Println(msg)
+	fe.SSHPrintln(msg)
+}
+
+// RunningVote is the vote running on a frontend, nil if there isn't one
+func RunningVote(fe *frontend.Frontend) *MapVote {
+	mapVotes.Lock()
+	defer mapVotes.Unlock()
+	return mapVotes.running[fe.Name]
+}
+
+// CancelVote stops any vote or map change waiting to happen on a frontend
+// and forgets the map a next vote picked, like when the map changes some
+// other way
+func CancelVote(fe *frontend.Frontend) {
+	mapVotes.Lock()
+	defer mapVotes.Unlock()
+	if v := mapVotes.running[fe.Name]; v != nil {
+		v.timer.Stop()
+		delete(mapVotes.running, fe.Name)
+	}
+	if t := mapVotes.changing[fe.Name]; t != nil {
+		t.Stop()
+		delete(mapVotes.changing, fe.Name)
+	}
+	delete(mapVotes.queued, fe.Name)
+}
+
+// CloseVote ends a vote, announces the result and acts on it. Returns false
+// if it had already been closed or cancelled.
+func CloseVote(fe *frontend.Frontend, v *MapVote) bool {
+	if v == nil {
+		return false
+	}
+	mapVotes.Lock()
+	if mapVotes.running[fe.Name] != v {
+		mapVotes.Unlock()
+		return false
+	}
+	v.timer.Stop()
+	delete(mapVotes.running, fe.Name)
+	quorum := voteSetting(fe.Vote.GetQuorum(), voteDefaultQuorum)
+	passed := v.Kind == VoteMap || v.Passed(quorum)
+	if passed && v.Kind == VoteNext {
+		mapVotes.queued[fe.Name] = v.Maps[0]
+	}
+	mapVotes.Unlock()
+
+	if err := RecordVoteResult(fe, v, passed); err != nil {
+		be.Logln(LogLevelInfo, err)
+	}
+	results := v.results(quorum)
+	SayEveryone(fe, PRINT_HIGH, results+"\n")
+	msg := fmt.Sprintf("%-20s %s", "VOTE:", results)
+	fe.Log.Println(msg)
+	fe.SSHPrintln(msg)
+	if !passed || fe.Maplist == nil {
+		return true
+	}
+	switch v.Kind {
+	case VoteMap:
+		fe.Maplist.Select(v.Winner())
+		changeMap(fe, v.Winner())
+	case VoteSkip:
+		changeMap(fe, fe.Maplist.Next(fe.PlayerCount))
+	}
+	return true
+}
+
+// changeMap runs the commands to change to a map from the rotation and
+// remembers where the rotation is at
+func changeMap(fe *frontend.Frontend, m *pb.Map) {
+	for _, cmd := range maprotator.Commands(m, fe.DMFlags) {
+		ConsoleCommand(fe, cmd)
+	}
+	if err := SaveRotationPosition(fe); err != nil {
+		be.Logln(LogLevelInfo, err)
+	}
+}
+
+// NextMap decides what's played after a match ends: the map a next vote
+// picked, what players vote for if the frontend has end of match votes, or
+// the rotation's next map.
+//
+// Called from ParsePrint()
+func NextMap(fe *frontend.Frontend) {
+	if fe.Maplist == nil {
+		return
+	}
+	mapVotes.Lock()
+	if v := mapVotes.running[fe.Name]; v != nil {
+		v.timer.Stop() // a called vote can't finish now
+		delete(mapVotes.running, fe.Name)
+	}
+	next := mapVotes.queued[fe.Name]
+	delete(mapVotes.queued, fe.Name)
+	if next == nil && fe.Vote.GetEnabled() {
+		n := voteSetting(fe.Vote.GetCandidates(), voteDefaultCandidates)
+		candidates := fe.Maplist.Candidates(fe.PlayerCount, n, fe.CurrentMap)
+		if len(candidates) > 1 {
+			startVote(fe, &MapVote{Kind: VoteMap, Maps: candidates, Ballots: make(map[int]int)})
+			mapVotes.Unlock()
+			return
+		}
+	}
+
+	if next == nil || !fe.Maplist.Select(next) {
+		next = fe.Maplist.Next(fe.PlayerCount)
+	}
+	fe.Log.Printf("changing map to %q in %d seconds\n", next.GetName(), voteMapDelay)
+	var change *time.Timer
+	change = time.AfterFunc(voteMapDelay*time.Second, func() {
+		mapVotes.Lock()
+		if mapVotes.changing[fe.Name] != change {
+			mapVotes.Unlock()
+			return
+		}
+		delete(mapVotes.changing, fe.Name)
+		mapVotes.Unlock()
+		changeMap(fe, next)
+		if fe.Connected {
+			SendMessages(fe)
+		}
+	})
+	mapVotes.changing[fe.Name] = change
+	mapVotes.Unlock()
+}
+
+// CallVote starts a skip or next vote for a player, mapname is the map for
+// a next vote. The caller's vote counts as yes, so if they're the only one
+// who can vote it passes right away.
+func CallVote(p *frontend.Player, kind string, mapname string) error {
+	fe := p.Frontend
+	if !fe.Vote.GetCall() {
+		return fmt.Errorf("votes can't be called on this server")
+	}
+	if fe.Maplist == nil {
+		return fmt.Errorf("there's no map rotation to vote on")
+	}
+	now := time.Now().Unix()
+	if !voteEligible(p, now) {
+		return fmt.Errorf("you just got here, wait a little before voting")
+	}
+	v := &MapVote{Kind: kind, Caller: p.Name, Ballots: map[int]int{p.ClientID: 1}, Eligible: eligibleVoters(fe, now)}
+	if kind == VoteNext {
+		for _, m := range fe.Maplist.GetMaps() {
+			if strings.EqualFold(m.GetName(), mapname) {
+				v.Maps = []*pb.Map{m}
+				break
+			}
+		}
+		if len(v.Maps) == 0 {
+			return fmt.Errorf("%q isn't in the map rotation", mapname)
+		}
+	}
+
+	mapVotes.Lock()
+	if mapVotes.running[fe.Name] != nil {
+		mapVotes.Unlock()
+		return fmt.Errorf("there's already a vote running")
+	}
+	if fe.MatchStart == 0 {
+		mapVotes.Unlock()
+		return fmt.Errorf("wait for the match to start")
+	}
+	if wait := mapVotes.called[fe.Name] + int64(voteSetting(fe.Vote.GetCooldown(), voteDefaultCooldown)) - now; wait > 0 {
+		mapVotes.Unlock()
+		return fmt.Errorf("a vote was called recently, wait %d seconds", wait)
+	}
+	mapVotes.called[fe.Name] = now
+	startVote(fe, v)
+	mapVotes.Unlock()
+
+	if v.Passed(voteSetting(fe.Vote.GetQuorum(), voteDefaultQuorum)) {
+		CloseVote(fe, v)
+	}
+	return nil
+}
+
+// CastVote records a player's vote in the running vote: a candidate's
+// number or name at the end of a match, yes or no for a called vote. A
+// called vote closes as soon as it has enough yes votes.
+func CastVote(p *frontend.Player, choice string) (string, error) {
+	fe := p.Frontend
+	if !voteEligible(p, time.Now().Unix()) {
+		return "", fmt.Errorf("you just got here, wait a little before voting")
+	}
+	mapVotes.Lock()
+	v := mapVotes.running[fe.Name]
+	if v == nil {
+		mapVotes.Unlock()
+		return "", fmt.Errorf("there's no vote running")
+	}
+	ballot, voted := -1, ""
+	choice = strings.ToLower(strings.TrimSpace(choice))
+	if v.Kind == VoteMap {
+		for i, m := range v.Maps {
+			if choice == strconv.Itoa(i+1) || choice == strings.ToLower(m.GetName()) {
+				ballot, voted = i, m.GetName()
+			}
+		}
+		if ballot == -1 {
+			mapVotes.Unlock()
+			return "", fmt.Errorf("pick a number from 1 to %d", len(v.Maps))
+		}
+	} else {
+		switch choice {
+		case "yes", "y":
+			ballot, voted = 1, "yes"
+		case "no", "n":
+			ballot, voted = 0, "no"
+		default:
+			mapVotes.Unlock()
+			return "", fmt.Errorf("vote yes or no")
+		}
+	}
+	v.Ballots[p.ClientID] = ballot
+	passed := v.Kind != VoteMap && v.Passed(voteSetting(fe.Vote.GetQuorum(), voteDefaultQuorum))
+	mapVotes.Unlock()
+
+	if passed {
+		CloseVote(fe, v)
+	}
+	return voted, nil
+}
+
+// Vote is called when a player uses the vote command in-game
+func Vote(fe *frontend.Frontend) {
+	if fe == nil {
+		return
+	}
+	client := (&fe.Message).ReadByte()
+	text := (&fe.Message).ReadString()
+	p, err := fe.FindPlayer(int(client))
+	if err != nil {
+		be.Logf(LogLevelInfo, "vote error: %v\n", err)
+		return
+	}
+	c, _ := ParseCmdArgs(text)
+	switch {
+	case c.command == "":
+		if v := RunningVote(fe); v != nil {
+			SayPlayer(fe, p, PRINT_HIGH, fmt.Sprintf("%s, %d seconds left\n", v, v.Ends-time.Now().Unix()))
+			return
+		}
+		SayPlayer(fe, p, PRINT_HIGH, "Usage: vote skip | vote next <map> | vote <choice>\n")
+	case c.command == VoteSkip && c.argc == 0, c.command == VoteNext && c.argc == 1:
+		mapname := ""
+		if c.argc == 1 {
+			mapname = c.argv[0]
+		}
+		if err := CallVote(p, c.command, mapname); err != nil {
+			SayPlayer(fe, p, PRINT_HIGH, fmt.Sprintf("vote: %v\n", err))
+		}
+	default:
+		voted, err := CastVote(p, c.command)
+		if err != nil {
+			SayPlayer(fe, p, PRINT_HIGH, fmt.Sprintf("vote: %v\n", err))
+			return
+		}
+		SayPlayer(fe, p, PRINT_HIGH, fmt.Sprintf("You voted %s\n", voted))
+	}
+}
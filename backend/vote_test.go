@@ -0,0 +1,190 @@
+package backend
+
+import (
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/packetflinger/q2admind/frontend"
+	"github.com/packetflinger/q2admind/maprotator"
+
+	pb "github.com/packetflinger/q2admind/proto"
+)
+
+// voteTestFrontend sets up a frontend mid-match with three players who can
+// vote and one who just connected
+func voteTestFrontend(t *testing.T, vote *pb.VoteConfig) *frontend.Frontend {
+	t.Helper()
+	useTestDatabase(t)
+	fe := &frontend.Frontend{
+		Name:       "test1",
+		Log:        log.New(io.Discard, "", 0),
+		Vote:       vote,
+		Players:    make([]frontend.Player, 8),
+		CurrentMap: "q2dm1",
+		MatchStart: time.Now().Unix(),
+	}
+	r := &Rotation{Name: "default", Maps: []string{"q2dm1", "q2dm2", "q2dm3", "q2dm4 10", "q2dm5"}}
+	if err := SaveRotation(fe, r); err != nil {
+		t.Fatal(err)
+	}
+	if err := ActivateRotation(fe, "default"); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().Unix()
+	for i, name := range []string{"leon", "claire", "ada", "sherry"} {
+		fe.Players[i] = frontend.Player{ClientID: i, Name: name, IP: "192.0.2.1", ConnectTime: now - 600, Frontend: fe}
+	}
+	fe.Players[3].ConnectTime = now
+	fe.PlayerCount = 4
+	t.Cleanup(func() {
+		CancelVote(fe)
+		mapVotes.Lock()
+		delete(mapVotes.called, fe.Name)
+		mapVotes.Unlock()
+	})
+	return fe
+}
+
+func TestParseVoteConfig(t *testing.T) {
+	got := ParseVoteConfig(true, false, "4", " 30 ", "150", "-1", "x")
+	if !got.GetEnabled() || got.GetCall() || got.GetCandidates() != 4 || got.GetDuration() != 30 ||
+		got.GetQuorum() != 0 || got.GetCooldown() != 0 || got.GetMinTime() != 0 {
+		t.Errorf("ParseVoteConfig() = %v", got)
+	}
+}
+
+func TestMapVoteTally(t *testing.T) {
+	maps := maprotator.NewMapRotation("", []string{"q2dm1", "q2dm2", "q2dm3"}).GetMaps()
+	tests := []struct {
+		name    string
+		ballots map[int]int
+		want    string
+	}{
+		{name: "nobody voted", ballots: map[int]int{}, want: "q2dm1"},
+		{name: "most votes", ballots: map[int]int{0: 2, 1: 1, 2: 2}, want: "q2dm3"},
+		{name: "tie", ballots: map[int]int{0: 2, 1: 1}, want: "q2dm2"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			v := &MapVote{Kind: VoteMap, Maps: maps, Ballots: tc.ballots}
+			if got := v.Winner().GetName(); got != tc.want {
+				t.Errorf("Winner() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMapVotePassed(t *testing.T) {
+	tests := []struct {
+		name     string
+		ballots  map[int]int
+		eligible int
+		quorum   int
+		want     bool
+	}{
+		{name: "quorum", ballots: map[int]int{0: 1, 1: 1}, eligible: 4, quorum: 50, want: true},
+		{name: "short", ballots: map[int]int{0: 1, 1: 1}, eligible: 5, quorum: 50, want: false},
+		{name: "more no", ballots: map[int]int{0: 1, 1: 1, 2: 0, 3: 0}, eligible: 4, quorum: 50, want: false},
+		{name: "alone", ballots: map[int]int{0: 1}, eligible: 1, quorum: 50, want: true},
+		{name: "nobody eligible", ballots: map[int]int{}, eligible: 0, quorum: 50, want: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			v := &MapVote{Kind: VoteSkip, Ballots: tc.ballots, Eligible: tc.eligible}
+			if got := v.Passed(tc.quorum); got != tc.want {
+				t.Errorf("Passed(%d) = %v, want %v", tc.quorum, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEndOfMatchVote(t *testing.T) {
+	fe := voteTestFrontend(t, &pb.VoteConfig{Enabled: true})
+	NextMap(fe)
+	v := RunningVote(fe)
+	if v == nil || v.Kind != VoteMap || len(v.Maps) != 3 || v.Maps[0].GetName() != "q2dm2" {
+		t.Fatalf("NextMap() started %+v", v)
+	}
+	for client, choice := range map[int]string{0: "3", 1: "q2dm5", 2: "2"} {
+		if _, err := CastVote(&fe.Players[client], choice); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := CastVote(&fe.Players[3], "1"); err == nil {
+		t.Error("CastVote() from a player who just connected = nil, want error")
+	}
+	if _, err := CastVote(&fe.Players[0], "4"); err == nil {
+		t.Error("CastVote() for a map that isn't a candidate = nil, want error")
+	}
+	if !CloseVote(fe, v) {
+		t.Fatal("CloseVote() = false")
+	}
+	if fe.Maplist.GetMaps()[fe.Maplist.GetIndex()].GetName() != "q2dm5" {
+		t.Errorf("rotation is at %d after the vote, want q2dm5", fe.Maplist.GetIndex())
+	}
+	list, _ := LoadActiveRotation(fe.Name)
+	if list.GetIndex() != fe.Maplist.GetIndex() {
+		t.Errorf("saved rotation position %d, want %d", list.GetIndex(), fe.Maplist.GetIndex())
+	}
+	if CloseVote(fe, v) {
+		t.Error("CloseVote() twice = true")
+	}
+}
+
+func TestCalledVote(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		fe := voteTestFrontend(t, &pb.VoteConfig{Enabled: true})
+		if err := CallVote(&fe.Players[0], VoteSkip, ""); err == nil {
+			t.Error("CallVote() when calling isn't allowed = nil, want error")
+		}
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		fe := voteTestFrontend(t, &pb.VoteConfig{Call: true})
+		if err := CallVote(&fe.Players[3], VoteSkip, ""); err == nil {
+			t.Error("CallVote() from a player who just connected = nil, want error")
+		}
+		if err := CallVote(&fe.Players[0], VoteSkip, ""); err != nil {
+			t.Fatal(err)
+		}
+		if err := CallVote(&fe.Players[1], VoteSkip, ""); err == nil {
+			t.Error("CallVote() while one is running = nil, want error")
+		}
+		if voted, err := CastVote(&fe.Players[1], "yes"); err != nil || voted != "yes" {
+			t.Fatalf("CastVote() = %q, %v", voted, err)
+		}
+		if RunningVote(fe) != nil {
+			t.Error("vote still running after reaching the quorum")
+		}
+		if fe.Maplist.GetIndex() != 1 {
+			t.Errorf("rotation is at %d after skipping, want 1", fe.Maplist.GetIndex())
+		}
+		if err := CallVote(&fe.Players[1], VoteSkip, ""); err == nil {
+			t.Error("CallVote() during the cooldown = nil, want error")
+		}
+	})
+
+	t.Run("next", func(t *testing.T) {
+		fe := voteTestFrontend(t, &pb.VoteConfig{Enabled: true, Call: true})
+		if err := CallVote(&fe.Players[0], VoteNext, "q2dm9"); err == nil {
+			t.Error("CallVote() for a map not in the rotation = nil, want error")
+		}
+		if err := CallVote(&fe.Players[0], VoteNext, "q2dm3"); err != nil {
+			t.Fatal(err)
+		}
+		CastVote(&fe.Players[1], "no")
+		CastVote(&fe.Players[2], "yes")
+		if RunningVote(fe) != nil {
+			t.Fatal("vote still running after reaching the quorum")
+		}
+		NextMap(fe)
+		if RunningVote(fe) != nil {
+			t.Error("NextMap() started a vote after a next vote passed")
+		}
+		if fe.Maplist.GetIndex() != 2 {
+			t.Errorf("rotation is at %d after the match, want 2", fe.Maplist.GetIndex())
+		}
+	})
+}
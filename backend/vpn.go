@@ -1,117 +1,268 @@
 package backend
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/packetflinger/q2admind/frontend"
 	"google.golang.org/protobuf/encoding/prototext"
 
 	pb "github.com/packetflinger/q2admind/proto"
 )
 
-var (
-	VPNCache map[string]VPNCacheEntry
+const (
+	vpnLookupTimeout = 2000       // milliseconds
+	vpnCacheTTL      = 86400 * 30 // seconds
+	vpnKickMessage   = "VPN connections aren't allowed on this server"
 )
 
-// Address ranges associated with VPN providers will probably
-// not update very frequently. If an IP is not in the cache,
-// look it up from the upstream source, then save it for future
-// lookups.
-type VPNCacheEntry struct {
-	ip      string // the IP in question
-	vpn     bool   // is this IP from a VPN provider?
-	ttl     int64  // unix timestamp when this record is considered stale
-	lookups int64  // how many times this IP has been looked up
+// VPNProvider is somewhere we can ask if an address belongs to a VPN
+// service
+type VPNProvider interface {
+	Name() string
+	IsVPN(ctx context.Context, ip string) (bool, error)
 }
 
-// Load the vpn config proto from disk
-func ReadVPNConfig(cfgfile string) (*pb.VPNConfig, error) {
-	var cfg *pb.VPNConfig
-	contents, err := os.ReadFile(cfgfile)
+// URLVPNProvider asks a web API. The URL is a pattern with the address and
+// the API key filled in (in that order), the response is JSON with a
+// security.vpn boolean. Anything but a 2xx status is an error, APIs answer
+// rate limits and bad keys with a body that would read as "not a VPN".
+type URLVPNProvider struct {
+	URL    string
+	APIKey string
+}
+
+// CIDRVPNProvider checks a local list of address ranges
+type CIDRVPNProvider struct {
+	File     string
+	Networks []*net.IPNet
+}
+
+// VPNProviderChain asks each provider in order until one says yes. A no only
+// counts if every provider answered.
+type VPNProviderChain []VPNProvider
+
+func (u *URLVPNProvider) Name() string {
+	return "url"
+}
+
+func (u *URLVPNProvider) IsVPN(ctx context.Context, ip string) (bool, error) {
+	type Results struct {
+		Security struct {
+			VPN bool `json:"vpn"`
+		} `json:"security"`
+	}
+	url := fmt.Sprintf(u.URL, ip, u.APIKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("unable to open VPN config: %v", err)
+		return false, err
 	}
-	err = prototext.Unmarshal(contents, cfg)
+	res, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error unmarshalling VPN config: %v", err)
+		return false, err
 	}
-	return cfg, nil
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return false, fmt.Errorf("lookup failed: %s", res.Status)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+	var results Results
+	err = json.Unmarshal(body, &results)
+	if err != nil {
+		return false, err
+	}
+	return results.Security.VPN, nil
 }
 
-func InitVPNCache() {
-	VPNCache = make(map[string]VPNCacheEntry)
+func (c *CIDRVPNProvider) Name() string {
+	return "cidr"
 }
 
-// Check if the supplied IP address is from a VPN service. First look at the cache, then
-// lookup via the actual service (and add to the cache)
-func IsVPN(ip string, cache map[string]VPNCacheEntry, config *pb.VPNConfig) (bool, error) {
-	if config == nil {
-		return false, fmt.Errorf("null config")
-	}
-	if !config.Enabled {
-		return false, nil
+func (c *CIDRVPNProvider) IsVPN(ctx context.Context, ip string) (bool, error) {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false, fmt.Errorf("invalid ip %q", ip)
 	}
-	r, found := cache[ip]
-	if found {
-		fmt.Println(ip, "found!")
-		if r.vpn && r.ttl > time.Now().Unix() {
+	for _, n := range c.Networks {
+		if n.Contains(addr) {
 			return true, nil
-		} else {
-			return false, nil
 		}
-	} else {
-		fmt.Println(ip, "not found")
-		lookup, err := lookupVPNStatus(ip, config)
+	}
+	return false, nil
+}
+
+// LoadCIDRVPNProvider reads a list of address ranges, one per line. Blank
+// lines and anything after a # are ignored, single addresses are allowed.
+func LoadCIDRVPNProvider(file string) (*CIDRVPNProvider, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open VPN address list: %v", err)
+	}
+	defer f.Close()
+	c := &CIDRVPNProvider{File: file}
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		if !strings.Contains(text, "/") {
+			if strings.Contains(text, ":") {
+				text += "/128"
+			} else {
+				text += "/32"
+			}
+		}
+		_, n, err := net.ParseCIDR(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid address range %q", file, line, text)
+		}
+		c.Networks = append(c.Networks, n)
+	}
+	return c, scanner.Err()
+}
+
+func (ch VPNProviderChain) Name() string {
+	var names []string
+	for _, p := range ch {
+		names = append(names, p.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+func (ch VPNProviderChain) IsVPN(ctx context.Context, ip string) (bool, error) {
+	vpn, _, err := ch.Lookup(ctx, ip)
+	return vpn, err
+}
+
+// Lookup asks each provider in turn, returning the name of the one whose
+// answer counts. If nobody says yes and a provider couldn't answer, its error
+// is returned so the incomplete answer isn't cached.
+func (ch VPNProviderChain) Lookup(ctx context.Context, ip string) (bool, string, error) {
+	var errs []error
+	answered := ""
+	for _, p := range ch {
+		vpn, err := p.IsVPN(ctx, ip)
 		if err != nil {
-			return false, err
+			errs = append(errs, fmt.Errorf("%s: %v", p.Name(), err))
+			continue
 		}
-		entry := VPNCacheEntry{
-			ip:      ip,
-			vpn:     lookup,
-			ttl:     time.Now().Unix() + (86400 * 30), // a month
-			lookups: 0,
+		if vpn {
+			return true, p.Name(), nil
 		}
-		cache[ip] = entry
-		return lookup, nil
+		answered = p.Name()
 	}
+	if len(errs) > 0 {
+		return false, "", errs[len(errs)-1]
+	}
+	return false, answered, nil
 }
 
-func lookupVPNStatus(ip string, config *pb.VPNConfig) (bool, error) {
-	if ip == "" {
-		return false, fmt.Errorf("empty ip looking up vpn status")
+// NewVPNProvider sets up the providers a config asks for, the local list
+// first since it's quick
+func NewVPNProvider(config *pb.VPNConfig) (VPNProvider, error) {
+	var chain VPNProviderChain
+	if config.GetCidrFile() != "" {
+		c, err := LoadCIDRVPNProvider(config.GetCidrFile())
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, c)
 	}
-	if config == nil {
-		return false, fmt.Errorf("null config looking up vpn status")
+	if config.GetLookupUrl() != "" {
+		chain = append(chain, &URLVPNProvider{URL: config.GetLookupUrl(), APIKey: config.GetApiKey()})
 	}
-	type Results struct {
-		Security struct {
-			VPN bool `JSON:"vpn"`
-		} `JSON:"security"`
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("no VPN providers configured, need lookup_url or cidr_file")
+	}
+	if len(chain) == 1 {
+		return chain[0], nil
 	}
+	return chain, nil
+}
 
-	apiClient := http.Client{
-		Timeout: time.Second * 2,
+// vpnTimeout is how long to wait on a lookup
+func vpnTimeout(config *pb.VPNConfig) time.Duration {
+	ms := config.GetTimeout()
+	if ms <= 0 {
+		ms = vpnLookupTimeout
 	}
-	url := fmt.Sprintf(config.GetLookupUrl(), ip, config.GetApiKey())
-	res, err := apiClient.Get(url)
+	return time.Duration(ms) * time.Millisecond
+}
+
+// Load the vpn config proto from disk
+func ReadVPNConfig(cfgfile string) (*pb.VPNConfig, error) {
+	cfg := &pb.VPNConfig{}
+	contents, err := os.ReadFile(cfgfile)
 	if err != nil {
-		return false, err
+		return nil, fmt.Errorf("unable to open VPN config: %v", err)
 	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
+	err = prototext.Unmarshal(contents, cfg)
 	if err != nil {
-		return false, err
+		return nil, fmt.Errorf("error unmarshalling VPN config: %v", err)
 	}
-	var results Results
-	err = json.Unmarshal(body, &results)
+	return cfg, nil
+}
+
+// askVPNProvider does a lookup, giving up after timeout. Returns the name
+// of the provider that answered.
+func askVPNProvider(provider VPNProvider, ip string, timeout time.Duration) (bool, string, error) {
+	if ip == "" {
+		return false, "", fmt.Errorf("empty ip looking up vpn status")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if ch, ok := provider.(VPNProviderChain); ok {
+		return ch.Lookup(ctx, ip)
+	}
+	vpn, err := provider.IsVPN(ctx, ip)
 	if err != nil {
-		return false, err
+		return false, "", err
 	}
+	return vpn, provider.Name(), nil
+}
 
-	return results.Security.VPN, nil
+// CheckVPN looks up whether a newly connected player is using a VPN before
+// rules are checked, so vpn rules can apply. Returns true if they were
+// kicked for it.
+func CheckVPN(p *frontend.Player) bool {
+	if p == nil || p.Frontend == nil || !be.vpn.GetEnabled() {
+		return false
+	}
+	fe := p.Frontend
+	vpn, source, err := LookupVPN(p.IP)
+	if err != nil {
+		be.Logf(LogLevelInfo, "vpn lookup for %s[%s] failed: %v\n", p.Name, p.IP, err)
+		return false
+	}
+	if !vpn {
+		return false
+	}
+	p.VPN = true
+	if err := fe.UpdatePlayerVPN(p); err != nil {
+		be.Logln(LogLevelInfo, err)
+	}
+	msg := fmt.Sprintf("%-20s[%d] %-20q %s (%s)", "VPN:", p.ClientID, p.Name, p.IP, source)
+	fe.Log.Printf("%s", msg)
+	fe.SSHPrintln(msg)
+	if be.vpn.GetKickVpnUsers() {
+		KickPlayer(fe, p, vpnKickMessage)
+		return true
+	}
+	return false
 }
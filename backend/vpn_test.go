@@ -1,96 +1,183 @@
 package backend
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
 	"testing"
 	"time"
 
+	"github.com/packetflinger/q2admind/frontend"
+
 	pb "github.com/packetflinger/q2admind/proto"
 )
 
-func TestLookupVPNStatus(t *testing.T) {
+// errVPNProvider can never answer
+type errVPNProvider struct{}
+
+func (errVPNProvider) Name() string { return "err" }
+
+func (errVPNProvider) IsVPN(ctx context.Context, ip string) (bool, error) {
+	return false, fmt.Errorf("unavailable")
+}
+
+func testVPNList(t *testing.T) string {
+	t.Helper()
+	file := path.Join(t.TempDir(), "vpn.list")
+	list := "# known VPN ranges\n192.0.2.0/24\n\n198.51.100.7 # single address\n2001:db8::/32\n"
+	if err := os.WriteFile(file, []byte(list), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestCIDRVPNProvider(t *testing.T) {
+	c, err := LoadCIDRVPNProvider(testVPNList(t))
+	if err != nil {
+		t.Fatal(err)
+	}
 	tests := []struct {
-		desc   string
-		ip     string
-		config *pb.VPNConfig
-		want   bool
+		ip   string
+		want bool
 	}{
-		{
-			// normally this test is true, but removing my api key...
-			desc: "test1",
-			ip:   "154.6.80.10",
-			config: &pb.VPNConfig{
-				Enabled:   true,
-				LookupUrl: "https://vpnapi.io/api/%s?key=%s",
-				ApiKey:    "yeahright",
-			},
-			want: false,
-		},
+		{"192.0.2.55", true},
+		{"198.51.100.7", true},
+		{"198.51.100.8", false},
+		{"2001:db8::1", true},
+		{"203.0.113.1", false},
 	}
-
 	for _, tc := range tests {
-		t.Run(tc.desc, func(t *testing.T) {
-			got, err := lookupVPNStatus(tc.ip, tc.config)
-			if err != nil {
-				t.Error(err)
-			}
-			if got != tc.want {
-				t.Error("lookupVPNStatus() =", got, ", want", tc.want)
-			}
-		})
+		if got, err := c.IsVPN(context.Background(), tc.ip); err != nil || got != tc.want {
+			t.Errorf("IsVPN(%q) = %t, %v, want %t", tc.ip, got, err, tc.want)
+		}
+	}
+
+	bad := path.Join(t.TempDir(), "bad.list")
+	os.WriteFile(bad, []byte("192.0.2.0/24\nnot-an-address\n"), 0644)
+	if _, err := LoadCIDRVPNProvider(bad); err == nil {
+		t.Error("LoadCIDRVPNProvider() with a bad line = nil, want error")
 	}
 }
 
-func TestIsVPN(t *testing.T) {
-	tests := []struct {
-		desc   string
-		ip     string
-		cache  map[string]VPNCacheEntry
-		config *pb.VPNConfig
-		want   bool
-	}{
-		{
-			desc:   "test1",
-			ip:     "154.6.80.10",
-			config: &pb.VPNConfig{Enabled: true},
-			cache: map[string]VPNCacheEntry{
-				"154.6.80.10": {
-					vpn: true,
-					ttl: time.Now().Unix() + 100,
-				},
-				"8.8.8.8": {
-					vpn: false,
-					ttl: time.Now().Unix() + 100,
-				},
-			},
-			want: true,
-		},
-		{
-			desc: "test2",
-			ip:   "154.6.80.10",
-			config: &pb.VPNConfig{
-				Enabled:   true,
-				LookupUrl: "https://vpnapi.io/api/%s?key=%s",
-				ApiKey:    "yeahright",
-			},
-			cache: map[string]VPNCacheEntry{
-				"8.8.8.8": {
-					vpn: false,
-					ttl: time.Now().Unix() + 100,
-				},
-			},
-			want: false,
-		},
+func TestURLVPNProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/192.0.2.1":
+			fmt.Fprint(w, `{"security": {"vpn": true}}`)
+		case "/api/192.0.2.2":
+			time.Sleep(200 * time.Millisecond)
+			fmt.Fprint(w, `{"security": {"vpn": true}}`)
+		case "/api/192.0.2.3":
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"message": "slow down"}`)
+		case "/api/192.0.2.4":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			fmt.Fprint(w, `{"security": {"vpn": false}}`)
+		}
+	}))
+	defer srv.Close()
+	u := &URLVPNProvider{URL: srv.URL + "/api/%s?key=%s", APIKey: "key"}
+
+	if got, _, err := askVPNProvider(u, "192.0.2.1", time.Second); err != nil || !got {
+		t.Errorf("askVPNProvider(vpn) = %t, %v", got, err)
+	}
+	if got, _, err := askVPNProvider(u, "203.0.113.1", time.Second); err != nil || got {
+		t.Errorf("askVPNProvider(not vpn) = %t, %v", got, err)
+	}
+	if _, _, err := askVPNProvider(u, "192.0.2.2", 50*time.Millisecond); err == nil {
+		t.Error("askVPNProvider(slow) = nil, want timeout")
+	}
+	for _, ip := range []string{"192.0.2.3", "192.0.2.4"} {
+		if _, _, err := askVPNProvider(u, ip, time.Second); err == nil {
+			t.Errorf("askVPNProvider(%s) with an error status = nil, want error", ip)
+		}
 	}
 
-	for _, tc := range tests {
-		t.Run(tc.desc, func(t *testing.T) {
-			got, err := IsVPN(tc.ip, tc.cache, tc.config)
-			if err != nil {
-				t.Error(err)
-			}
-			if got != tc.want {
-				t.Error("IsVPN() =", got, ", want", tc.want)
-			}
-		})
+	// failed lookups aren't cached
+	useTestDatabase(t)
+	cfg, lookup := be.vpn, be.vpnLookup
+	t.Cleanup(func() { be.vpn, be.vpnLookup = cfg, lookup })
+	be.vpn = &pb.VPNConfig{Enabled: true}
+	be.vpnLookup = u
+	if _, _, err := LookupVPN("192.0.2.3"); err == nil {
+		t.Error("LookupVPN() when rate limited = nil, want error")
+	}
+	if r, err := LoadVPNRecord("192.0.2.3"); err != nil || r != nil {
+		t.Errorf("LoadVPNRecord() after a failed lookup = %+v, %v, want nothing", r, err)
+	}
+}
+
+func TestVPNProviderChain(t *testing.T) {
+	c, err := LoadCIDRVPNProvider(testVPNList(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	chain := VPNProviderChain{errVPNProvider{}, c}
+	if got, err := chain.IsVPN(ctx, "192.0.2.1"); err != nil || !got {
+		t.Errorf("IsVPN() after a failed provider = %t, %v", got, err)
+	}
+	if got, err := chain.IsVPN(ctx, "203.0.113.1"); err == nil || got {
+		t.Errorf("IsVPN() with a failed provider = %t, %v, want an error", got, err)
+	}
+	if got, err := (VPNProviderChain{c, c}).IsVPN(ctx, "203.0.113.1"); err != nil || got {
+		t.Errorf("IsVPN() = %t, %v, want false", got, err)
+	}
+	if _, err := (VPNProviderChain{errVPNProvider{}}).IsVPN(ctx, "203.0.113.1"); err == nil {
+		t.Error("IsVPN() with no working providers = nil, want error")
+	}
+
+	p, err := NewVPNProvider(&pb.VPNConfig{CidrFile: testVPNList(t), LookupUrl: "http://127.0.0.1/%s/%s"})
+	if err != nil || p.Name() != "cidr,url" {
+		t.Errorf("NewVPNProvider() = %v, %v", p, err)
+	}
+	if _, err := NewVPNProvider(&pb.VPNConfig{Enabled: true}); err == nil {
+		t.Error("NewVPNProvider() without providers = nil, want error")
+	}
+}
+
+func TestCheckVPN(t *testing.T) {
+	useTestDatabase(t)
+	c, err := LoadCIDRVPNProvider(testVPNList(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, lookup := be.vpn, be.vpnLookup
+	t.Cleanup(func() { be.vpn, be.vpnLookup = cfg, lookup })
+	be.vpn = &pb.VPNConfig{Enabled: true}
+	be.vpnLookup = c
+
+	fe := &frontend.Frontend{Name: "test1", Log: log.New(io.Discard, "", 0)}
+	home := &frontend.Player{ClientID: 1, Name: "claire", IP: "203.0.113.1"}
+	away := &frontend.Player{ClientID: 2, Name: "leon", IP: "192.0.2.8"}
+	addTestPlayer(t, fe, home)
+	addTestPlayer(t, fe, away)
+
+	if CheckVPN(home) || home.VPN {
+		t.Errorf("CheckVPN(%s) = VPN", home.IP)
+	}
+	if CheckVPN(away) || !away.VPN {
+		t.Errorf("CheckVPN(%s) = not VPN", away.IP)
+	}
+	var vpn bool
+	db.Handle.QueryRow("SELECT vpn FROM player WHERE id = ?", away.Database_ID).Scan(&vpn)
+	if !vpn {
+		t.Error("CheckVPN() didn't record the player's VPN")
+	}
+	if r, err := LoadVPNRecord(away.IP); err != nil || r == nil || r.Provider != "cidr" {
+		t.Errorf("CheckVPN() cached %+v, %v", r, err)
+	}
+
+	be.vpn.KickVpnUsers = true
+	away.VPN = false
+	if !CheckVPN(away) || !bytes.Contains(fe.MessageOut.Data, []byte("kick 2")) {
+		t.Errorf("CheckVPN() with kick_vpn_users sent %q", fe.MessageOut.Data)
 	}
 }
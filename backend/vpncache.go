@@ -0,0 +1,202 @@
+// VPN lookups are remembered in the database so returning players don't
+// cost another (often paid) API call. Each address keeps the answer, which
+// provider gave it, how many times it's been asked about and when it goes
+// stale.
+//
+// Backend admins can override an address, marking it as a VPN (deny) or not
+// (allow). Overrides never expire and win over anything a provider says.
+package backend
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// VPN cache overrides
+const (
+	VPNOverrideNone  = ""
+	VPNOverrideVPN   = "vpn"   // always a VPN
+	VPNOverrideClean = "clean" // never a VPN
+)
+
+// VPNRecord is what we know about an address
+type VPNRecord struct {
+	ID       int64
+	IP       string
+	VPN      bool
+	Provider string // who answered
+	Override string
+	Author   string // who set the override
+	Hits     int64  // times it's been asked about
+	Created  int64
+	Expires  int64 // unix timestamp, the provider's answer is stale after
+}
+
+// Fresh checks if a record can be used without asking a provider again
+func (r *VPNRecord) Fresh() bool {
+	return r.Override != VPNOverrideNone || r.Expires > time.Now().Unix()
+}
+
+// IsVPN is the answer for the address, overrides first
+func (r *VPNRecord) IsVPN() bool {
+	switch r.Override {
+	case VPNOverrideVPN:
+		return true
+	case VPNOverrideClean:
+		return false
+	}
+	return r.VPN
+}
+
+// vpnCacheExpiry is when a provider's answer made now goes stale
+func vpnCacheExpiry() int64 {
+	ttl := be.vpn.GetCacheTtl()
+	if ttl <= 0 {
+		ttl = vpnCacheTTL
+	}
+	return time.Now().Unix() + ttl
+}
+
+// LoadVPNRecord fetches what's cached about an address, nil if nothing
+func LoadVPNRecord(ip string) (*VPNRecord, error) {
+	var r VPNRecord
+	qry := `
+		SELECT id, ip, vpn, provider, override, author, hits, created, expires
+		FROM vpn_cache WHERE ip = ?`
+	err := db.Handle.QueryRow(qry, ip).Scan(&r.ID, &r.IP, &r.VPN, &r.Provider, &r.Override, &r.Author, &r.Hits, &r.Created, &r.Expires)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading vpn cache for %s: %v", ip, err)
+	}
+	return &r, nil
+}
+
+// LoadVPNRecords fetches cached addresses, overrides first then the most
+// asked about. Only overrides if overrides is set.
+func LoadVPNRecords(overrides bool, limit int) ([]VPNRecord, error) {
+	var records []VPNRecord
+	qry := `
+		SELECT id, ip, vpn, provider, override, author, hits, created, expires
+		FROM vpn_cache WHERE (override != '' OR NOT ?)
+		ORDER BY override = '', hits DESC, id LIMIT ?`
+	rows, err := db.Handle.Query(qry, overrides, limit)
+	if err != nil {
+		return records, fmt.Errorf("error loading vpn cache: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var r VPNRecord
+		err := rows.Scan(&r.ID, &r.IP, &r.VPN, &r.Provider, &r.Override, &r.Author, &r.Hits, &r.Created, &r.Expires)
+		if err != nil {
+			return records, fmt.Errorf("error scanning vpn cache: %v", err)
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+// cacheVPNLookup saves a provider's answer, leaving any override alone
+func cacheVPNLookup(ip string, vpn bool, provider string) error {
+	now := time.Now().Unix()
+	qry := `
+		INSERT INTO vpn_cache (ip, vpn, provider, hits, created, expires)
+		VALUES (?,?,?,1,?,?)
+		ON CONFLICT(ip) DO UPDATE SET
+			vpn = excluded.vpn, provider = excluded.provider,
+			hits = hits + 1, expires = excluded.expires`
+	_, err := db.Handle.Exec(qry, ip, vpn, provider, now, vpnCacheExpiry())
+	if err != nil {
+		return fmt.Errorf("error caching vpn lookup for %s: %v", ip, err)
+	}
+	return nil
+}
+
+// vpnCacheHit counts a cached answer being used
+func vpnCacheHit(ip string) error {
+	_, err := db.Handle.Exec("UPDATE vpn_cache SET hits = hits + 1 WHERE ip = ?", ip)
+	if err != nil {
+		return fmt.Errorf("error updating vpn cache for %s: %v", ip, err)
+	}
+	return nil
+}
+
+// SetVPNOverride marks an address as always or never a VPN, or clears the
+// override so providers decide again
+func SetVPNOverride(ip string, override string, author string) error {
+	if net.ParseIP(ip) == nil {
+		return fmt.Errorf("invalid ip %q", ip)
+	}
+	switch override {
+	case VPNOverrideNone, VPNOverrideVPN, VPNOverrideClean:
+	default:
+		return fmt.Errorf("unknown vpn override %q", override)
+	}
+	if override == VPNOverrideNone {
+		author = ""
+	}
+	qry := `
+		INSERT INTO vpn_cache (ip, override, author, created)
+		VALUES (?,?,?,?)
+		ON CONFLICT(ip) DO UPDATE SET
+			override = excluded.override, author = excluded.author`
+	_, err := db.Handle.Exec(qry, ip, override, author, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("error overriding vpn status for %s: %v", ip, err)
+	}
+	return nil
+}
+
+// PurgeVPNCache forgets provider answers so they're looked up again.
+// Overrides are kept, clear those with SetVPNOverride. An empty ip purges
+// every stale answer, "all" purges every answer.
+func PurgeVPNCache(ip string) (int64, error) {
+	var res sql.Result
+	var err error
+	switch ip {
+	case "":
+		res, err = db.Handle.Exec("DELETE FROM vpn_cache WHERE override = '' AND expires <= ?", time.Now().Unix())
+	case "all":
+		res, err = db.Handle.Exec("DELETE FROM vpn_cache WHERE override = ''")
+	default:
+		res, err = db.Handle.Exec("DELETE FROM vpn_cache WHERE override = '' AND ip = ?", ip)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error purging vpn cache: %v", err)
+	}
+	return res.RowsAffected()
+}
+
+// LookupVPN answers whether an address is a VPN from the cache, or the
+// configured providers when there's no fresh answer. Returns where the
+// answer came from.
+func LookupVPN(ip string) (bool, string, error) {
+	r, err := LoadVPNRecord(ip)
+	if err != nil {
+		return false, "", err
+	}
+	if r != nil && r.Fresh() {
+		if err := vpnCacheHit(ip); err != nil {
+			be.Logln(LogLevelInfo, err)
+		}
+		if r.Override != VPNOverrideNone {
+			return r.IsVPN(), "override", nil
+		}
+		return r.VPN, r.Provider, nil
+	}
+	if be.vpnLookup == nil {
+		return false, "", fmt.Errorf("no vpn providers")
+	}
+	vpn, provider, err := askVPNProvider(be.vpnLookup, ip, vpnTimeout(be.vpn))
+	if err != nil {
+		return false, "", err
+	}
+	if err := cacheVPNLookup(ip, vpn, provider); err != nil {
+		be.Logln(LogLevelInfo, err)
+	}
+	return vpn, provider, nil
+}
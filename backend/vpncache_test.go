@@ -0,0 +1,110 @@
+package backend
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	pb "github.com/packetflinger/q2admind/proto"
+)
+
+// countingVPNProvider says everything in 192.0.2.0/24 is a VPN and counts
+// how many times it's asked
+type countingVPNProvider struct {
+	asked int
+}
+
+func (c *countingVPNProvider) Name() string { return "counting" }
+
+func (c *countingVPNProvider) IsVPN(ctx context.Context, ip string) (bool, error) {
+	c.asked++
+	return strings.HasPrefix(ip, "192.0.2."), nil
+}
+
+func useTestVPNProvider(t *testing.T) *countingVPNProvider {
+	t.Helper()
+	useTestDatabase(t)
+	cfg, lookup := be.vpn, be.vpnLookup
+	t.Cleanup(func() { be.vpn, be.vpnLookup = cfg, lookup })
+	provider := &countingVPNProvider{}
+	be.vpn = &pb.VPNConfig{Enabled: true}
+	be.vpnLookup = provider
+	return provider
+}
+
+func TestLookupVPN(t *testing.T) {
+	provider := useTestVPNProvider(t)
+	for i := 0; i < 3; i++ {
+		vpn, source, err := LookupVPN("192.0.2.80")
+		if err != nil || !vpn {
+			t.Fatalf("LookupVPN() = %t, %q, %v", vpn, source, err)
+		}
+	}
+	if provider.asked != 1 {
+		t.Errorf("provider asked %d times, want 1", provider.asked)
+	}
+	r, err := LoadVPNRecord("192.0.2.80")
+	if err != nil || r.Hits != 3 || r.Provider != "counting" || !r.Fresh() {
+		t.Errorf("LoadVPNRecord() = %+v, %v", r, err)
+	}
+
+	// stale answers are asked about again
+	db.Handle.Exec("UPDATE vpn_cache SET expires = 1")
+	if _, _, err := LookupVPN("192.0.2.80"); err != nil || provider.asked != 2 {
+		t.Errorf("LookupVPN() stale = %v, asked %d times", err, provider.asked)
+	}
+}
+
+func TestVPNOverride(t *testing.T) {
+	provider := useTestVPNProvider(t)
+	if _, _, err := LookupVPN("192.0.2.80"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetVPNOverride("192.0.2.80", VPNOverrideClean, "root@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetVPNOverride("203.0.113.1", VPNOverrideVPN, "root@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	for _, bad := range [][2]string{{"nope", VPNOverrideVPN}, {"203.0.113.1", "maybe"}} {
+		if err := SetVPNOverride(bad[0], bad[1], "root@example.com"); err == nil {
+			t.Errorf("SetVPNOverride(%q, %q) = nil, want error", bad[0], bad[1])
+		}
+	}
+
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"192.0.2.80", false},
+		{"203.0.113.1", true},
+	}
+	for _, tc := range tests {
+		vpn, source, err := LookupVPN(tc.ip)
+		if err != nil || vpn != tc.want || source != "override" {
+			t.Errorf("LookupVPN(%q) = %t, %q, %v, want %t from override", tc.ip, vpn, source, err, tc.want)
+		}
+	}
+	if provider.asked != 1 {
+		t.Errorf("provider asked %d times, want 1", provider.asked)
+	}
+
+	// overrides survive purges and don't expire
+	db.Handle.Exec("UPDATE vpn_cache SET expires = 1")
+	if n, err := PurgeVPNCache("all"); err != nil || n != 0 {
+		t.Errorf("PurgeVPNCache(all) = %d, %v, want 0", n, err)
+	}
+	if overrides, _ := LoadVPNRecords(true, 10); len(overrides) != 2 {
+		t.Errorf("LoadVPNRecords(overrides) = %+v", overrides)
+	}
+
+	if err := SetVPNOverride("192.0.2.80", VPNOverrideNone, "root@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := PurgeVPNCache(""); err != nil || n != 1 {
+		t.Errorf("PurgeVPNCache() = %d, %v, want 1", n, err)
+	}
+	if vpn, source, _ := LookupVPN("192.0.2.80"); !vpn || source != "counting" {
+		t.Errorf("LookupVPN() after clearing override = %t, %q", vpn, source)
+	}
+}
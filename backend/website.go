@@ -47,16 +47,26 @@ var (
 		"ago":        util.TimeAgo,
 		"dmflags":    dmflags,
 		"datetime":   util.TimeDateString,
+		"percent":    percent,
+		"linkcard":   profileLinkCard,
+		"periods":    func() []string { return LeaderboardPeriods },
+		"jobactions": func() []string { return JobActions },
+		"weapons":    frontend.WeaponNames,
+		"join":       strings.Join,
+		"inc":        func(i int) int { return i + 1 },
 	}
 )
 
 type PlayerDatabaseInfo struct {
+	ASN         uint32
 	ConnectTime int64
 	Cookie      string
+	Country     string
 	Hostname    string
 	Id          int
 	IP          string
 	Name        string
+	Profile     int64
 	ServerUUID  string
 	Server_id   int
 	Userinfo    string
@@ -91,6 +101,22 @@ type PageResponse struct {
 	Rule          *pb.Rule   // the rule to view/edit
 	Rules         []*pb.Rule // a list of rules (srv level)
 	Player        *frontend.Player
+	Profile       *Profile
+	Leaderboard   Leaderboard
+	Matches       []Match
+	Match         *Match
+	Reports       []PlayerReport
+	Report        *PlayerReport
+	Bridge        []BridgeMessage
+	AccountLinks  []AccountLink
+	LinkCode      string // just created, only shown once
+	LinkExpires   int64
+	Notes         []Note
+	NoteAccess    bool // can see and write notes on the frontend
+	Rotations     []Rotation
+	MapStats      []MapStat
+	MapStatsDays  int // how far back map stats go, 0 for all time
+	Jobs          []Job
 }
 
 type SessionUser struct {
@@ -336,9 +362,68 @@ func dashboardPage(user *pb.User) (PageResponse, error) {
 	out.SessionUser = user
 	out.Head.Title = "Dashboard | CloudAdmin"
 	out.Frontends = FrontendsByIdentity(user.GetEmail())
+	links, err := LoadAccountLinks(user.GetEmail())
+	if err != nil {
+		return out, err
+	}
+	out.AccountLinks = links
 	return out, nil
 }
 
+// LinkCodeHandler makes a one-time code for linking an in-game player to the
+// user's account and shows it on their dashboard
+func LinkCodeHandler(w http.ResponseWriter, r *http.Request) {
+	u, err := GetSessionUser(r)
+	if err != nil {
+		RedirectToSignon(w, r)
+		return
+	}
+	page, err := dashboardPage(u)
+	if err != nil {
+		log.Println(err)
+		fmt.Fprintln(w, "error 500")
+		return
+	}
+	page.LinkCode, page.LinkExpires, err = CreateLinkCode(u.GetEmail())
+	if err != nil {
+		log.Println(err)
+		fmt.Fprintln(w, "error 500")
+		return
+	}
+	tmpl, e := template.New("dashboard").Funcs(funcMap).ParseFiles(
+		path.Join(be.config.GetWebRoot(), "templates", "new", "common-header.tmpl"),
+		path.Join(be.config.GetWebRoot(), "templates", "new", "dashboard.tmpl"),
+		path.Join(be.config.GetWebRoot(), "templates", "new", "common-footer.tmpl"),
+	)
+	if e != nil {
+		log.Println(e)
+	} else {
+		err = tmpl.ExecuteTemplate(w, "dashboard", page)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// UnlinkHandler removes a player linked to the user's account
+func UnlinkHandler(w http.ResponseWriter, r *http.Request) {
+	u, err := GetSessionUser(r)
+	if err != nil {
+		RedirectToSignon(w, r)
+		return
+	}
+	id, err := strconv.ParseInt(mux.Vars(r)["LinkID"], 10, 64)
+	if err != nil {
+		fmt.Fprintf(w, "invalid link id %q", mux.Vars(r)["LinkID"])
+		return
+	}
+	if err := UnlinkAccount(u.GetEmail(), id); err != nil {
+		fmt.Fprintln(w, err)
+		return
+	}
+	http.Redirect(w, r, Routes.Dashboard, http.StatusSeeOther)
+}
+
 // Displays info page for a particular client
 func WebsiteHandlerServerView(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -444,7 +529,7 @@ func WebAddServer(w http.ResponseWriter, r *http.Request) {
 	// The add button was pressed to submit the form
 	if r.PostFormValue("AddButton") != "" {
 		name := r.PostFormValue("NewServerName")
-		address := r.PostFormValue("NewServerAddress")
+		address := strings.Trim(r.PostFormValue("NewServerAddress"), " []")
 		port := r.PostFormValue("NewServerPort")
 		if name == "" || address == "" {
 			fmt.Fprintf(w, "error - form fields are empty")
@@ -755,23 +840,33 @@ func WebEditServer(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintln(w, "403 - permission denied")
 		return
 	}
-	tokens := strings.Split(addr, ":")
-	if len(tokens) == 2 {
-		f.IPAddress = tokens[0]
-		port, err := strconv.Atoi(tokens[1])
-		if err != nil {
-			port = 27910
-		}
-		f.Port = port
+	if addr != "" {
+		f.IPAddress, f.Port = frontend.ParseServerAddress(addr)
 	}
 	f.Enabled = enabled
 	f.AllowTeleport = teleport
 	f.AllowInvite = invite
 	f.Name = name
 	f.DeleteProtect = protect
-
-	qry := "UPDATE frontend SET name=?, ip_address=?, port=?, enabled=?, allow_teleport=?, allow_invite=?, delete_protection=? WHERE id=?"
-	_, err = db.Handle.Exec(qry, f.Name, f.IPAddress, f.Port, f.Enabled, f.AllowTeleport, f.AllowInvite, f.DeleteProtect, f.ID)
+	f.RatingPool = strings.TrimSpace(r.PostFormValue("ratingpool"))
+	f.WhoisFields = ParseWhoisFields(r.PostFormValue("whoisfields"))
+	f.ChatGroup = strings.TrimSpace(r.PostFormValue("chatgroup"))
+	f.ChatTrigger = strings.TrimSpace(r.PostFormValue("chattrigger"))
+	f.DiscordHook = strings.TrimSpace(r.PostFormValue("discordwebhook"))
+	f.DiscordChan = strings.TrimSpace(r.PostFormValue("discordchannel"))
+	f.DiscordEvents = ParseDiscordEvents(r.PostFormValue("discordevents"))
+	f.Flood = ParseFloodConfig(r.PostFormValue("floodchat"), r.PostFormValue("flooduserinfo"), r.PostFormValue("floodactions"))
+	f.Admins = ParseAdmins(r.PostFormValue("admins"))
+	f.Vote = ParseVoteConfig(r.PostFormValue("switchvote") == "on", r.PostFormValue("switchvotecall") == "on",
+		r.PostFormValue("votecandidates"), r.PostFormValue("voteduration"), r.PostFormValue("votequorum"),
+		r.PostFormValue("votecooldown"), r.PostFormValue("votemintime"))
+
+	qry := "UPDATE frontend SET name=?, ip_address=?, port=?, enabled=?, allow_teleport=?, allow_invite=?, delete_protection=?, rating_pool=?, whois_fields=?, chat_group=?, chat_trigger=?, discord_webhook=?, discord_channel=?, discord_events=?, flood_chat=?, flood_userinfo=?, flood_actions=?, admins=?, vote_enabled=?, vote_call=?, vote_candidates=?, vote_duration=?, vote_quorum=?, vote_cooldown=?, vote_min_time=? WHERE id=?"
+	_, err = db.Handle.Exec(qry, f.Name, f.IPAddress, f.Port, f.Enabled, f.AllowTeleport, f.AllowInvite, f.DeleteProtect, f.RatingPool, strings.Join(f.WhoisFields, ","), f.ChatGroup, f.ChatTrigger, f.DiscordHook, f.DiscordChan, strings.Join(f.DiscordEvents, ","),
+		FormatFloodLimit(f.Flood.GetChatMessages(), f.Flood.GetChatWindow()),
+		FormatFloodLimit(f.Flood.GetUserinfoChanges(), f.Flood.GetUserinfoWindow()),
+		strings.Join(f.Flood.GetActions(), ","), strings.Join(f.Admins, ","),
+		f.Vote.GetEnabled(), f.Vote.GetCall(), f.Vote.GetCandidates(), f.Vote.GetDuration(), f.Vote.GetQuorum(), f.Vote.GetCooldown(), f.Vote.GetMinTime(), f.ID)
 	if err != nil {
 		fmt.Fprintf(w, "500 - error updating frontend in database")
 		log.Printf("error updating frontend %q: %v\n", f.Name, err)
@@ -812,13 +907,19 @@ func PlayerViewHandler(w http.ResponseWriter, r *http.Request) {
 	data.SessionUser = user
 	data.Frontend = fe
 	data.Player = &(fe.Players[pid])
+	data.NoteAccess = noteAccess(fe, user.GetEmail())
+	if data.Player.ConnectTime > 0 {
+		data.Notes, err = PlayerNotes(fe, user.GetEmail(), data.Player)
+		if err != nil {
+			be.Logln(LogLevelInfo, err)
+		}
+	}
 
-	tmpl, e := template.ParseFiles(
+	tmpl, e := template.New("player-view").Funcs(funcMap).ParseFiles(
 		path.Join(be.config.GetWebRoot(), "templates", "new", "common-header.tmpl"),
 		path.Join(be.config.GetWebRoot(), "templates", "new", "player-view.tmpl"),
 		path.Join(be.config.GetWebRoot(), "templates", "new", "common-footer.tmpl"),
 	)
-	tmpl = tmpl.Funcs(funcMap)
 	if e != nil {
 		log.Println(e)
 	} else {
@@ -829,6 +930,108 @@ func PlayerViewHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// PlayerNoteHandler leaves a note on a player from the web player view
+func PlayerNoteHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	user, err := GetSessionUser(r)
+	if err != nil {
+		RedirectToSignon(w, r)
+		return
+	}
+	fe, err := be.FindFrontend(vars["ServerUUID"])
+	if err != nil {
+		fmt.Fprintf(w, "500 - unable to locate frontend %q", vars["ServerUUID"])
+		return
+	}
+	if !noteAccess(fe, user.GetEmail()) {
+		fmt.Fprintln(w, "403 - permission denied")
+		return
+	}
+	pid, err := strconv.Atoi(vars["ClientNum"])
+	if err != nil || !fe.PlayerSlotInUse(pid) {
+		fmt.Fprintf(w, "invalid player id %q", vars["ClientNum"])
+		return
+	}
+	n := Note{Server: fe.Name, Author: user.GetEmail(), Text: strings.TrimSpace(r.PostFormValue("note"))}
+	if r.PostFormValue("global") == "on" {
+		n.Server = ""
+	}
+	n.SetPlayer(&fe.Players[pid])
+	if err := SaveNote(&n); err != nil {
+		fmt.Fprintln(w, err)
+		return
+	}
+	http.Redirect(w, r, path.Join("/sv", fe.UUID, fe.Name, "player", vars["ClientNum"]), http.StatusSeeOther)
+}
+
+// NoteDeleteHandler removes a note, then goes back to where it was removed
+// from
+func NoteDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := GetSessionUser(r)
+	if err != nil {
+		RedirectToSignon(w, r)
+		return
+	}
+	id, err := strconv.ParseInt(mux.Vars(r)["NoteID"], 10, 64)
+	if err != nil {
+		fmt.Fprintf(w, "invalid note id %q", mux.Vars(r)["NoteID"])
+		return
+	}
+	if err := DeleteNote(id, user.GetEmail()); err != nil {
+		fmt.Fprintln(w, err)
+		return
+	}
+	back := r.Referer()
+	if back == "" {
+		back = Routes.Dashboard
+	}
+	http.Redirect(w, r, back, http.StatusSeeOther)
+}
+
+// PlayerBanHandler will ban the player in a particular slot by creating rules
+// from their cookie, address and name, then kicking them.
+func PlayerBanHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	uuid := vars["ServerUUID"]
+	playerId := vars["ClientNum"]
+	user, err := GetSessionUser(r)
+	if err != nil {
+		RedirectToSignon(w, r)
+		return
+	}
+	fe, err := be.FindFrontend(uuid)
+	if err != nil {
+		fmt.Fprintf(w, "500 - unable to locate frontend %q", uuid)
+		return
+	}
+	if fe.Owner != user.Email {
+		fmt.Fprintln(w, "403 - permission denied")
+		return
+	}
+	pid, err := strconv.Atoi(playerId)
+	if err != nil || !fe.PlayerSlotInUse(pid) {
+		fmt.Fprintf(w, "invalid player id %q", playerId)
+		return
+	}
+	_, err = BanPlayer(fe, &fe.Players[pid], user.Email, r.PostFormValue("reason"))
+	if err != nil {
+		fmt.Fprintf(w, "500 - error banning player")
+		be.Logf(LogLevelInfo, "error banning player %d on %q: %v", pid, fe.Name, err)
+		return
+	}
+	http.Redirect(w, r, path.Join("/sv", fe.UUID, fe.Name, "rules"), http.StatusSeeOther)
+}
+
+// userFrontend finds one of the frontends the user has access to by its UUID
+func userFrontend(email string, uuid string) (*frontend.Frontend, bool) {
+	for _, f := range be.UserFrontends(email) {
+		if f.UUID == uuid {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
 func ServerConsoleHandler(w http.ResponseWriter, r *http.Request) {
 	user, err := GetSessionUser(r)
 	if err != nil {
@@ -841,7 +1044,15 @@ func ServerConsoleHandler(w http.ResponseWriter, r *http.Request) {
 	data.Title = "Server Console"
 	data.SessionUser = user
 
-	tmpl, e := template.ParseFiles(
+	fe, ok := userFrontend(user.GetEmail(), mux.Vars(r)["ServerUUID"])
+	if !ok {
+		fmt.Fprintf(w, "404 - server not found")
+		return
+	}
+	data.Frontend = fe
+	data.Bridge = BridgeHistory(data.Frontend.Owner, data.Frontend.ChatGroup)
+
+	tmpl, e := template.New("terminal").Funcs(funcMap).ParseFiles(
 		path.Join(be.config.GetWebRoot(), "templates", "new", "common-header.tmpl"),
 		path.Join(be.config.GetWebRoot(), "templates", "new", "terminal.tmpl"),
 		path.Join(be.config.GetWebRoot(), "templates", "new", "common-footer.tmpl"),
@@ -856,6 +1067,37 @@ func ServerConsoleHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// ServerConsoleSayHandler sends a message from the web console to the
+// frontend's chat group
+func ServerConsoleSayHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := GetSessionUser(r)
+	if err != nil {
+		RedirectToSignon(w, r)
+		return
+	}
+	fe, ok := userFrontend(user.GetEmail(), mux.Vars(r)["ServerUUID"])
+	if !ok {
+		fmt.Fprintf(w, "404 - server not found")
+		return
+	}
+	if fe.ChatGroup == "" {
+		fmt.Fprintf(w, "%s isn't in a chat group", fe.Name)
+		return
+	}
+	name := user.GetName()
+	if name == "" {
+		name = user.GetEmail()
+	}
+	RelayChat(BridgeMessage{
+		Owner:  fe.Owner,
+		Group:  fe.ChatGroup,
+		Origin: BridgeOriginWeb,
+		Name:   name,
+		Text:   strings.TrimSpace(r.PostFormValue("message")),
+	})
+	http.Redirect(w, r, fmt.Sprintf("/sv/%s/%s/console", fe.UUID, fe.Name), http.StatusSeeOther)
+}
+
 func SearchHandler(w http.ResponseWriter, r *http.Request) {
 	/*
 		user, err := GetSessionUser(r)
@@ -968,14 +1210,16 @@ func PlayerSearchViewHandler(w http.ResponseWriter, r *http.Request) {
 	nameLookup := tokens[0]
 	timeLookup := tokens[1]
 	qry := `
-	SELECT p.*, f.uuid
+	SELECT
+		p.id, p.server_id, p.name, p.ip, p.hostname, p.vpn, p.cookie, p.version,
+		p.userinfo, p.time, p.country, p.asn, p.profile, f.uuid
 	FROM player AS p
 	JOIN frontend AS f ON f.id = p.server_id
 	WHERE p.name = ? AND p.time = ?
 	LIMIT 1`
 	res := db.Handle.QueryRow(qry, nameLookup, timeLookup)
 	var p PlayerDatabaseInfo
-	err = res.Scan(&p.Id, &p.Server_id, &p.Name, &p.IP, &p.Hostname, &p.Vpn, &p.Cookie, &p.Version, &p.Userinfo, &p.ConnectTime, &p.ServerUUID)
+	err = res.Scan(&p.Id, &p.Server_id, &p.Name, &p.IP, &p.Hostname, &p.Vpn, &p.Cookie, &p.Version, &p.Userinfo, &p.ConnectTime, &p.Country, &p.ASN, &p.Profile, &p.ServerUUID)
 	if err != nil {
 		fmt.Fprintf(w, "500 - error scanning player data")
 		fmt.Printf("Error: scanning player data: %v\n", err)
@@ -1001,6 +1245,97 @@ func PlayerSearchViewHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// ProfileViewHandler shows everything known about a player across all their
+// sightings.
+func ProfileViewHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := GetSessionUser(r)
+	if err != nil {
+		RedirectToSignon(w, r)
+		return
+	}
+	vars := mux.Vars(r)
+	id, err := strconv.ParseInt(vars["ProfileID"], 10, 64)
+	if err != nil {
+		fmt.Fprintf(w, "invalid profile id %q", vars["ProfileID"])
+		return
+	}
+	pr, err := LoadProfile(id)
+	if err != nil {
+		fmt.Fprintf(w, "404 - profile not found")
+		be.Logf(LogLevelInfo, "error loading profile %d: %v", id, err)
+		return
+	}
+	// merged profiles live on at their new id
+	if pr.ID != id {
+		http.Redirect(w, r, fmt.Sprintf("/profile/%d", pr.ID), http.StatusSeeOther)
+		return
+	}
+	if !pr.VisibleTo(user) {
+		fmt.Fprintln(w, "403 - permission denied")
+		return
+	}
+	data := PageResponse{}
+	data.Head.Title = "Profile | Q2Admin CloudAdmin"
+	data.Title = "Profile: " + pr.Name
+	data.SessionUser = user
+	data.Profile = pr
+	tmpl, e := template.New("profile").Funcs(funcMap).ParseFiles(
+		path.Join(be.config.GetWebRoot(), "templates", "new", "common-header.tmpl"),
+		path.Join(be.config.GetWebRoot(), "templates", "new", "profile.tmpl"),
+		path.Join(be.config.GetWebRoot(), "templates", "new", "common-footer.tmpl"),
+	)
+	if e != nil {
+		log.Println(e)
+	} else {
+		err = tmpl.ExecuteTemplate(w, "profile", data)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// ProfileMergeHandler will fold the profile in the URL into the one from the
+// form. The user has to own a server both profiles have played on.
+func ProfileMergeHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := GetSessionUser(r)
+	if err != nil {
+		RedirectToSignon(w, r)
+		return
+	}
+	vars := mux.Vars(r)
+	from, err1 := strconv.ParseInt(vars["ProfileID"], 10, 64)
+	into, err2 := strconv.ParseInt(r.PostFormValue("into"), 10, 64)
+	if err1 != nil || err2 != nil {
+		fmt.Fprintf(w, "invalid profile id")
+		return
+	}
+	fromProfile, err1 := LoadProfile(from)
+	intoProfile, err2 := LoadProfile(into)
+	if err1 != nil || err2 != nil {
+		fmt.Fprintf(w, "404 - profile not found")
+		return
+	}
+	allowed := false
+	for _, fe := range MyFrontends(user) {
+		if fromProfile.SeenOn(fe.Name) && intoProfile.SeenOn(fe.Name) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		fmt.Fprintln(w, "403 - permission denied")
+		return
+	}
+	err = MergeProfiles(fromProfile.ID, intoProfile.ID)
+	if err != nil {
+		fmt.Fprintf(w, "500 - error merging profiles")
+		be.Logf(LogLevelInfo, "error merging profile %d into %d: %v", fromProfile.ID, intoProfile.ID, err)
+		return
+	}
+	be.Logf(LogLevelInfo, "%s merged profile %d into %d", user.Email, fromProfile.ID, intoProfile.ID)
+	http.Redirect(w, r, fmt.Sprintf("/profile/%d", intoProfile.ID), http.StatusSeeOther)
+}
+
 func RuleViewHandler(w http.ResponseWriter, r *http.Request) {
 	user, err := GetSessionUser(r)
 	if err != nil {
@@ -1112,14 +1447,9 @@ func RuleListHandler(w http.ResponseWriter, r *http.Request) {
 	data.Title = "Rules"
 	data.SessionUser = user
 
-	lookup := mux.Vars(r)["ServerUUID"]
-	fes := be.UserFrontends(user.GetEmail())
-	for _, f := range fes {
-		if f.UUID == lookup {
-			data.Frontend = f
-			data.Frontend.Rules = SortRules(data.Frontend.Rules)
-			break
-		}
+	if fe, ok := userFrontend(user.GetEmail(), mux.Vars(r)["ServerUUID"]); ok {
+		data.Frontend = fe
+		data.Frontend.Rules = SortRules(data.Frontend.Rules)
 	}
 
 	tmpl, e := template.ParseFiles(
@@ -1241,3 +1571,536 @@ func dmflags(val string) string {
 	}
 	return flags.ToString(fl)
 }
+
+// convert a 0-1 ratio to a percentage
+func percent(val float64) float64 {
+	return val * 100
+}
+
+// Bundle a title and a list of profile links together so both can be passed
+// to the "profile-links" template.
+func profileLinkCard(title string, links []ProfileLink) any {
+	return struct {
+		Title string
+		Links []ProfileLink
+	}{title, links}
+}
+
+// LeaderboardHandler shows the top players. The server, period and weapon
+// are taken from the query string, defaulting to the all-time leaderboard
+// across every server.
+func LeaderboardHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := GetSessionUser(r)
+	if err != nil {
+		RedirectToSignon(w, r)
+		return
+	}
+	q := r.URL.Query()
+	lb, err := LoadLeaderboard(q.Get("server"), q.Get("period"), q.Get("weapon"), 25)
+	if err != nil {
+		fmt.Fprintf(w, "invalid leaderboard: %v", err)
+		return
+	}
+	data := PageResponse{}
+	data.Head.Title = "Leaderboards | Q2Admin CloudAdmin"
+	data.Title = "Leaderboards"
+	data.SessionUser = user
+	data.Leaderboard = lb
+	for i := range be.frontends {
+		if be.frontends[i].Enabled {
+			data.Frontends = append(data.Frontends, &be.frontends[i])
+		}
+	}
+	tmpl, e := template.New("leaderboard").Funcs(funcMap).ParseFiles(
+		path.Join(be.config.GetWebRoot(), "templates", "new", "common-header.tmpl"),
+		path.Join(be.config.GetWebRoot(), "templates", "new", "leaderboard.tmpl"),
+		path.Join(be.config.GetWebRoot(), "templates", "new", "common-footer.tmpl"),
+	)
+	if e != nil {
+		log.Println(e)
+	} else {
+		err = tmpl.ExecuteTemplate(w, "leaderboard", data)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// userMatch will load the match in the URL if it was played on the frontend in
+// the URL and the user has access to that frontend.
+func userMatch(user *pb.User, r *http.Request) (*frontend.Frontend, *Match, error) {
+	vars := mux.Vars(r)
+	fe, ok := userFrontend(user.GetEmail(), vars["ServerUUID"])
+	if !ok {
+		return nil, nil, fmt.Errorf("server not found")
+	}
+	id, err := strconv.ParseInt(vars["MatchID"], 10, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid match id %q", vars["MatchID"])
+	}
+	m, err := LoadMatch(id)
+	if err != nil || m.Server != fe.Name {
+		return nil, nil, fmt.Errorf("match not found")
+	}
+	return fe, m, nil
+}
+
+// MatchListHandler shows the recent matches played on a frontend
+func MatchListHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := GetSessionUser(r)
+	if err != nil {
+		RedirectToSignon(w, r)
+		return
+	}
+	data := PageResponse{}
+	data.Head.Title = "Matches | Q2Admin CloudAdmin"
+	data.Title = "Matches"
+	data.SessionUser = user
+
+	fe, ok := userFrontend(user.GetEmail(), mux.Vars(r)["ServerUUID"])
+	if !ok {
+		fmt.Fprintf(w, "404 - server not found")
+		return
+	}
+	data.Frontend = fe
+	data.Matches, err = LoadMatches(data.Frontend.Name, 100)
+	if err != nil {
+		be.Logln(LogLevelInfo, err)
+	}
+
+	tmpl, e := template.New("match-list").Funcs(funcMap).ParseFiles(
+		path.Join(be.config.GetWebRoot(), "templates", "new", "common-header.tmpl"),
+		path.Join(be.config.GetWebRoot(), "templates", "new", "match-list.tmpl"),
+		path.Join(be.config.GetWebRoot(), "templates", "new", "common-footer.tmpl"),
+	)
+	if e != nil {
+		log.Println(e)
+	} else {
+		err = tmpl.ExecuteTemplate(w, "match-list", data)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// MatchViewHandler shows the final scoreboard of a single match
+func MatchViewHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := GetSessionUser(r)
+	if err != nil {
+		RedirectToSignon(w, r)
+		return
+	}
+	fe, m, err := userMatch(user, r)
+	if err != nil {
+		fmt.Fprintf(w, "404 - %v", err)
+		return
+	}
+	data := PageResponse{}
+	data.Head.Title = "Match | Q2Admin CloudAdmin"
+	data.Title = fmt.Sprintf("Match #%d: %s", m.ID, m.Map)
+	data.SessionUser = user
+	data.Frontend = fe
+	data.Match = m
+
+	tmpl, e := template.New("match-view").Funcs(funcMap).ParseFiles(
+		path.Join(be.config.GetWebRoot(), "templates", "new", "common-header.tmpl"),
+		path.Join(be.config.GetWebRoot(), "templates", "new", "match-view.tmpl"),
+		path.Join(be.config.GetWebRoot(), "templates", "new", "common-footer.tmpl"),
+	)
+	if e != nil {
+		log.Println(e)
+	} else {
+		err = tmpl.ExecuteTemplate(w, "match-view", data)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// MatchExportHandler will download a match and its scoreboard as JSON
+func MatchExportHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := GetSessionUser(r)
+	if err != nil {
+		RedirectToSignon(w, r)
+		return
+	}
+	_, m, err := userMatch(user, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	contents, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"match-%d.json\"", m.ID))
+	fmt.Fprintln(w, string(contents))
+}
+
+// userReport finds the report in the request's path, as long as the user has
+// access to the frontend it was made on
+func userReport(user *pb.User, r *http.Request) (*frontend.Frontend, *PlayerReport, error) {
+	vars := mux.Vars(r)
+	fe, ok := userFrontend(user.GetEmail(), vars["ServerUUID"])
+	if !ok {
+		return nil, nil, fmt.Errorf("server not found")
+	}
+	id, err := strconv.ParseInt(vars["ReportID"], 10, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid report id %q", vars["ReportID"])
+	}
+	report, err := LoadReport(id)
+	if err != nil || report.Server != fe.Name {
+		return nil, nil, fmt.Errorf("report not found")
+	}
+	return fe, report, nil
+}
+
+// ReportListHandler shows the player reports made on a frontend. Only open
+// and claimed reports are shown unless "all" is in the query.
+func ReportListHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := GetSessionUser(r)
+	if err != nil {
+		RedirectToSignon(w, r)
+		return
+	}
+	data := PageResponse{}
+	data.Head.Title = "Reports | Q2Admin CloudAdmin"
+	data.Title = "Reports"
+	data.SessionUser = user
+
+	fe, ok := userFrontend(user.GetEmail(), mux.Vars(r)["ServerUUID"])
+	if !ok {
+		fmt.Fprintf(w, "404 - server not found")
+		return
+	}
+	data.Frontend = fe
+	data.Reports, err = LoadReports(data.Frontend.Name, r.URL.Query().Has("all"), 100)
+	if err != nil {
+		be.Logln(LogLevelInfo, err)
+	}
+
+	tmpl, e := template.New("report-list").Funcs(funcMap).ParseFiles(
+		path.Join(be.config.GetWebRoot(), "templates", "new", "common-header.tmpl"),
+		path.Join(be.config.GetWebRoot(), "templates", "new", "report-list.tmpl"),
+		path.Join(be.config.GetWebRoot(), "templates", "new", "common-footer.tmpl"),
+	)
+	if e != nil {
+		log.Println(e)
+	} else {
+		err = tmpl.ExecuteTemplate(w, "report-list", data)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// ReportViewHandler shows a single player report and its snapshot
+func ReportViewHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := GetSessionUser(r)
+	if err != nil {
+		RedirectToSignon(w, r)
+		return
+	}
+	fe, report, err := userReport(user, r)
+	if err != nil {
+		fmt.Fprintf(w, "404 - %v", err)
+		return
+	}
+	data := PageResponse{}
+	data.Head.Title = "Report | Q2Admin CloudAdmin"
+	data.Title = fmt.Sprintf("Report #%d: %s", report.ID, report.TargetName)
+	data.SessionUser = user
+	data.Frontend = fe
+	data.Report = report
+
+	tmpl, e := template.New("report-view").Funcs(funcMap).ParseFiles(
+		path.Join(be.config.GetWebRoot(), "templates", "new", "common-header.tmpl"),
+		path.Join(be.config.GetWebRoot(), "templates", "new", "report-view.tmpl"),
+		path.Join(be.config.GetWebRoot(), "templates", "new", "common-footer.tmpl"),
+	)
+	if e != nil {
+		log.Println(e)
+	} else {
+		err = tmpl.ExecuteTemplate(w, "report-view", data)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// ReportUpdateHandler claims, releases, resolves or dismisses a player report
+func ReportUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := GetSessionUser(r)
+	if err != nil {
+		RedirectToSignon(w, r)
+		return
+	}
+	fe, report, err := userReport(user, r)
+	if err != nil {
+		fmt.Fprintf(w, "404 - %v", err)
+		return
+	}
+	status, ok := ReportActions[r.PostFormValue("action")]
+	if !ok {
+		fmt.Fprintf(w, "invalid action")
+		return
+	}
+	report, err = UpdateReport(report.ID, status, user.GetEmail(), r.PostFormValue("note"))
+	if err != nil {
+		fmt.Fprintf(w, "%v", err)
+		return
+	}
+	be.Logf(LogLevelInfo, "%s set report %d to %s", user.GetEmail(), report.ID, report.Status)
+	http.Redirect(w, r, fmt.Sprintf("/sv/%s/%s/report/%d", fe.UUID, fe.Name, report.ID), http.StatusSeeOther)
+}
+
+// RotationListHandler shows a frontend's map rotations
+func RotationListHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := GetSessionUser(r)
+	if err != nil {
+		RedirectToSignon(w, r)
+		return
+	}
+	data := PageResponse{}
+	data.Head.Title = "Map Rotations | Q2Admin CloudAdmin"
+	data.Title = "Map Rotations"
+	data.SessionUser = user
+
+	fe, ok := userFrontend(user.GetEmail(), mux.Vars(r)["ServerUUID"])
+	if !ok {
+		fmt.Fprintf(w, "404 - server not found")
+		return
+	}
+	data.Frontend = fe
+	data.Rotations, err = LoadRotations(data.Frontend.Name)
+	if err != nil {
+		be.Logln(LogLevelInfo, err)
+	}
+
+	tmpl, e := template.New("rotation-list").Funcs(funcMap).ParseFiles(
+		path.Join(be.config.GetWebRoot(), "templates", "new", "common-header.tmpl"),
+		path.Join(be.config.GetWebRoot(), "templates", "new", "rotation-list.tmpl"),
+		path.Join(be.config.GetWebRoot(), "templates", "new", "common-footer.tmpl"),
+	)
+	if e != nil {
+		log.Println(e)
+	} else {
+		err = tmpl.ExecuteTemplate(w, "rotation-list", data)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// RotationUpdateHandler saves, shuffles, reorders, activates or removes a
+// map rotation. Only the frontend's owner can.
+func RotationUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := GetSessionUser(r)
+	if err != nil {
+		RedirectToSignon(w, r)
+		return
+	}
+	fe, err := be.FindFrontend(mux.Vars(r)["ServerUUID"])
+	if err != nil {
+		fmt.Fprintf(w, "404 - server not found")
+		return
+	}
+	if fe.Owner != user.Email {
+		fmt.Fprintln(w, "403 - permission denied")
+		return
+	}
+	name := strings.TrimSpace(r.PostFormValue("name"))
+	action := r.PostFormValue("action")
+	switch action {
+	case "save":
+		maps, err := ParseRotationMaps(r.PostFormValue("maps"))
+		if err != nil {
+			fmt.Fprintf(w, "%v", err)
+			return
+		}
+		rot, err := LoadRotation(fe.Name, name)
+		if err != nil {
+			rot = &Rotation{Name: name}
+		}
+		rot.Maps = maps
+		rot.Reverse = r.PostFormValue("reverse") == "on"
+		err = SaveRotation(fe, rot)
+	case "use":
+		err = ActivateRotation(fe, name)
+	case "delete":
+		err = DeleteRotation(fe, name)
+	case "shuffle", "up", "down":
+		var rot *Rotation
+		rot, err = LoadRotation(fe.Name, name)
+		if err != nil {
+			break
+		}
+		if action == "shuffle" {
+			rot.Shuffle()
+		} else {
+			from, _ := strconv.Atoi(r.PostFormValue("map"))
+			to := from - 1
+			if action == "down" {
+				to = from + 1
+			}
+			if err = rot.MoveMap(from, to); err != nil {
+				break
+			}
+		}
+		err = SaveRotation(fe, rot)
+	default:
+		fmt.Fprintf(w, "invalid action")
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(w, "%v", err)
+		return
+	}
+	be.Logf(LogLevelInfo, "%s did rotation %s %q on %s", user.GetEmail(), action, name, fe.Name)
+	http.Redirect(w, r, fmt.Sprintf("/sv/%s/%s/rotations", fe.UUID, fe.Name), http.StatusSeeOther)
+}
+
+// MapStatsHandler ranks the maps played on a frontend
+func MapStatsHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := GetSessionUser(r)
+	if err != nil {
+		RedirectToSignon(w, r)
+		return
+	}
+	data := PageResponse{}
+	data.Head.Title = "Map Stats | Q2Admin CloudAdmin"
+	data.Title = "Map Stats"
+	data.SessionUser = user
+
+	fe, ok := userFrontend(user.GetEmail(), mux.Vars(r)["ServerUUID"])
+	if !ok {
+		fmt.Fprintf(w, "404 - server not found")
+		return
+	}
+	data.Frontend = fe
+	data.MapStatsDays, _ = strconv.Atoi(r.URL.Query().Get("days"))
+	data.MapStats, err = LoadMapStats(data.Frontend, data.MapStatsDays)
+	if err != nil {
+		be.Logln(LogLevelInfo, err)
+	}
+
+	tmpl, e := template.New("map-stats").Funcs(funcMap).ParseFiles(
+		path.Join(be.config.GetWebRoot(), "templates", "new", "common-header.tmpl"),
+		path.Join(be.config.GetWebRoot(), "templates", "new", "map-stats.tmpl"),
+		path.Join(be.config.GetWebRoot(), "templates", "new", "common-footer.tmpl"),
+	)
+	if e != nil {
+		log.Println(e)
+	} else {
+		err = tmpl.ExecuteTemplate(w, "map-stats", data)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// ScheduleListHandler shows the jobs scheduled on a frontend and their
+// recent runs
+func ScheduleListHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := GetSessionUser(r)
+	if err != nil {
+		RedirectToSignon(w, r)
+		return
+	}
+	data := PageResponse{}
+	data.Head.Title = "Scheduled Jobs | Q2Admin CloudAdmin"
+	data.Title = "Scheduled Jobs"
+	data.SessionUser = user
+
+	fe, ok := userFrontend(user.GetEmail(), mux.Vars(r)["ServerUUID"])
+	if !ok {
+		fmt.Fprintf(w, "404 - server not found")
+		return
+	}
+	data.Frontend = fe
+	data.Jobs, err = LoadJobs(data.Frontend)
+	if err != nil {
+		be.Logln(LogLevelInfo, err)
+	}
+	for i := range data.Jobs {
+		data.Jobs[i].Runs, err = LoadJobRuns(data.Jobs[i].ID, 5)
+		if err != nil {
+			be.Logln(LogLevelInfo, err)
+		}
+	}
+
+	tmpl, e := template.New("schedule-list").Funcs(funcMap).ParseFiles(
+		path.Join(be.config.GetWebRoot(), "templates", "new", "common-header.tmpl"),
+		path.Join(be.config.GetWebRoot(), "templates", "new", "schedule-list.tmpl"),
+		path.Join(be.config.GetWebRoot(), "templates", "new", "common-footer.tmpl"),
+	)
+	if e != nil {
+		log.Println(e)
+	} else {
+		err = tmpl.ExecuteTemplate(w, "schedule-list", data)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// ScheduleUpdateHandler saves, runs, turns on or off, or removes a scheduled
+// job. Only the frontend's owner can.
+func ScheduleUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := GetSessionUser(r)
+	if err != nil {
+		RedirectToSignon(w, r)
+		return
+	}
+	fe, err := be.FindFrontend(mux.Vars(r)["ServerUUID"])
+	if err != nil {
+		fmt.Fprintf(w, "404 - server not found")
+		return
+	}
+	if fe.Owner != user.Email {
+		fmt.Fprintln(w, "403 - permission denied")
+		return
+	}
+	name := strings.TrimSpace(r.PostFormValue("name"))
+	action := r.PostFormValue("action")
+	job := &Job{Owner: fe.Owner, Server: fe.Name, Name: name, Enabled: true, Author: user.GetEmail()}
+	if r.PostFormValue("group") == "on" {
+		job.Server, job.Group = "", fe.ChatGroup
+	}
+	if old, err := LoadJob(fe, name); err == nil && old.Target() == job.Target() {
+		job.Enabled = old.Enabled
+	}
+	if action != "save" {
+		if job, err = LoadJob(fe, name); err != nil {
+			fmt.Fprintf(w, "%v", err)
+			return
+		}
+	}
+	switch action {
+	case "save":
+		job.Spec = r.PostFormValue("spec")
+		job.Timezone = strings.TrimSpace(r.PostFormValue("timezone"))
+		job.Action = r.PostFormValue("job")
+		job.Args = r.PostFormValue("args")
+		err = SaveJob(job)
+	case "enable", "disable":
+		job.Enabled = action == "enable"
+		err = SaveJob(job)
+	case "run":
+		err = RunJob(job)
+	case "delete":
+		err = DeleteJob(job)
+	default:
+		fmt.Fprintf(w, "invalid action")
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(w, "%v", err)
+		return
+	}
+	be.Logf(LogLevelInfo, "%s did schedule %s %q on %s", user.GetEmail(), action, name, job.Target())
+	http.Redirect(w, r, fmt.Sprintf("/sv/%s/%s/schedule", fe.UUID, fe.Name), http.StatusSeeOther)
+}
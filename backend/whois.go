@@ -0,0 +1,167 @@
+// Players can look each other up in-game with the whois command. Unlike the
+// SSH console's whois, only public details from the player's profile are
+// shown (never addresses, cookies or userinfo) and each frontend's owner
+// decides which of those are exposed.
+package backend
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/packetflinger/q2admind/frontend"
+
+	pb "github.com/packetflinger/q2admind/proto"
+)
+
+// The player details in-game whois can show
+const (
+	WhoisAliases   = "aliases"
+	WhoisFirstSeen = "first_seen"
+	WhoisPlayTime  = "play_time"
+	WhoisRating    = "rating"
+	WhoisVerified  = "verified"
+	WhoisNone      = "none" // whois is turned off
+)
+
+const (
+	whoisMaxAliases = 5
+
+	// in-game output, unlike the ssh whois it only has public details
+	playerWhoisTemplate = `
+Whois [{{ .GetClientId }}] {{ .GetName }}:
+{{- if .GetAliases }}
+  Also known as: {{ join .GetAliases ", " }}{{ end }}
+{{- if .GetFirstSeen }}
+  First seen:    {{ .GetFirstSeen }}{{ end }}
+{{- if .GetPlayTime }}
+  Play time:     {{ .GetPlayTime }}{{ end }}
+{{- if .GetRating }}
+  Rating:        {{ .GetRating }} ({{ .GetPool }}){{ end }}
+{{- if .GetVerified }}
+  Verified:      {{ .GetVerified }}{{ end }}
+`
+)
+
+// WhoisFields are all the details whois can show, in display order
+var WhoisFields = []string{WhoisAliases, WhoisFirstSeen, WhoisPlayTime, WhoisRating, WhoisVerified}
+
+// ParseWhoisFields will turn a comma separated list of fields (from the
+// database or the website) into the fields a frontend exposes. Unknown fields
+// are dropped.
+func ParseWhoisFields(in string) []string {
+	var fields []string
+	for _, f := range strings.Split(in, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if (f == WhoisNone || slices.Contains(WhoisFields, f)) && !slices.Contains(fields, f) {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// whoisShows checks if a frontend exposes a whois field. Frontends that
+// haven't chosen any show them all.
+func whoisShows(fe *frontend.Frontend, field string) bool {
+	if slices.Contains(fe.WhoisFields, WhoisNone) {
+		return false
+	}
+	return len(fe.WhoisFields) == 0 || slices.Contains(fe.WhoisFields, field)
+}
+
+// profileVerified is whether a player is known to be who their profile says.
+// That takes their client's cookie (or a website account linked to it),
+// other signals (address, name) are easy to share or fake.
+func profileVerified(p *frontend.Player) bool {
+	if p.Account != "" {
+		return true
+	}
+	return p.ProfileID != 0 && p.Cookie != "" && p.ProfileScore >= profileScoreCookie
+}
+
+// BuildWhoisReply gathers the details a frontend exposes about a player. The
+// profile can be nil if the player isn't linked to one yet.
+func BuildWhoisReply(fe *frontend.Frontend, p *frontend.Player, pr *Profile) *pb.WhoisReply {
+	reply := &pb.WhoisReply{
+		ReplyDate: time.Now().Unix(),
+		ClientId:  int32(p.ClientID),
+		Name:      p.Name,
+	}
+	if whoisShows(fe, WhoisVerified) {
+		reply.Verified = "no"
+		if profileVerified(p) {
+			reply.Verified = "yes"
+		}
+	}
+	if whoisShows(fe, WhoisRating) && p.ProfileID != 0 {
+		reply.Rating = int32(p.Rating)
+		reply.Pool = fe.Pool()
+	}
+	if pr == nil {
+		return reply
+	}
+	if whoisShows(fe, WhoisAliases) {
+		for _, a := range pr.Aliases {
+			if strings.EqualFold(a.Value, p.Name) {
+				continue
+			}
+			reply.Aliases = append(reply.Aliases, a.Value)
+			if len(reply.Aliases) == whoisMaxAliases {
+				break
+			}
+		}
+	}
+	if first := pr.FirstSeen(); whoisShows(fe, WhoisFirstSeen) && first > 0 {
+		reply.FirstSeen = time.Unix(first, 0).Format(time.DateOnly)
+	}
+	if whoisShows(fe, WhoisPlayTime) && pr.Stats.PlayTime > 0 {
+		reply.PlayTime = (time.Duration(pr.Stats.PlayTime) * time.Second).String()
+	}
+	return reply
+}
+
+// Whois is called when a player issues the whois command in-game. The
+// argument is the client number or (part of) the name of the player to look
+// up, without one players get their own details.
+func Whois(fe *frontend.Frontend) {
+	if fe == nil {
+		return
+	}
+	client := (&fe.Message).ReadByte()
+	who := strings.TrimSpace((&fe.Message).ReadString())
+	p, err := fe.FindPlayer(int(client))
+	if err != nil {
+		be.Logf(LogLevelInfo, "whois error: %v\n", err)
+		return
+	}
+	if slices.Contains(fe.WhoisFields, WhoisNone) {
+		SayPlayer(fe, p, PRINT_HIGH, "Whois isn't available on this server\n")
+		return
+	}
+
+	target := p
+	if who != "" {
+		if target = findTarget(fe, p, who); target == nil {
+			return
+		}
+	}
+
+	var pr *Profile
+	if target.ProfileID != 0 {
+		pr, err = LoadProfile(target.ProfileID)
+		if err != nil {
+			be.Logln(LogLevelInfo, err)
+		}
+	}
+	reply := BuildWhoisReply(fe, target, pr)
+
+	var rendered bytes.Buffer
+	tmpl := template.Must(template.New("whois").Funcs(template.FuncMap{"join": strings.Join}).Parse(playerWhoisTemplate))
+	if err := tmpl.Execute(&rendered, reply); err != nil {
+		be.Logf(LogLevelInfo, "error executing whois template: %v\n", err)
+		return
+	}
+	SayPlayer(fe, p, PRINT_CHAT, rendered.String())
+}
@@ -0,0 +1,95 @@
+package backend
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/packetflinger/q2admind/frontend"
+)
+
+func TestParseWhoisFields(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"rating", []string{"rating"}},
+		{" Aliases, rating,rating ,bogus", []string{"aliases", "rating"}},
+		{"none", []string{"none"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			if got := ParseWhoisFields(tc.in); !slices.Equal(got, tc.want) {
+				t.Errorf("ParseWhoisFields(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBuildWhoisReply(t *testing.T) {
+	now := time.Now().Unix()
+	p := &frontend.Player{
+		ClientID:     3,
+		Name:         "claire",
+		Cookie:       "aaaa",
+		ProfileID:    7,
+		ProfileScore: 1.0,
+		Rating:       1612.4,
+	}
+	pr := &Profile{
+		ID: 7,
+		Aliases: []ProfileLink{
+			{Kind: ProfileLinkName, Value: "claire", FirstSeen: now},
+			{Kind: ProfileLinkName, Value: "c1aire", FirstSeen: now - 86400},
+		},
+		Stats: ProfileStats{PlayTime: 5400},
+	}
+
+	tests := []struct {
+		desc   string
+		fields []string
+		want   []string // expected in the rendered output
+		hidden []string // shouldn't be
+	}{
+		{
+			desc: "everything",
+			want: []string{"[3] claire", "c1aire", "First seen", "1h30m0s", "1612 (ffa)", "Verified:      yes"},
+		},
+		{
+			desc:   "rating only",
+			fields: []string{WhoisRating},
+			want:   []string{"1612 (ffa)"},
+			hidden: []string{"c1aire", "First seen", "Play time", "Verified"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			fe := &frontend.Frontend{RatingPool: "ffa", WhoisFields: tc.fields}
+			reply := BuildWhoisReply(fe, p, pr)
+			var out bytes.Buffer
+			tmpl := template.Must(template.New("whois").Funcs(template.FuncMap{"join": strings.Join}).Parse(playerWhoisTemplate))
+			if err := tmpl.Execute(&out, reply); err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range tc.want {
+				if !strings.Contains(out.String(), w) {
+					t.Errorf("whois output missing %q:\n%s", w, out.String())
+				}
+			}
+			for _, h := range tc.hidden {
+				if strings.Contains(out.String(), h) {
+					t.Errorf("whois output has hidden %q:\n%s", h, out.String())
+				}
+			}
+		})
+	}
+
+	unverified := &frontend.Player{Name: "leon", ProfileID: 8, ProfileScore: 0.6}
+	if got := BuildWhoisReply(&frontend.Frontend{}, unverified, nil).GetVerified(); got != "no" {
+		t.Errorf("BuildWhoisReply() verified = %q for an address match, want no", got)
+	}
+}
@@ -19,11 +19,26 @@ import (
 )
 
 var (
-	host     = flag.String("host", "127.0.0.1", "Server IP/Hostname")
-	port     = flag.Int("port", 9989, "Server port")
-	key      = flag.String("key", "id_ecdsa", "Your private key file")
-	user     = flag.String("user", "", "email address with access")
-	tokenTTL = flag.Int("token_ttl", 5, "Seconds the token is valid, DON'T CHANGE THIS")
+	host      = flag.String("host", "127.0.0.1", "Server IP/Hostname")
+	port      = flag.Int("port", 9989, "Server port")
+	key       = flag.String("key", "id_ecdsa", "Your private key file")
+	user      = flag.String("user", "", "email address with access")
+	tokenTTL  = flag.Int("token_ttl", 5, "Seconds the token is valid, DON'T CHANGE THIS")
+	export    = flag.String("export", "", "Print everything stored about a player (profile:<id>, cookie:<value>, ip:<address> or name:<name>)")
+	erase     = flag.String("erase", "", "Remove everything personal stored about a player (profile:<id> or cookie:<value>)")
+	anonymize = flag.Bool("anonymize", false, "With -erase, keep offenses and reports and drop only personal fields")
+	mapstats  = flag.String("mapstats", "", "Rank the maps played on a server by popularity")
+	days      = flag.Int("days", 0, "With -mapstats, how many days back to look (0 for all time)")
+	jobs      = flag.String("jobs", "", "List the jobs scheduled on a server, or the one to change with -job")
+	job       = flag.String("job", "", "With -jobs, the job to save, -delete or -run")
+	when      = flag.String("when", "", "With -job, when to run it: cron schedule or @daily, @hourly, etc")
+	timezone  = flag.String("tz", "", "With -job, the timezone of the schedule")
+	action    = flag.String("action", "", "With -job, what to do: command, say, rotation, enable-rule, disable-rule or restart")
+	args      = flag.String("args", "", "With -job, the command, message, rotation or rule ID")
+	group     = flag.Bool("group", false, "With -job, run on all your servers in the chat group")
+	disabled  = flag.Bool("disabled", false, "With -job, save it turned off")
+	remove    = flag.Bool("delete", false, "With -job, remove it")
+	run       = flag.Bool("run", false, "With -job, run it now")
 )
 
 type tokenAuth struct {
@@ -85,9 +100,96 @@ func main() {
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
+	if *export != "" {
+		r, err := c.ExportPlayerData(ctx, &pb.PlayerDataRequest{Identity: *export})
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		fmt.Println(r.GetJson())
+		return
+	}
+	if *erase != "" {
+		r, err := c.ErasePlayerData(ctx, &pb.PlayerDataRequest{Identity: *erase, Anonymize: *anonymize})
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		for table, n := range r.GetRows() {
+			fmt.Printf("%-15s %d\n", table, n)
+		}
+		return
+	}
+	if *mapstats != "" {
+		r, err := c.FetchMapStats(ctx, &pb.MapStatsRequest{Server: *mapstats, Days: int32(*days)})
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		fmt.Printf("%-4s %-16s %6s %10s %7s %5s %5s %6s %5s %5s\n", "#", "map", "plays", "time", "avg", "peak", "left", "votes", "won", "skip")
+		for _, e := range r.GetEntry() {
+			name := e.GetMap()
+			if !e.GetInRotation() {
+				name += "*"
+			}
+			fmt.Printf("%-4d %-16s %6d %10s %7.1f %5d %5d %6d %5d %5d\n", e.GetRank(), name, e.GetPlays(),
+				time.Duration(e.GetPlayTime())*time.Second, e.GetAvgPlayers(), e.GetPeak(), e.GetLeft(), e.GetVotes(), e.GetWon(), e.GetSkipped())
+		}
+		fmt.Println("* not in the active rotation")
+		return
+	}
+	if *jobs != "" && *job != "" {
+		req := &pb.JobRequest{
+			Server:   *jobs,
+			Name:     *job,
+			Group:    *group,
+			Spec:     *when,
+			Timezone: *timezone,
+			Action:   *action,
+			Args:     *args,
+			Disabled: *disabled,
+		}
+		var r *pb.JobEntry
+		if *remove {
+			r, err = c.DeleteJob(ctx, req)
+		} else if *run {
+			r, err = c.RunJob(ctx, req)
+		} else {
+			r, err = c.SaveJob(ctx, req)
+		}
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		printJob(r)
+		return
+	}
+	if *jobs != "" {
+		r, err := c.ListJobs(ctx, &pb.JobsRequest{Server: *jobs})
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		for _, j := range r.GetJob() {
+			printJob(j)
+		}
+		return
+	}
 	r, err := c.FetchStatus(ctx, &pb.StatusRequest{Server: "test"})
 	if err != nil {
 		log.Fatalf("error: %v", err)
 	}
 	log.Printf("UUID: %s", r.GetUuid())
 }
+
+// printJob shows a scheduled job and its recent runs
+func printJob(j *pb.JobEntry) {
+	fmt.Printf("%-16s %-16s %-20s %s %s\n", j.GetName(), j.GetTarget(), j.GetSpec(), j.GetAction(), j.GetArgs())
+	next := "never"
+	if j.GetNextRun() != 0 {
+		next = time.Unix(j.GetNextRun(), 0).Format(time.DateTime)
+	}
+	fmt.Printf("%16s enabled: %t  timezone: %q  next: %s\n", "", j.GetEnabled(), j.GetTimezone(), next)
+	for _, r := range j.GetRun() {
+		status := "ok"
+		if !r.GetSuccess() {
+			status = "failed"
+		}
+		fmt.Printf("%16s %s %-16s %-6s %s\n", "", time.Unix(r.GetTime(), 0).Format(time.DateTime), r.GetServer(), status, r.GetOutput())
+	}
+}
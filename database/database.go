@@ -3,6 +3,7 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"slices"
 
 	// "github.com/packetflinger/q2admind/frontend"
 	"github.com/packetflinger/q2admind/util"
@@ -23,17 +24,312 @@ CREATE TABLE IF NOT EXISTS "player" (
 	"version"	TEXT,
 	"userinfo"	TEXT,
 	"time"		INTEGER,
+	"country"	TEXT NOT NULL DEFAULT "",
+	"asn"		INTEGER NOT NULL DEFAULT 0,
+	"profile"	INTEGER NOT NULL DEFAULT 0,
+	"bot"		INTEGER NOT NULL DEFAULT 0,
 	PRIMARY KEY("id" AUTOINCREMENT)
 );`
 
+	// Tables added after the original schema. These are created at every
+	// startup so existing databases pick them up.
+	tables = `
+CREATE TABLE IF NOT EXISTS "player_stat" (
+	"id"		INTEGER,
+	"player"	INTEGER,
+	"frags"		INTEGER,
+	"deaths"	INTEGER,
+	"suicides"	INTEGER,
+	"kdr"		INTEGER,
+	"play_time"	INTEGER,
+	"time"		INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX IF NOT EXISTS "player_idx" ON "player_stat" ("player");
+CREATE TABLE IF NOT EXISTS "weapon_stat" (
+	"id"		INTEGER,
+	"session"	INTEGER NOT NULL DEFAULT 0,
+	"player"	INTEGER NOT NULL DEFAULT 0,
+	"means"		INTEGER NOT NULL DEFAULT 0,
+	"weapon"	TEXT NOT NULL DEFAULT "",
+	"frags"		INTEGER NOT NULL DEFAULT 0,
+	"deaths"	INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX IF NOT EXISTS "weapon_stat_session_idx" ON "weapon_stat" ("session");
+CREATE TABLE IF NOT EXISTS "profile" (
+	"id"		INTEGER,
+	"name"		TEXT NOT NULL DEFAULT "",
+	"created"	INTEGER NOT NULL DEFAULT 0,
+	"merged_into"	INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE TABLE IF NOT EXISTS "profile_link" (
+	"id"		INTEGER,
+	"profile"	INTEGER NOT NULL,
+	"kind"		TEXT NOT NULL,
+	"value"		TEXT NOT NULL,
+	"confidence"	REAL NOT NULL DEFAULT 0,
+	"first_seen"	INTEGER NOT NULL DEFAULT 0,
+	"last_seen"	INTEGER NOT NULL DEFAULT 0,
+	"count"		INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT),
+	UNIQUE("profile", "kind", "value")
+);
+CREATE INDEX IF NOT EXISTS "profile_link_value_idx" ON "profile_link" ("kind", "value");
+CREATE TABLE IF NOT EXISTS "offense" (
+	"id"		INTEGER,
+	"player"	INTEGER NOT NULL DEFAULT 0,
+	"profile"	INTEGER NOT NULL DEFAULT 0,
+	"server"	TEXT NOT NULL DEFAULT "",
+	"kind"		TEXT NOT NULL DEFAULT "",
+	"rule"		TEXT NOT NULL DEFAULT "",
+	"detail"	TEXT NOT NULL DEFAULT "",
+	"time"		INTEGER NOT NULL DEFAULT 0,
+	"expires"	INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX IF NOT EXISTS "offense_time_idx" ON "offense" ("time");
+CREATE TABLE IF NOT EXISTS "match_result" (
+	"id"		INTEGER,
+	"server"	TEXT NOT NULL DEFAULT "",
+	"map"		TEXT NOT NULL DEFAULT "",
+	"reason"	TEXT NOT NULL DEFAULT "",
+	"start"		INTEGER NOT NULL DEFAULT 0,
+	"end"		INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX IF NOT EXISTS "match_result_server_idx" ON "match_result" ("server", "end");
+CREATE TABLE IF NOT EXISTS "match_score" (
+	"id"		INTEGER,
+	"match"		INTEGER NOT NULL,
+	"player"	INTEGER NOT NULL DEFAULT 0,
+	"profile"	INTEGER NOT NULL DEFAULT 0,
+	"client"	INTEGER NOT NULL DEFAULT 0,
+	"name"		TEXT NOT NULL DEFAULT "",
+	"frags"		INTEGER NOT NULL DEFAULT 0,
+	"deaths"	INTEGER NOT NULL DEFAULT 0,
+	"suicides"	INTEGER NOT NULL DEFAULT 0,
+	"kdr"		REAL NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX IF NOT EXISTS "match_score_match_idx" ON "match_score" ("match");
+CREATE TABLE IF NOT EXISTS "rating" (
+	"id"		INTEGER,
+	"profile"	INTEGER NOT NULL,
+	"pool"		TEXT NOT NULL,
+	"rating"	REAL NOT NULL DEFAULT 1500,
+	"games"		INTEGER NOT NULL DEFAULT 0,
+	"updated"	INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT),
+	UNIQUE("profile", "pool")
+);
+CREATE TABLE IF NOT EXISTS "report" (
+	"id"		INTEGER,
+	"server"	TEXT NOT NULL DEFAULT "",
+	"time"		INTEGER NOT NULL DEFAULT 0,
+	"reporter"	INTEGER NOT NULL DEFAULT 0,
+	"reporter_profile"	INTEGER NOT NULL DEFAULT 0,
+	"reporter_name"	TEXT NOT NULL DEFAULT "",
+	"reporter_ip"	TEXT NOT NULL DEFAULT "",
+	"target"	INTEGER NOT NULL DEFAULT 0,
+	"target_profile"	INTEGER NOT NULL DEFAULT 0,
+	"target_name"	TEXT NOT NULL DEFAULT "",
+	"reason"	TEXT NOT NULL DEFAULT "",
+	"snapshot"	TEXT NOT NULL DEFAULT "",
+	"status"	TEXT NOT NULL DEFAULT "open",
+	"moderator"	TEXT NOT NULL DEFAULT "",
+	"note"		TEXT NOT NULL DEFAULT "",
+	"updated"	INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX IF NOT EXISTS "report_server_idx" ON "report" ("server", "status");
+CREATE INDEX IF NOT EXISTS "report_reporter_idx" ON "report" ("reporter_ip", "time");
+CREATE TABLE IF NOT EXISTS "reserved_name" (
+	"id"		INTEGER,
+	"server"	TEXT NOT NULL,
+	"kind"		TEXT NOT NULL DEFAULT "name",
+	"pattern"	TEXT NOT NULL,
+	"cookie"	TEXT NOT NULL DEFAULT "",
+	"profile"	INTEGER NOT NULL DEFAULT 0,
+	"account"	TEXT NOT NULL DEFAULT "",
+	"action"	TEXT NOT NULL DEFAULT "rename",
+	"owner"		TEXT NOT NULL DEFAULT "",
+	"created"	INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX IF NOT EXISTS "reserved_name_server_idx" ON "reserved_name" ("server");
+CREATE TABLE IF NOT EXISTS "link_code" (
+	"id"		INTEGER,
+	"code"		TEXT NOT NULL,
+	"account"	TEXT NOT NULL,
+	"created"	INTEGER NOT NULL,
+	"expires"	INTEGER NOT NULL,
+	"used"		INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX IF NOT EXISTS "link_code_code_idx" ON "link_code" ("code");
+CREATE TABLE IF NOT EXISTS "account_link" (
+	"id"		INTEGER,
+	"account"	TEXT NOT NULL,
+	"cookie"	TEXT NOT NULL,
+	"profile"	INTEGER NOT NULL DEFAULT 0,
+	"server"	TEXT NOT NULL DEFAULT "",
+	"created"	INTEGER NOT NULL,
+	PRIMARY KEY("id" AUTOINCREMENT),
+	UNIQUE("cookie")
+);
+CREATE INDEX IF NOT EXISTS "account_link_account_idx" ON "account_link" ("account");
+CREATE TABLE IF NOT EXISTS "admin_log" (
+	"id"		INTEGER,
+	"server"	TEXT NOT NULL DEFAULT "",
+	"actor"		TEXT NOT NULL DEFAULT "",
+	"action"	TEXT NOT NULL DEFAULT "",
+	"target"	TEXT NOT NULL DEFAULT "",
+	"profile"	INTEGER NOT NULL DEFAULT 0,
+	"detail"	TEXT NOT NULL DEFAULT "",
+	"time"		INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX IF NOT EXISTS "admin_log_server_idx" ON "admin_log" ("server");
+CREATE TABLE IF NOT EXISTS "player_note" (
+	"id"		INTEGER,
+	"server"	TEXT NOT NULL DEFAULT "",
+	"author"	TEXT NOT NULL DEFAULT "",
+	"profile"	INTEGER NOT NULL DEFAULT 0,
+	"cookie"	TEXT NOT NULL DEFAULT "",
+	"ip"		TEXT NOT NULL DEFAULT "",
+	"note"		TEXT NOT NULL DEFAULT "",
+	"created"	INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX IF NOT EXISTS "player_note_profile_idx" ON "player_note" ("profile");
+CREATE INDEX IF NOT EXISTS "player_note_cookie_idx" ON "player_note" ("cookie");
+CREATE INDEX IF NOT EXISTS "player_note_ip_idx" ON "player_note" ("ip");
+CREATE TABLE IF NOT EXISTS "vpn_cache" (
+	"id"		INTEGER,
+	"ip"		TEXT NOT NULL,
+	"vpn"		INTEGER NOT NULL DEFAULT 0,
+	"provider"	TEXT NOT NULL DEFAULT "",
+	"override"	TEXT NOT NULL DEFAULT "",
+	"author"	TEXT NOT NULL DEFAULT "",
+	"hits"		INTEGER NOT NULL DEFAULT 0,
+	"created"	INTEGER NOT NULL DEFAULT 0,
+	"expires"	INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT),
+	UNIQUE("ip")
+);
+CREATE TABLE IF NOT EXISTS "map_rotation" (
+	"id"		INTEGER,
+	"server"	TEXT NOT NULL,
+	"name"		TEXT NOT NULL,
+	"maps"		TEXT NOT NULL DEFAULT "",
+	"reverse"	INTEGER NOT NULL DEFAULT 0,
+	"active"	INTEGER NOT NULL DEFAULT 0,
+	"position"	INTEGER NOT NULL DEFAULT 0,
+	"iterations"	INTEGER NOT NULL DEFAULT 0,
+	"updated"	INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT),
+	UNIQUE("server", "name")
+);
+CREATE TABLE IF NOT EXISTS "map_play" (
+	"id"		INTEGER,
+	"server"	TEXT NOT NULL,
+	"map"		TEXT NOT NULL,
+	"start"		INTEGER NOT NULL,
+	"end"		INTEGER NOT NULL,
+	"player_seconds"	INTEGER NOT NULL DEFAULT 0,
+	"peak"		INTEGER NOT NULL DEFAULT 0,
+	"joined"	INTEGER NOT NULL DEFAULT 0,
+	"left_early"	INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX IF NOT EXISTS "map_play_server_idx" ON "map_play" ("server", "map");
+CREATE TABLE IF NOT EXISTS "map_vote" (
+	"id"		INTEGER,
+	"server"	TEXT NOT NULL,
+	"map"		TEXT NOT NULL,
+	"kind"		TEXT NOT NULL,
+	"votes"		INTEGER NOT NULL DEFAULT 0,
+	"won"		INTEGER NOT NULL DEFAULT 0,
+	"time"		INTEGER NOT NULL,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX IF NOT EXISTS "map_vote_server_idx" ON "map_vote" ("server", "map");
+CREATE TABLE IF NOT EXISTS "scheduled_job" (
+	"id"		INTEGER,
+	"owner"		TEXT NOT NULL,
+	"server"	TEXT NOT NULL DEFAULT "",
+	"grp"		TEXT NOT NULL DEFAULT "",
+	"name"		TEXT NOT NULL,
+	"spec"		TEXT NOT NULL,
+	"timezone"	TEXT NOT NULL DEFAULT "",
+	"action"	TEXT NOT NULL,
+	"args"		TEXT NOT NULL DEFAULT "",
+	"enabled"	INTEGER NOT NULL DEFAULT 1,
+	"author"	TEXT NOT NULL DEFAULT "",
+	"created"	INTEGER NOT NULL DEFAULT 0,
+	"last_run"	INTEGER NOT NULL DEFAULT 0,
+	"last_status"	TEXT NOT NULL DEFAULT "",
+	PRIMARY KEY("id" AUTOINCREMENT),
+	UNIQUE("owner", "server", "grp", "name")
+);
+CREATE TABLE IF NOT EXISTS "scheduled_run" (
+	"id"		INTEGER,
+	"job"		INTEGER NOT NULL,
+	"server"	TEXT NOT NULL,
+	"time"		INTEGER NOT NULL,
+	"success"	INTEGER NOT NULL DEFAULT 0,
+	"output"	TEXT NOT NULL DEFAULT "",
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX IF NOT EXISTS "scheduled_run_job_idx" ON "scheduled_run" ("job", "time");`
+
 	//	insertPlayer = `
 	// INSERT INTO player (server, name, ip, hostname, vpn, cookie, version, userinfo, time)
 	// VALUES (?,?,?,?,?,?,?,?,?)`
 
-	search = `SELECT * FROM player WHERE
+	search = `
+	SELECT
+		id, server, name, ip, hostname, vpn, cookie, version, userinfo, time,
+		country, asn, profile
+	FROM player WHERE
 	(name LIKE ? OR ip LIKE ? OR hostname LIKE ? OR userinfo LIKE ?)`
 )
 
+// Columns added to existing tables after the original schema. Older
+// databases are altered at startup to include them.
+var columns = []struct {
+	table      string
+	column     string
+	definition string
+}{
+	{"player", "country", `TEXT NOT NULL DEFAULT ""`},
+	{"player", "asn", `INTEGER NOT NULL DEFAULT 0`},
+	{"player", "profile", `INTEGER NOT NULL DEFAULT 0`},
+	{"player", "bot", `INTEGER NOT NULL DEFAULT 0`},
+	{"player_stat", "time", `INTEGER NOT NULL DEFAULT 0`},
+	{"frontend", "rating_pool", `TEXT NOT NULL DEFAULT ""`},
+	{"frontend", "whois_fields", `TEXT NOT NULL DEFAULT ""`},
+	{"frontend", "chat_group", `TEXT NOT NULL DEFAULT ""`},
+	{"frontend", "chat_trigger", `TEXT NOT NULL DEFAULT ""`},
+	{"frontend", "discord_webhook", `TEXT NOT NULL DEFAULT ""`},
+	{"frontend", "discord_channel", `TEXT NOT NULL DEFAULT ""`},
+	{"frontend", "discord_events", `TEXT NOT NULL DEFAULT ""`},
+	{"frontend", "flood_chat", `TEXT NOT NULL DEFAULT ""`},
+	{"frontend", "flood_userinfo", `TEXT NOT NULL DEFAULT ""`},
+	{"frontend", "flood_actions", `TEXT NOT NULL DEFAULT ""`},
+	{"frontend", "admins", `TEXT NOT NULL DEFAULT ""`},
+	{"frontend", "vote_enabled", `INTEGER NOT NULL DEFAULT 0`},
+	{"frontend", "vote_call", `INTEGER NOT NULL DEFAULT 0`},
+	{"frontend", "vote_candidates", `INTEGER NOT NULL DEFAULT 0`},
+	{"frontend", "vote_duration", `INTEGER NOT NULL DEFAULT 0`},
+	{"frontend", "vote_quorum", `INTEGER NOT NULL DEFAULT 0`},
+	{"frontend", "vote_cooldown", `INTEGER NOT NULL DEFAULT 0`},
+	{"frontend", "vote_min_time", `INTEGER NOT NULL DEFAULT 0`},
+	{"reserved_name", "account", `TEXT NOT NULL DEFAULT ""`},
+}
+
 // A struct for holding all our DB stuff
 type Database struct {
 	Handle *sql.DB
@@ -51,7 +347,11 @@ type SearchResult struct {
 	Version  string
 	Userinfo string
 	Time     int64
+	Country  string
+	ASN      uint32
+	Profile  int64
 	Ago      string
+	Notes    int // moderator notes on the player, filled in by the backend
 }
 
 func (d Database) Begin() (*sql.Tx, error) {
@@ -86,10 +386,56 @@ func Open(filename string) (Database, error) {
 			return database, fmt.Errorf("error loading db schema: %v", err)
 		}
 	}
+	if err = migrate(db); err != nil {
+		return database, err
+	}
 	database.Handle = db
 	return database, nil
 }
 
+// migrate will bring an existing database up to date with the current
+// schema by creating any missing tables and adding any missing columns.
+//
+// Called from Open()
+func migrate(db *sql.DB) error {
+	if _, err := db.Exec(tables); err != nil {
+		return fmt.Errorf("error creating tables: %v", err)
+	}
+	for _, c := range columns {
+		cols, err := tableColumns(db, c.table)
+		if err != nil {
+			return err
+		}
+		// tables this database doesn't use are left alone
+		if len(cols) == 0 || slices.Contains(cols, c.column) {
+			continue
+		}
+		qry := fmt.Sprintf("ALTER TABLE %q ADD COLUMN %q %s", c.table, c.column, c.definition)
+		if _, err := db.Exec(qry); err != nil {
+			return fmt.Errorf("error adding column %s.%s: %v", c.table, c.column, err)
+		}
+	}
+	return nil
+}
+
+// tableColumns lists the columns in a table, empty if the table doesn't exist
+func tableColumns(db *sql.DB, table string) ([]string, error) {
+	var cols []string
+	rows, err := db.Query(fmt.Sprintf("SELECT name FROM pragma_table_info(%q)", table))
+	if err != nil {
+		return cols, fmt.Errorf("error reading columns of %q: %v", table, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return cols, fmt.Errorf("error scanning columns of %q: %v", table, err)
+		}
+		cols = append(cols, name)
+	}
+	return cols, rows.Err()
+}
+
 // Search will fetch the rows that match the input pattern.
 func (d Database) Search(pattern string) ([]SearchResult, error) {
 	var results []SearchResult
@@ -112,7 +458,7 @@ func (d Database) Search(pattern string) ([]SearchResult, error) {
 	defer res.Close()
 	for res.Next() {
 		var r SearchResult
-		err := res.Scan(&r.ID, &r.Server, &r.Name, &r.IP, &r.Hostname, &r.VPN, &r.Cookie, &r.Version, &r.Userinfo, &r.Time)
+		err := res.Scan(&r.ID, &r.Server, &r.Name, &r.IP, &r.Hostname, &r.VPN, &r.Cookie, &r.Version, &r.Userinfo, &r.Time, &r.Country, &r.ASN, &r.Profile)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning results: %v", err)
 		}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"database/sql"
+	"path"
+	"slices"
+	"testing"
+)
+
+// oldPlayerTable is the player table before any columns were added
+const oldPlayerTable = `
+CREATE TABLE "player" (
+	"id"		INTEGER,
+	"server"	TEXT,
+	"name"		TEXT,
+	"ip"		TEXT,
+	"hostname"	TEXT,
+	"vpn"		INTEGER,
+	"cookie"	TEXT,
+	"version"	TEXT,
+	"userinfo"	TEXT,
+	"time"		INTEGER,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);`
+
+// openOldDatabase makes a database with the original player table and a
+// row in it, then opens it the way the backend does
+func openOldDatabase(t *testing.T) Database {
+	t.Helper()
+	filename := path.Join(t.TempDir(), "old.sqlite")
+	old, err := sql.Open("sqlite3", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := old.Exec(oldPlayerTable); err != nil {
+		t.Fatal(err)
+	}
+	qry := "INSERT INTO player (server, name, ip, hostname, vpn, cookie, version, userinfo, time) VALUES (?,?,?,?,?,?,?,?,?)"
+	if _, err := old.Exec(qry, "test1", "claire", "192.0.2.1", "host.example.com", false, "abc", "q2pro", `\name\claire`, 100); err != nil {
+		t.Fatal(err)
+	}
+	old.Close()
+
+	d, err := Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { d.Handle.Close() })
+	return d
+}
+
+func TestMigratePlayerColumns(t *testing.T) {
+	d := openOldDatabase(t)
+	cols, err := tableColumns(d.Handle, "player")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"country", "asn"} {
+		if !slices.Contains(cols, want) {
+			t.Errorf("player columns after migrating = %v, missing %q", cols, want)
+		}
+	}
+	qry := "INSERT INTO player (server, name, ip, hostname, vpn, cookie, version, userinfo, time, country, asn) VALUES (?,?,?,?,?,?,?,?,?,?,?)"
+	if _, err := d.Handle.Exec(qry, "test1", "leon", "198.51.100.1", "", false, "def", "q2pro", "", 200, "US", 64496); err != nil {
+		t.Errorf("inserting a player with a country and ASN: %v", err)
+	}
+	var country string
+	var asn uint32
+	if err := d.Handle.QueryRow("SELECT country, asn FROM player WHERE name = 'claire'").Scan(&country, &asn); err != nil {
+		t.Fatal(err)
+	}
+	if country != "" || asn != 0 {
+		t.Errorf("existing player got country %q and ASN %d, want blanks", country, asn)
+	}
+}
+
+func TestSearchMigrated(t *testing.T) {
+	d := openOldDatabase(t)
+	qry := "INSERT INTO player (server, name, ip, hostname, vpn, cookie, version, userinfo, time, country, asn, profile, bot) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?)"
+	if _, err := d.Handle.Exec(qry, "test1", "claire2", "198.51.100.1", "", false, "def", "q2pro", "", 200, "US", 64496, 3, true); err != nil {
+		t.Fatal(err)
+	}
+	results, err := d.Search("claire")
+	if err != nil {
+		t.Fatalf("Search() on a migrated database: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Search() found %d players, want 2", len(results))
+	}
+	for _, r := range results {
+		if r.Name == "claire2" && (r.Country != "US" || r.ASN != 64496 || r.Profile != 3) {
+			t.Errorf("Search() result = %+v, want the new columns filled in", r)
+		}
+	}
+}
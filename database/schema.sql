@@ -27,6 +27,10 @@ CREATE TABLE IF NOT EXISTS "player" (
 	"version"	TEXT,
 	"userinfo"	TEXT,
 	"time"	INTEGER,
+	"country"	TEXT NOT NULL DEFAULT "",
+	"asn"	INTEGER NOT NULL DEFAULT 0,
+	"profile"	INTEGER NOT NULL DEFAULT 0,
+	"bot"	INTEGER NOT NULL DEFAULT 0,
 	PRIMARY KEY("id" AUTOINCREMENT)
 );
 CREATE INDEX "server_idx" ON "player" (
@@ -40,8 +44,288 @@ CREATE TABLE IF NOT EXISTS "player_stat" (
         "suicides"      INTEGER,
         "kdr"   INTEGER,
         "play_time"     INTEGER,
+        "time"  INTEGER NOT NULL DEFAULT 0,
         PRIMARY KEY("id" AUTOINCREMENT)
 );
 CREATE INDEX "player_idx" ON "player_stat" (
         "player"
 );
+CREATE TABLE IF NOT EXISTS "weapon_stat" (
+	"id"	INTEGER,
+	"session"	INTEGER NOT NULL DEFAULT 0,
+	"player"	INTEGER NOT NULL DEFAULT 0,
+	"means"	INTEGER NOT NULL DEFAULT 0,
+	"weapon"	TEXT NOT NULL DEFAULT "",
+	"frags"	INTEGER NOT NULL DEFAULT 0,
+	"deaths"	INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX "weapon_stat_session_idx" ON "weapon_stat" (
+	"session"
+);
+CREATE TABLE IF NOT EXISTS "profile" (
+	"id"	INTEGER,
+	"name"	TEXT NOT NULL DEFAULT "",
+	"created"	INTEGER NOT NULL DEFAULT 0,
+	"merged_into"	INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE TABLE IF NOT EXISTS "profile_link" (
+	"id"	INTEGER,
+	"profile"	INTEGER NOT NULL,
+	"kind"	TEXT NOT NULL,
+	"value"	TEXT NOT NULL,
+	"confidence"	REAL NOT NULL DEFAULT 0,
+	"first_seen"	INTEGER NOT NULL DEFAULT 0,
+	"last_seen"	INTEGER NOT NULL DEFAULT 0,
+	"count"	INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT),
+	UNIQUE("profile", "kind", "value")
+);
+CREATE INDEX "profile_link_value_idx" ON "profile_link" (
+	"kind",
+	"value"
+);
+CREATE TABLE IF NOT EXISTS "offense" (
+	"id"	INTEGER,
+	"player"	INTEGER NOT NULL DEFAULT 0,
+	"profile"	INTEGER NOT NULL DEFAULT 0,
+	"server"	TEXT NOT NULL DEFAULT "",
+	"kind"	TEXT NOT NULL DEFAULT "",
+	"rule"	TEXT NOT NULL DEFAULT "",
+	"detail"	TEXT NOT NULL DEFAULT "",
+	"time"	INTEGER NOT NULL DEFAULT 0,
+	"expires"	INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX "offense_time_idx" ON "offense" (
+	"time"
+);
+CREATE TABLE IF NOT EXISTS "match_result" (
+	"id"	INTEGER,
+	"server"	TEXT NOT NULL DEFAULT "",
+	"map"	TEXT NOT NULL DEFAULT "",
+	"reason"	TEXT NOT NULL DEFAULT "",
+	"start"	INTEGER NOT NULL DEFAULT 0,
+	"end"	INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX "match_result_server_idx" ON "match_result" (
+	"server",
+	"end"
+);
+CREATE TABLE IF NOT EXISTS "match_score" (
+	"id"	INTEGER,
+	"match"	INTEGER NOT NULL,
+	"player"	INTEGER NOT NULL DEFAULT 0,
+	"profile"	INTEGER NOT NULL DEFAULT 0,
+	"client"	INTEGER NOT NULL DEFAULT 0,
+	"name"	TEXT NOT NULL DEFAULT "",
+	"frags"	INTEGER NOT NULL DEFAULT 0,
+	"deaths"	INTEGER NOT NULL DEFAULT 0,
+	"suicides"	INTEGER NOT NULL DEFAULT 0,
+	"kdr"	REAL NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX "match_score_match_idx" ON "match_score" (
+	"match"
+);
+CREATE TABLE IF NOT EXISTS "rating" (
+	"id"	INTEGER,
+	"profile"	INTEGER NOT NULL,
+	"pool"	TEXT NOT NULL,
+	"rating"	REAL NOT NULL DEFAULT 1500,
+	"games"	INTEGER NOT NULL DEFAULT 0,
+	"updated"	INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT),
+	UNIQUE("profile", "pool")
+);
+CREATE TABLE IF NOT EXISTS "report" (
+	"id"	INTEGER,
+	"server"	TEXT NOT NULL DEFAULT "",
+	"time"	INTEGER NOT NULL DEFAULT 0,
+	"reporter"	INTEGER NOT NULL DEFAULT 0,
+	"reporter_profile"	INTEGER NOT NULL DEFAULT 0,
+	"reporter_name"	TEXT NOT NULL DEFAULT "",
+	"reporter_ip"	TEXT NOT NULL DEFAULT "",
+	"target"	INTEGER NOT NULL DEFAULT 0,
+	"target_profile"	INTEGER NOT NULL DEFAULT 0,
+	"target_name"	TEXT NOT NULL DEFAULT "",
+	"reason"	TEXT NOT NULL DEFAULT "",
+	"snapshot"	TEXT NOT NULL DEFAULT "",
+	"status"	TEXT NOT NULL DEFAULT "open",
+	"moderator"	TEXT NOT NULL DEFAULT "",
+	"note"	TEXT NOT NULL DEFAULT "",
+	"updated"	INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX "report_server_idx" ON "report" (
+	"server",
+	"status"
+);
+CREATE INDEX "report_reporter_idx" ON "report" (
+	"reporter_ip",
+	"time"
+);
+CREATE TABLE IF NOT EXISTS "reserved_name" (
+	"id"	INTEGER,
+	"server"	TEXT NOT NULL,
+	"kind"	TEXT NOT NULL DEFAULT "name",
+	"pattern"	TEXT NOT NULL,
+	"cookie"	TEXT NOT NULL DEFAULT "",
+	"profile"	INTEGER NOT NULL DEFAULT 0,
+	"account"	TEXT NOT NULL DEFAULT "",
+	"action"	TEXT NOT NULL DEFAULT "rename",
+	"owner"	TEXT NOT NULL DEFAULT "",
+	"created"	INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX "reserved_name_server_idx" ON "reserved_name" (
+	"server"
+);
+CREATE TABLE IF NOT EXISTS "link_code" (
+	"id"	INTEGER,
+	"code"	TEXT NOT NULL,
+	"account"	TEXT NOT NULL,
+	"created"	INTEGER NOT NULL,
+	"expires"	INTEGER NOT NULL,
+	"used"	INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX "link_code_code_idx" ON "link_code" (
+	"code"
+);
+CREATE TABLE IF NOT EXISTS "account_link" (
+	"id"	INTEGER,
+	"account"	TEXT NOT NULL,
+	"cookie"	TEXT NOT NULL,
+	"profile"	INTEGER NOT NULL DEFAULT 0,
+	"server"	TEXT NOT NULL DEFAULT "",
+	"created"	INTEGER NOT NULL,
+	PRIMARY KEY("id" AUTOINCREMENT),
+	UNIQUE("cookie")
+);
+CREATE INDEX "account_link_account_idx" ON "account_link" (
+	"account"
+);
+CREATE TABLE IF NOT EXISTS "admin_log" (
+	"id"	INTEGER,
+	"server"	TEXT NOT NULL DEFAULT "",
+	"actor"	TEXT NOT NULL DEFAULT "",
+	"action"	TEXT NOT NULL DEFAULT "",
+	"target"	TEXT NOT NULL DEFAULT "",
+	"profile"	INTEGER NOT NULL DEFAULT 0,
+	"detail"	TEXT NOT NULL DEFAULT "",
+	"time"	INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX "admin_log_server_idx" ON "admin_log" (
+	"server"
+);
+CREATE TABLE IF NOT EXISTS "player_note" (
+	"id"	INTEGER,
+	"server"	TEXT NOT NULL DEFAULT "",
+	"author"	TEXT NOT NULL DEFAULT "",
+	"profile"	INTEGER NOT NULL DEFAULT 0,
+	"cookie"	TEXT NOT NULL DEFAULT "",
+	"ip"	TEXT NOT NULL DEFAULT "",
+	"note"	TEXT NOT NULL DEFAULT "",
+	"created"	INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX "player_note_profile_idx" ON "player_note" (
+	"profile"
+);
+CREATE INDEX "player_note_cookie_idx" ON "player_note" (
+	"cookie"
+);
+CREATE INDEX "player_note_ip_idx" ON "player_note" (
+	"ip"
+);
+CREATE TABLE IF NOT EXISTS "vpn_cache" (
+	"id"	INTEGER,
+	"ip"	TEXT NOT NULL,
+	"vpn"	INTEGER NOT NULL DEFAULT 0,
+	"provider"	TEXT NOT NULL DEFAULT "",
+	"override"	TEXT NOT NULL DEFAULT "",
+	"author"	TEXT NOT NULL DEFAULT "",
+	"hits"	INTEGER NOT NULL DEFAULT 0,
+	"created"	INTEGER NOT NULL DEFAULT 0,
+	"expires"	INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT),
+	UNIQUE("ip")
+);
+CREATE TABLE IF NOT EXISTS "map_rotation" (
+	"id"	INTEGER,
+	"server"	TEXT NOT NULL,
+	"name"	TEXT NOT NULL,
+	"maps"	TEXT NOT NULL DEFAULT "",
+	"reverse"	INTEGER NOT NULL DEFAULT 0,
+	"active"	INTEGER NOT NULL DEFAULT 0,
+	"position"	INTEGER NOT NULL DEFAULT 0,
+	"iterations"	INTEGER NOT NULL DEFAULT 0,
+	"updated"	INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT),
+	UNIQUE("server", "name")
+);
+CREATE TABLE IF NOT EXISTS "map_play" (
+	"id"	INTEGER,
+	"server"	TEXT NOT NULL,
+	"map"	TEXT NOT NULL,
+	"start"	INTEGER NOT NULL,
+	"end"	INTEGER NOT NULL,
+	"player_seconds"	INTEGER NOT NULL DEFAULT 0,
+	"peak"	INTEGER NOT NULL DEFAULT 0,
+	"joined"	INTEGER NOT NULL DEFAULT 0,
+	"left_early"	INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX "map_play_server_idx" ON "map_play" (
+	"server",
+	"map"
+);
+CREATE TABLE IF NOT EXISTS "map_vote" (
+	"id"	INTEGER,
+	"server"	TEXT NOT NULL,
+	"map"	TEXT NOT NULL,
+	"kind"	TEXT NOT NULL,
+	"votes"	INTEGER NOT NULL DEFAULT 0,
+	"won"	INTEGER NOT NULL DEFAULT 0,
+	"time"	INTEGER NOT NULL,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX "map_vote_server_idx" ON "map_vote" (
+	"server",
+	"map"
+);
+CREATE TABLE IF NOT EXISTS "scheduled_job" (
+	"id"	INTEGER,
+	"owner"	TEXT NOT NULL,
+	"server"	TEXT NOT NULL DEFAULT "",
+	"grp"	TEXT NOT NULL DEFAULT "",
+	"name"	TEXT NOT NULL,
+	"spec"	TEXT NOT NULL,
+	"timezone"	TEXT NOT NULL DEFAULT "",
+	"action"	TEXT NOT NULL,
+	"args"	TEXT NOT NULL DEFAULT "",
+	"enabled"	INTEGER NOT NULL DEFAULT 1,
+	"author"	TEXT NOT NULL DEFAULT "",
+	"created"	INTEGER NOT NULL DEFAULT 0,
+	"last_run"	INTEGER NOT NULL DEFAULT 0,
+	"last_status"	TEXT NOT NULL DEFAULT "",
+	PRIMARY KEY("id" AUTOINCREMENT),
+	UNIQUE("owner", "server", "grp", "name")
+);
+CREATE TABLE IF NOT EXISTS "scheduled_run" (
+	"id"	INTEGER,
+	"job"	INTEGER NOT NULL,
+	"server"	TEXT NOT NULL,
+	"time"	INTEGER NOT NULL,
+	"success"	INTEGER NOT NULL DEFAULT 0,
+	"output"	TEXT NOT NULL DEFAULT "",
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+CREATE INDEX "scheduled_run_job_idx" ON "scheduled_run" (
+	"job",
+	"time"
+);
@@ -25,14 +25,21 @@ import (
 	pb "github.com/packetflinger/q2admind/proto"
 )
 
+// How many recent chat lines and frags each frontend keeps
+const HistoryLength = 20
+
 // This struct is partially populated by parsing a config file
 // on disk during init and the rest is filled in when the game
 // server actually connects
 type Frontend struct {
+	Admins        []string                // identities allowed to use in-game admin commands
 	AllowInvite   bool                    // honor invites from players
 	AllowTeleport bool                    // enable teleport functionality
 	APIKeys       *pb.ApiKeys             // keys generated for accessing this client
 	Challenge     []byte                  // random data for auth set by server
+	ChatGroup     string                  // frontends in the same group share a chat channel
+	ChatHistory   []string                // the most recent chat lines
+	ChatTrigger   string                  // chat starting with this is bridged to the group
 	Connected     bool                    // is it currently connected to us?
 	Connection    *net.Conn               // the tcp connection
 	ConnectTime   int64                   // unix timestamp when connection made
@@ -40,8 +47,14 @@ type Frontend struct {
 	Data          *database.Database      // pointer to database
 	DeleteProtect bool                    // can be deleted or not
 	Description   string                  // used in teleporting
+	DMFlags       int64                   // the server's own dmflags, for maps without any
+	DiscordChan   string                  // discord channel relayed into the game
+	DiscordEvents []string                // which events are posted to discord
+	DiscordHook   string                  // discord webhook events are posted to
 	Enabled       bool                    // actually use it
 	Encrypted     bool                    // are the messages AES encrypted?
+	Flood         *pb.FloodConfig         // chat/userinfo flood limits
+	FragHistory   []string                // the most recent frags
 	ID            int                     // this is the database index, remove later
 	InitVector    []byte                  // AES IV,
 	Invites       InviteBucket            // Invite throttling
@@ -49,6 +62,9 @@ type Frontend struct {
 	Log           *log.Logger             // log stuff here
 	LogFile       *os.File                // pointer to file so we can close when client disconnects
 	Maplist       *maprotator.MapList     // the maps for the frontend
+	MapPlay       *MapPlay                // how the current map is being played
+	MatchFrags    map[Duel]int            // frags between profiles in the current match
+	MatchStart    int64                   // when the current match started, 0 if between matches
 	MaxPlayers    int                     // total number
 	Message       message.Buffer          // incoming byte stream
 	MessageOut    message.Buffer          // outgoing byte stream
@@ -63,6 +79,7 @@ type Frontend struct {
 	PreviousMap   string                  // what was the last map?
 	PublicKey     *rsa.PublicKey          // supplied by owner via website
 	PublicKeyData string                  // the contents of the `key` file
+	RatingPool    string                  // frontends in the same pool share skill ratings
 	Rules         []*pb.Rule              // bans, mutes, etc
 	Server        any                     // pointer for circular reference back
 	ServerVars    map[string]string       // public server cvars
@@ -74,7 +91,27 @@ type Frontend struct {
 	UUID          string                  // random identifier
 	Verified      bool                    // client owner proved they're the owner
 	Version       int                     // q2admin library version
+	Vote          *pb.VoteConfig          // map voting
 	WebUsers      map[string]bool         // key is email addr, val is write access
+	WhoisFields   []string                // player details in-game whois can show
+}
+
+// Duel is a pairing of two profiles, one fragging the other
+type Duel struct {
+	Attacker int64
+	Victim   int64
+}
+
+// MapPlay tracks how many players are on the current map while it's loaded
+type MapPlay struct {
+	Map           string
+	Start         int64 // unix timestamp the map loaded
+	Counted       int64 // when PlayerSeconds was last brought up to date
+	Players       int   // players connected at Counted
+	PlayerSeconds int64 // players connected, summed over every second
+	Peak          int
+	Joined        int
+	Left          int // players who left before the map ended
 }
 
 // Each frontend has a small collection of invite tokens available. As players
@@ -198,17 +235,18 @@ func LoadSettings(name string, clientsDir string) (Frontend, error) {
 		fe.Enabled = !f.GetDisabled()
 		fe.AllowInvite = f.GetAllowInvite()
 		fe.AllowTeleport = f.GetAllowTeleport()
+		fe.RatingPool = f.GetRatingPool()
+		fe.WhoisFields = f.GetWhoisFields()
+		fe.ChatGroup = f.GetChatGroup()
+		fe.ChatTrigger = f.GetChatTrigger()
+		fe.DiscordHook = f.GetDiscordWebhook()
+		fe.DiscordChan = f.GetDiscordChannel()
+		fe.DiscordEvents = f.GetDiscordEvents()
+		fe.Flood = f.GetFlood()
+		fe.Admins = f.GetAdmins()
+		fe.Vote = f.GetVote()
 
-		tokens := strings.Split(f.GetAddress(), ":")
-		if len(tokens) == 2 {
-			fe.Port, err = strconv.Atoi(tokens[1])
-			if err != nil {
-				fe.Port = 27910
-			}
-		} else {
-			fe.Port = 27910
-		}
-		fe.IPAddress = tokens[0]
+		fe.IPAddress, fe.Port = ParseServerAddress(f.GetAddress())
 		fe.WebUsers = make(map[string]bool)
 		for _, user := range f.GetUsers() {
 			fe.WebUsers[user.GetEmail()] = (user.GetAccess() == "write")
@@ -320,16 +358,76 @@ func (fe *Frontend) ToProto() *pb.Frontend {
 		users = append(users, &pb.FrontendUser{Email: k, Access: access})
 	}
 	return &pb.Frontend{
-		Address:       fmt.Sprintf("%s:%d", fe.IPAddress, fe.Port),
-		Name:          fe.Name,
-		Uuid:          fe.UUID,
-		Description:   fe.Description,
-		Owner:         fe.Owner,
-		Verified:      fe.Verified,
-		AllowTeleport: fe.AllowTeleport,
-		AllowInvite:   fe.AllowInvite,
-		Users:         users,
+		Address:        fe.Address(),
+		Name:           fe.Name,
+		Uuid:           fe.UUID,
+		Description:    fe.Description,
+		Owner:          fe.Owner,
+		Verified:       fe.Verified,
+		AllowTeleport:  fe.AllowTeleport,
+		AllowInvite:    fe.AllowInvite,
+		Users:          users,
+		RatingPool:     fe.RatingPool,
+		WhoisFields:    fe.WhoisFields,
+		ChatGroup:      fe.ChatGroup,
+		ChatTrigger:    fe.ChatTrigger,
+		DiscordWebhook: fe.DiscordHook,
+		DiscordChannel: fe.DiscordChan,
+		DiscordEvents:  fe.DiscordEvents,
+		Flood:          fe.Flood,
+		Admins:         fe.Admins,
+		Vote:           fe.Vote,
+	}
+}
+
+// Pool gives the name of the rating pool this frontend's players are rated
+// in. Unless one is set, it's the mod the server is running.
+func (fe *Frontend) Pool() string {
+	if fe.RatingPool != "" {
+		return fe.RatingPool
+	}
+	if g := fe.ServerVars["gamename"]; g != "" {
+		return g
+	}
+	return "default"
+}
+
+// AddChat will remember a chat line, dropping the oldest once there are
+// more than HistoryLength
+func (fe *Frontend) AddChat(line string) {
+	fe.ChatHistory = appendHistory(fe.ChatHistory, line)
+}
+
+// AddFrag will remember a frag, dropping the oldest once there are more than
+// HistoryLength
+func (fe *Frontend) AddFrag(line string) {
+	fe.FragHistory = appendHistory(fe.FragHistory, line)
+}
+
+func appendHistory(history []string, line string) []string {
+	history = append(history, line)
+	if len(history) > HistoryLength {
+		history = history[len(history)-HistoryLength:]
+	}
+	return history
+}
+
+// Address gives the frontend's "host:port" in a form suitable for use with
+// a client "connect" command. IPv6 addresses are bracketed.
+func (fe *Frontend) Address() string {
+	return net.JoinHostPort(fe.IPAddress, strconv.Itoa(fe.Port))
+}
+
+// ParseServerAddress splits a server address ("192.0.2.4:27910",
+// "[2001:db8::4]:27910" or just a host) into host and port. The default
+// port of 27910 is used if it's missing or invalid.
+func ParseServerAddress(in string) (string, int) {
+	host, port := SplitAddress(in)
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		p = 27910
 	}
+	return host, p
 }
 
 // Find all players that match the name provided. Multiple players
@@ -396,11 +494,12 @@ func (fe *Frontend) AddPlayer(pl *Player) error {
 		return fmt.Errorf("error adding player to db: null player")
 	}
 	qry := `
-		INSERT INTO player (server, name, ip, hostname, vpn, cookie, version, userinfo, time) 
-		VALUES (?,?,?,?,?,?,?,?,?)`
+		INSERT INTO player (server, name, ip, hostname, vpn, cookie, version, userinfo, time, country, asn, bot) 
+		VALUES (?,?,?,?,?,?,?,?,?,?,?,?)`
 	res, err := fe.Data.Handle.Exec(
 		qry, pl.Frontend.Name, pl.Name, pl.IP, pl.Hostname, pl.VPN,
-		pl.Cookie, pl.Version, pl.Userinfo, time.Now().Unix(),
+		pl.Cookie, pl.Version, pl.Userinfo, time.Now().Unix(), pl.Country,
+		pl.ASN, pl.IsBot(),
 	)
 	if err != nil {
 		return fmt.Errorf("error inserting player %s[%s]: %v", pl.Name, pl.IP, err)
@@ -413,6 +512,35 @@ func (fe *Frontend) AddPlayer(pl *Player) error {
 	return nil
 }
 
+// UpdatePlayerCookie will record the player's cookie on their existing
+// database record. Players without a cookie are given one after they connect,
+// so it's not known when the record is first inserted.
+func (fe *Frontend) UpdatePlayerCookie(pl *Player) error {
+	if pl == nil {
+		return fmt.Errorf("error updating player cookie: null player")
+	}
+	qry := "UPDATE player SET cookie = ? WHERE id = ?"
+	_, err := fe.Data.Handle.Exec(qry, pl.Cookie, pl.Database_ID)
+	if err != nil {
+		return fmt.Errorf("error updating cookie for player %s[%d]: %v", pl.Name, pl.Database_ID, err)
+	}
+	return nil
+}
+
+// UpdatePlayerVPN records that a player is connecting from a VPN, which is
+// found after they're added
+func (fe *Frontend) UpdatePlayerVPN(pl *Player) error {
+	if pl == nil {
+		return fmt.Errorf("error updating player vpn: null player")
+	}
+	qry := "UPDATE player SET vpn = ? WHERE id = ?"
+	_, err := fe.Data.Handle.Exec(qry, pl.VPN, pl.Database_ID)
+	if err != nil {
+		return fmt.Errorf("error updating vpn for player %s[%d]: %v", pl.Name, pl.Database_ID, err)
+	}
+	return nil
+}
+
 // Get the ID used in the database of a particular frontend. If this is a new
 // frontend, ensure it's setup correctly in the database whether it's an old
 // existing one or brand new.
@@ -528,9 +656,9 @@ func (fe *Frontend) CalculateKDR(cid int) float64 {
 }
 
 // WritePlayer will write a player's stats to the database. This includes data
-// like their frag counts, death counts, KDR, etc. This will typically happen
-// when the player quits (or teleports) or in the event the backend server is
-// shutdown.
+// like their frag counts, death counts, KDR, etc. and a breakdown of frags
+// and deaths by weapon. This will typically happen when the player quits (or
+// teleports) or in the event the backend server is shutdown.
 func (fe *Frontend) WritePlayer(client int) error {
 	pls := fe.Players
 	if client < 0 || client >= fe.MaxPlayers {
@@ -540,22 +668,42 @@ func (fe *Frontend) WritePlayer(client int) error {
 		return nil
 	}
 	p := pls[client]
+	now := time.Now().Unix()
 	qry := `
 			INSERT INTO player_stat
-				(player, frags, deaths, suicides, kdr, play_time)
+				(player, frags, deaths, suicides, kdr, play_time, time)
 			VALUES
-				(?,?,?,?,?,?)`
-	_, err := fe.Data.Handle.Exec(qry,
+				(?,?,?,?,?,?,?)`
+	res, err := fe.Data.Handle.Exec(qry,
 		p.Database_ID,
 		p.Frags,
 		p.Deaths,
 		p.Suicides,
 		p.KDR,
-		(time.Now().Unix() - p.ConnectTime),
+		(now - p.ConnectTime),
+		now,
 	)
 	if err != nil {
 		return fmt.Errorf("error writing player %s:%d[%d]: %v", fe.Name, p.ClientID, p.Database_ID, err)
 	}
+	if len(p.Weapons) == 0 {
+		return nil
+	}
+	session, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error getting stat id for player %s:%d[%d]: %v", fe.Name, p.ClientID, p.Database_ID, err)
+	}
+	qry = `
+			INSERT INTO weapon_stat
+				(session, player, means, weapon, frags, deaths)
+			VALUES
+				(?,?,?,?,?,?)`
+	for means, st := range p.Weapons {
+		_, err := fe.Data.Handle.Exec(qry, session, p.Database_ID, means, MeansName(means), st.Frags, st.Deaths)
+		if err != nil {
+			return fmt.Errorf("error writing weapon stats %s:%d[%d]: %v", fe.Name, p.ClientID, p.Database_ID, err)
+		}
+	}
 	return nil
 }
 
@@ -4,6 +4,7 @@ import (
 	//"cmp"
 	"reflect"
 	"sort"
+	"strconv"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -288,3 +289,16 @@ func TestCalculateKDR(t *testing.T) {
 		})
 	}
 }
+
+func TestAddChat(t *testing.T) {
+	fe := &Frontend{}
+	for i := range HistoryLength + 5 {
+		fe.AddChat(strconv.Itoa(i))
+	}
+	if len(fe.ChatHistory) != HistoryLength {
+		t.Fatalf("AddChat() kept %d lines, want %d", len(fe.ChatHistory), HistoryLength)
+	}
+	if fe.ChatHistory[0] != "5" || fe.ChatHistory[HistoryLength-1] != strconv.Itoa(HistoryLength+4) {
+		t.Errorf("AddChat() kept %q...%q, want the newest lines", fe.ChatHistory[0], fe.ChatHistory[HistoryLength-1])
+	}
+}
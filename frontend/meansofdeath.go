@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 )
 
 // represents a frag
@@ -14,6 +15,12 @@ type Death struct {
 	Solo     bool // self-frag
 }
 
+// Frags and deaths for a single means of death
+type WeaponStat struct {
+	Frags  int
+	Deaths int
+}
+
 // All possible means of death
 const (
 	ModUnknown = iota
@@ -261,11 +268,29 @@ func (fe *Frontend) CalculateDeath(obit string) (*Death, error) {
 
 // MeansToString will return a string representation of the means of death.
 func (d *Death) MeansToString() string {
-	var means string
 	if d == nil {
 		return "unknown"
 	}
-	switch d.Means {
+	return MeansName(d.Means)
+}
+
+// WeaponNames lists the distinct names MeansName() can return, in the order
+// of their means of death.
+func WeaponNames() []string {
+	var names []string
+	for mod := ModUnknown; mod <= ModFriendlyFire; mod++ {
+		if n := MeansName(mod); !slices.Contains(names, n) {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// MeansName gives the name of the weapon (or whatever else) responsible for a
+// means of death. Splash damage is grouped with the weapon that caused it.
+func MeansName(mod int) string {
+	var means string
+	switch mod {
 	case ModBlaster:
 		means = "blaster"
 	case ModShotgun:
@@ -341,3 +366,30 @@ func (d *Death) MeansToString() string {
 	}
 	return means
 }
+
+// Tally will add this death to the per-means counters of the players
+// involved. Self and environmental frags only count as a death for the
+// victim.
+//
+// Called from ParseObituary()
+func (d *Death) Tally() {
+	if d == nil {
+		return
+	}
+	if d.Murderer != nil && !d.Solo {
+		if d.Murderer.Weapons == nil {
+			d.Murderer.Weapons = make(map[int]WeaponStat)
+		}
+		st := d.Murderer.Weapons[d.Means]
+		st.Frags++
+		d.Murderer.Weapons[d.Means] = st
+	}
+	if d.Victim != nil {
+		if d.Victim.Weapons == nil {
+			d.Victim.Weapons = make(map[int]WeaponStat)
+		}
+		st := d.Victim.Weapons[d.Means]
+		st.Deaths++
+		d.Victim.Weapons[d.Means] = st
+	}
+}
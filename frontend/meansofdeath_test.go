@@ -56,3 +56,59 @@ func TestMOD1(t *testing.T) {
 		t.Error(d)
 	}
 }
+
+func TestTally(t *testing.T) {
+	fe := MODTestSetup()
+	obits := []string{
+		"claire ate scarred's rocket",
+		"claire almost dodged scarred's rocket",
+		"scarred was railed by claire",
+		"claire does a back flip into the lava",
+	}
+	for _, obit := range obits {
+		d, err := fe.CalculateDeath(obit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d.Tally()
+	}
+
+	claire, scarred := fe.Players[0], fe.Players[1]
+	tests := []struct {
+		desc  string
+		stats map[int]WeaponStat
+		means int
+		want  WeaponStat
+	}{
+		{"claire rocket", claire.Weapons, ModRocket, WeaponStat{Deaths: 1}},
+		{"claire splash", claire.Weapons, ModRSplash, WeaponStat{Deaths: 1}},
+		{"claire rail", claire.Weapons, ModRailgun, WeaponStat{Frags: 1}},
+		{"claire lava", claire.Weapons, ModLava, WeaponStat{Deaths: 1}},
+		{"scarred rocket", scarred.Weapons, ModRocket, WeaponStat{Frags: 1}},
+		{"scarred rail", scarred.Weapons, ModRailgun, WeaponStat{Deaths: 1}},
+		{"scarred lava", scarred.Weapons, ModLava, WeaponStat{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := tc.stats[tc.means]; got != tc.want {
+				t.Errorf("Weapons[%d] = %+v, want %+v", tc.means, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWeaponNames(t *testing.T) {
+	names := WeaponNames()
+	seen := make(map[string]bool)
+	for _, n := range names {
+		if seen[n] {
+			t.Errorf("WeaponNames() has %q more than once", n)
+		}
+		seen[n] = true
+	}
+	for _, want := range []string{"rocket launcher", "railgun", "lava", "unknown"} {
+		if !seen[want] {
+			t.Errorf("WeaponNames() is missing %q", want)
+		}
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"crypto/md5"
 	"fmt"
 	"log"
+	"net"
+	"net/netip"
 	"strings"
 
 	pb "github.com/packetflinger/q2admind/proto"
@@ -13,9 +15,14 @@ import (
 // Each player on a game server has one of these.
 // Each game server has a slice of all current players
 type Player struct {
-	ClientID         int // ID on the gameserver (0-maxplayers)
+	Account          string  // linked website account (email)
+	ASN              uint32  // autonomous system number of their network
+	ASNOrg           string  // who owns the ASN
+	BridgeChats      []int64 // when their recent bridged messages were sent
+	ClientID         int     // ID on the gameserver (0-maxplayers)
 	ConnectTime      int64
 	Cookie           string // a unique value to identify players
+	Country          string // ISO 3166-1 alpha-2 code from geoip
 	Database_ID      int64
 	Deaths           int
 	FloodInfo        *pb.FloodInfo
@@ -28,14 +35,23 @@ type Player struct {
 	IP               string
 	KDR              float64 // kill:death ratio
 	LastInvite       int64
+	LastReport       int64 // when they last reported someone
 	LastTeleport     int64 // actually going
 	LastTeleportList int64 // viewing the big list of destinations
+	LinkAttempts     int   // link codes tried this connection
+	MatchBase        Score // counters when the current match started
 	Muted            bool  // is this player muted?
 	Name             string
+	NameDeadline     int64 // when they have to stop using a reserved name
+	NameRenamed      bool  // already force-renamed for using a reserved name
 	Port             int
+	ProfileID        int64      // the identity profile this player is linked to
+	ProfileScore     float64    // confidence of the profile link (0-1)
+	Rating           float64    // skill rating in the frontend's pool
 	Rules            []*pb.Rule // rules that match this player
 	Stifled          bool
-	StifleLength     int // seconds
+	StifledUntil     int64 // when a temporary stifle ends, 0 for the whole connection
+	StifleLength     int   // seconds
 	Suicides         int
 	Teleports        int
 	Userinfo         string
@@ -43,6 +59,14 @@ type Player struct {
 	UserInfoHash     string // md5 hash for checking if UI changed
 	Version          string // q2 client flavor + version
 	VPN              bool
+	Weapons          map[int]WeaponStat // frags/deaths keyed by means of death
+}
+
+// Score is a snapshot of a player's frag counters
+type Score struct {
+	Frags    int
+	Deaths   int
+	Suicides int
 }
 
 // Get a pointer to a player based on a client number. This pointer is the
@@ -63,9 +87,9 @@ func (fe *Frontend) FindPlayer(client int) (*Player, error) {
 // A player hash is a way of uniquely identifiying a player.
 //
 // It's the first 16 characters of an MD5 hash of their
-// name + skin + fov + partial IP. The idea is to identify
-// players with the same name as different people, so someone can't
-// impersonate someone else and tank their stats.
+// name + skin + fov + partial IP (/24 for IPv4, /64 for IPv6). The idea is
+// to identify players with the same name as different people, so someone
+// can't impersonate someone else and tank their stats.
 //
 // Players can specify a player hash in their Userinfo rather than
 // having one generated. This way they can use different names and
@@ -82,8 +106,17 @@ func (player *Player) LoadPlayerHash() {
 	if phash != "" {
 		player.UserInfoHash = phash
 	} else {
-		ipslice := strings.Split(player.IP, ".")
-		ip := fmt.Sprintf("%s.%s.%s", ipslice[0], ipslice[1], ipslice[2])
+		// IPv4 keeps the original "a.b.c" format so existing hashes
+		// don't change
+		ip := player.IP
+		prefix, err := AddressPrefix(player.IP)
+		if err == nil {
+			ip = prefix.String()
+			if prefix.Addr().Is4() {
+				a := prefix.Addr().As4()
+				ip = fmt.Sprintf("%d.%d.%d", a[0], a[1], a[2])
+			}
+		}
 
 		pt := []byte(fmt.Sprintf(
 			"%s-%s-%s-%s",
@@ -147,15 +180,60 @@ func UserinfoMap(ui string) map[string]string {
 	}
 
 	// special case: split the IP value into IP and Port
-	ip := info["ip"]
-	ipport := strings.Split(ip, ":")
-	if len(ipport) >= 2 {
-		info["port"] = ipport[1]
-		info["ip"] = ipport[0]
+	if ip, ok := info["ip"]; ok {
+		addr, port := SplitAddress(ip)
+		info["ip"] = addr
+		if port != "" {
+			info["port"] = port
+		}
 	}
 	return info
 }
 
+// SplitAddress will separate the address and port from the `ip` userinfo
+// value. IPv4 clients look like "192.0.2.4:27901", IPv6 clients are bracketed
+// like "[2001:db8::4]:27901". Values without a port (like "loopback" or a bare
+// IPv6 address) are returned as-is with an empty port.
+func SplitAddress(in string) (string, string) {
+	if strings.HasPrefix(in, "[") || strings.Count(in, ":") == 1 {
+		addr, port, err := net.SplitHostPort(in)
+		if err == nil {
+			return addr, port
+		}
+		return strings.Trim(in, "[]"), ""
+	}
+	return in, ""
+}
+
+// AddressPrefix will return the network a player's address belongs to. For
+// IPv4 this is the /24, for IPv6 it's the /64 (typically a single customer
+// allocation). IPv4-mapped IPv6 addresses are treated as IPv4.
+func AddressPrefix(ip string) (netip.Prefix, error) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap().WithZone("")
+	bits := 24
+	if addr.Is6() {
+		bits = 64
+	}
+	return addr.Prefix(bits)
+}
+
+// IsBot guesses whether a player is a bot rather than a person. Bots are
+// spawned by the game mod itself, so they have no real address.
+func (player Player) IsBot() bool {
+	if player.UserinfoMap["bot"] != "" && player.UserinfoMap["bot"] != "0" {
+		return true
+	}
+	switch player.IP {
+	case "", "loopback", "bot":
+		return true
+	}
+	return false
+}
+
 // Find the first player using the provided name
 // on this particular client.
 //
@@ -0,0 +1,128 @@
+package frontend
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUserinfoMap(t *testing.T) {
+	tests := []struct {
+		name     string
+		userinfo string
+		want     map[string]string
+	}{
+		{
+			name:     "ipv4 with port",
+			userinfo: `\name\claire\ip\192.0.2.4:27901`,
+			want:     map[string]string{"name": "claire", "ip": "192.0.2.4", "port": "27901"},
+		},
+		{
+			name:     "ipv6 with port",
+			userinfo: `\name\claire\ip\[2001:db8::4]:27901`,
+			want:     map[string]string{"name": "claire", "ip": "2001:db8::4", "port": "27901"},
+		},
+		{
+			name:     "ipv6 without port",
+			userinfo: `\name\claire\ip\2001:db8::4`,
+			want:     map[string]string{"name": "claire", "ip": "2001:db8::4"},
+		},
+		{
+			name:     "loopback",
+			userinfo: `\name\claire\ip\loopback`,
+			want:     map[string]string{"name": "claire", "ip": "loopback"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := UserinfoMap(tc.userinfo)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("UserinfoMap(%q) mismatch (-want +got):\n%s", tc.userinfo, diff)
+			}
+		})
+	}
+}
+
+func TestAddressPrefix(t *testing.T) {
+	tests := []struct {
+		name    string
+		ip      string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "ipv4",
+			ip:   "192.0.2.4",
+			want: "192.0.2.0/24",
+		},
+		{
+			name: "ipv6",
+			ip:   "2001:db8:1:2:3:4:5:6",
+			want: "2001:db8:1:2::/64",
+		},
+		{
+			name: "ipv4 mapped",
+			ip:   "::ffff:192.0.2.4",
+			want: "192.0.2.0/24",
+		},
+		{
+			name:    "invalid",
+			ip:      "loopback",
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := AddressPrefix(tc.ip)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("AddressPrefix(%q) error = %v, wantErr %v", tc.ip, err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if got.String() != tc.want {
+				t.Errorf("AddressPrefix(%q) = %q, want %q", tc.ip, got.String(), tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadPlayerHashIPv6(t *testing.T) {
+	userinfo := map[string]string{"name": "claire", "skin": "female/athena", "fov": "90"}
+	p1 := &Player{IP: "2001:db8:1:2::10", UserinfoMap: userinfo}
+	p2 := &Player{IP: "2001:db8:1:2::20", UserinfoMap: userinfo}
+	p3 := &Player{IP: "2001:db8:1:3::10", UserinfoMap: userinfo}
+	p1.LoadPlayerHash()
+	p2.LoadPlayerHash()
+	p3.LoadPlayerHash()
+	if p1.UserInfoHash == "" {
+		t.Fatalf("LoadPlayerHash() left an empty hash")
+	}
+	if p1.UserInfoHash != p2.UserInfoHash {
+		t.Errorf("same /64 got different hashes: %q, %q", p1.UserInfoHash, p2.UserInfoHash)
+	}
+	if p1.UserInfoHash == p3.UserInfoHash {
+		t.Errorf("different /64 got the same hash: %q", p1.UserInfoHash)
+	}
+}
+
+func TestParseServerAddress(t *testing.T) {
+	tests := []struct {
+		in       string
+		wantHost string
+		wantPort int
+	}{
+		{in: "192.0.2.4:27911", wantHost: "192.0.2.4", wantPort: 27911},
+		{in: "[2001:db8::4]:27911", wantHost: "2001:db8::4", wantPort: 27911},
+		{in: "2001:db8::4", wantHost: "2001:db8::4", wantPort: 27910},
+		{in: "q2.example.net", wantHost: "q2.example.net", wantPort: 27910},
+	}
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			host, port := ParseServerAddress(tc.in)
+			if host != tc.wantHost || port != tc.wantPort {
+				t.Errorf("ParseServerAddress(%q) = %q, %d, want %q, %d", tc.in, host, port, tc.wantHost, tc.wantPort)
+			}
+		})
+	}
+}
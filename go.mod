@@ -10,6 +10,7 @@ require (
 	github.com/gorilla/mux v1.8.0
 	github.com/gorilla/websocket v1.5.0
 	github.com/mattn/go-sqlite3 v1.14.12
+	github.com/oschwald/maxminddb-golang v1.13.1
 	github.com/packetflinger/libq2 v1.0.277
 	github.com/ravener/discord-oauth2 v0.0.0-20220615092331-f6a9839c223e
 	golang.org/x/crypto v0.43.0
@@ -11,6 +11,10 @@ import (
 	pb "github.com/packetflinger/q2admind/proto"
 )
 
+// DefaultDMFlags is what quake 2 starts with, used when a server's own
+// dmflags aren't known
+const DefaultDMFlags = 0
+
 type MapList struct{ *pb.MapRotation }
 
 func NewMapRotation(name string, maps []string) *MapList {
@@ -55,27 +59,127 @@ func NewMapRotation(name string, maps []string) *MapList {
 	return &MapList{rot}
 }
 
-// Get the next map in the rotation. If we're at the end, start over at the
-// beginning.
-func (m *MapList) Next() *pb.Map {
-	if m.GetSize() == 0 {
+// Get the next map in the rotation for the number of players connected.
+// Maps meant for more or fewer players are skipped, limits of 0 mean no
+// limit. If nothing fits, the map whose limits are closest wins, earlier in
+// the rotation on a tie. If we're at the end, start over at the beginning.
+func (m *MapList) Next(players int) *pb.Map {
+	size := int(m.GetSize())
+	if size == 0 {
 		return nil
 	}
-	var nextIndex int32
-	switch m.Direction {
-	case pb.MapRotationDirection_MapRotationDirectionForward:
-		nextIndex = m.GetIndex() + 1
-		if nextIndex == m.GetSize() {
-			nextIndex = 0
+	step := 1
+	if m.GetDirection() == pb.MapRotationDirection_MapRotationDirectionReverse {
+		step = -1
+	}
+	index := int(m.GetIndex())
+	next, closest := -1, 0
+	for i := 1; i <= size; i++ {
+		candidate := ((index+step*i)%size + size) % size
+		d := playerDistance(m.GetMaps()[candidate], players)
+		if next == -1 || d < closest {
+			next, closest = candidate, d
 		}
-	case pb.MapRotationDirection_MapRotationDirectionReverse:
-		nextIndex = m.GetIndex() - 1
-		if nextIndex == -1 {
-			nextIndex = m.GetSize() - 1
+		if d == 0 {
+			break
+		}
+	}
+	if (step > 0 && next <= index) || (step < 0 && next >= index) {
+		m.Iterations++
+	}
+	m.Index = int32(next)
+	return m.GetMaps()[next]
+}
+
+// Candidates are up to n maps coming up in the rotation that fit the number
+// of players connected, in the order they'd be played. Maps named skip
+// (usually the one just played) aren't included.
+func (m *MapList) Candidates(players int, n int, skip string) []*pb.Map {
+	size := int(m.GetSize())
+	step := 1
+	if m.GetDirection() == pb.MapRotationDirection_MapRotationDirectionReverse {
+		step = -1
+	}
+	var maps []*pb.Map
+	for i := 1; i <= size && len(maps) < n; i++ {
+		candidate := m.GetMaps()[((int(m.GetIndex())+step*i)%size+size)%size]
+		if candidate.GetName() == skip || playerDistance(candidate, players) != 0 {
+			continue
 		}
+		maps = append(maps, candidate)
+	}
+	return maps
+}
+
+// Select moves the rotation to a map, as if Next had picked it. Returns
+// false if the map isn't in the rotation.
+func (m *MapList) Select(mp *pb.Map) bool {
+	index := int(m.GetIndex())
+	for i, candidate := range m.GetMaps() {
+		if candidate != mp {
+			continue
+		}
+		reverse := m.GetDirection() == pb.MapRotationDirection_MapRotationDirectionReverse
+		if (!reverse && i <= index) || (reverse && i >= index) {
+			m.Iterations++
+		}
+		m.Index = int32(i)
+		return true
+	}
+	return false
+}
+
+// playerDistance is how many players short or over a map's limits we are,
+// 0 if it fits
+func playerDistance(m *pb.Map, players int) int {
+	min, max := int(m.GetMinimumPlayers()), int(m.GetMaximumPlayers())
+	if players < min {
+		return min - players
+	}
+	if max > 0 && players > max {
+		return players - max
+	}
+	return 0
+}
+
+// Commands are what to run on the server console to change to a map. A
+// map's flags are its dmflags, maps without any get the server's usual
+// dmflags so ones left by the previous map don't carry over.
+func Commands(m *pb.Map, dmflags int64) []string {
+	if m.GetName() == "" {
+		return nil
+	}
+	if m.GetFlags() != 0 {
+		dmflags = m.GetFlags()
+	}
+	return []string{
+		fmt.Sprintf("dmflags %d", dmflags),
+		fmt.Sprintf("gamemap %s", m.GetName()),
+	}
+}
+
+// Line is a map written the way NewMapRotation reads it, limits and flags
+// only when they're needed
+func Line(m *pb.Map) string {
+	fields := []string{m.GetName()}
+	switch {
+	case m.GetFlags() != 0:
+		fields = append(fields, strconv.Itoa(int(m.GetMinimumPlayers())), strconv.Itoa(int(m.GetMaximumPlayers())), strconv.FormatInt(m.GetFlags(), 10))
+	case m.GetMaximumPlayers() != 0:
+		fields = append(fields, strconv.Itoa(int(m.GetMinimumPlayers())), strconv.Itoa(int(m.GetMaximumPlayers())))
+	case m.GetMinimumPlayers() != 0:
+		fields = append(fields, strconv.Itoa(int(m.GetMinimumPlayers())))
+	}
+	return strings.Join(fields, " ")
+}
+
+// Lines are the rotation's maps as NewMapRotation reads them
+func (m *MapList) Lines() []string {
+	var lines []string
+	for _, mp := range m.GetMaps() {
+		lines = append(lines, Line(mp))
 	}
-	m.Index = nextIndex
-	return m.GetMaps()[nextIndex]
+	return lines
 }
 
 // Fisher-Yates shuffle
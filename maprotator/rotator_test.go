@@ -80,10 +80,27 @@ func TestMapRotation(t *testing.T) {
 }
 
 func TestNext(t *testing.T) {
+	sized := func(index int32, dir pb.MapRotationDirection) *pb.MapRotation {
+		return &pb.MapRotation{
+			Name:      "name",
+			Size:      4,
+			Index:     index,
+			Direction: dir,
+			Maps: []*pb.Map{
+				{Name: "q2dm1", MaximumPlayers: 4},
+				{Name: "q2dm2", MinimumPlayers: 4, MaximumPlayers: 8},
+				{Name: "q2dm8", MinimumPlayers: 6},
+				{Name: "q2dm3", MinimumPlayers: 2, MaximumPlayers: 6},
+			},
+		}
+	}
+	forward := pb.MapRotationDirection_MapRotationDirectionForward
+	reverse := pb.MapRotationDirection_MapRotationDirectionReverse
 	tests := []struct {
-		name string
-		rot  *pb.MapRotation
-		want *pb.Map
+		name    string
+		rot     *pb.MapRotation
+		players int
+		want    *pb.Map
 	}{
 		{
 			name: "nil",
@@ -158,10 +175,90 @@ func TestNext(t *testing.T) {
 				Name: "q2dm3",
 			},
 		},
+		{
+			name:    "fits next",
+			rot:     sized(0, forward),
+			players: 5,
+			want:    &pb.Map{Name: "q2dm2", MinimumPlayers: 4, MaximumPlayers: 8},
+		},
+		{
+			name:    "minimum is inclusive",
+			rot:     sized(0, forward),
+			players: 4,
+			want:    &pb.Map{Name: "q2dm2", MinimumPlayers: 4, MaximumPlayers: 8},
+		},
+		{
+			name:    "skip too few players",
+			rot:     sized(0, forward),
+			players: 2,
+			want:    &pb.Map{Name: "q2dm3", MinimumPlayers: 2, MaximumPlayers: 6},
+		},
+		{
+			name:    "maximum is inclusive",
+			rot:     sized(1, forward),
+			players: 6,
+			want:    &pb.Map{Name: "q2dm8", MinimumPlayers: 6},
+		},
+		{
+			name:    "no maximum",
+			rot:     sized(1, forward),
+			players: 30,
+			want:    &pb.Map{Name: "q2dm8", MinimumPlayers: 6},
+		},
+		{
+			name:    "skip too many players, wrapping",
+			rot:     sized(2, forward),
+			players: 7,
+			want:    &pb.Map{Name: "q2dm2", MinimumPlayers: 4, MaximumPlayers: 8},
+		},
+		{
+			name:    "empty server",
+			rot:     sized(3, forward),
+			players: 0,
+			want:    &pb.Map{Name: "q2dm1", MaximumPlayers: 4},
+		},
+		{
+			name:    "reverse skip",
+			rot:     sized(0, reverse),
+			players: 1,
+			want:    &pb.Map{Name: "q2dm1", MaximumPlayers: 4},
+		},
+		{
+			name:    "reverse fits",
+			rot:     sized(3, reverse),
+			players: 5,
+			want:    &pb.Map{Name: "q2dm2", MinimumPlayers: 4, MaximumPlayers: 8},
+		},
+		{
+			name: "nothing fits, closest",
+			rot: &pb.MapRotation{
+				Size: 3,
+				Maps: []*pb.Map{
+					{Name: "q2dm1", MaximumPlayers: 2},
+					{Name: "q2dm2", MinimumPlayers: 12},
+					{Name: "q2dm3", MaximumPlayers: 4},
+				},
+			},
+			players: 7,
+			want:    &pb.Map{Name: "q2dm3", MaximumPlayers: 4},
+		},
+		{
+			name: "nothing fits, tie goes to next",
+			rot: &pb.MapRotation{
+				Size: 3,
+				Maps: []*pb.Map{
+					{Name: "q2dm1", MaximumPlayers: 2},
+					{Name: "q2dm2", MinimumPlayers: 12},
+					{Name: "q2dm3", MinimumPlayers: 12},
+				},
+			},
+			players: 7,
+			want:    &pb.Map{Name: "q2dm2", MinimumPlayers: 12},
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			got := (&MapList{tc.rot}).Next()
+			got := (&MapList{tc.rot}).Next(tc.players)
 			options := []cmp.Option{
 				protocmp.Transform(),
 				protocmp.IgnoreFields(&pb.MapRotation{}, "uuid"),
@@ -172,3 +269,94 @@ func TestNext(t *testing.T) {
 		})
 	}
 }
+
+func TestNextIterations(t *testing.T) {
+	m := NewMapRotation("", []string{"q2dm1", "q2dm2 10", "q2dm3"})
+	for range 4 {
+		m.Next(2)
+	}
+	if m.GetIndex() != 0 || m.GetIterations() != 2 {
+		t.Errorf("Next() x4 index %d, iterations %d, want 0, 2", m.GetIndex(), m.GetIterations())
+	}
+}
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *pb.Map
+		want []string
+	}{
+		{name: "nil", m: nil, want: nil},
+		{name: "no flags", m: &pb.Map{Name: "q2dm1"}, want: []string{"dmflags 16", "gamemap q2dm1"}},
+		{name: "dmflags", m: &pb.Map{Name: "q2dm1", Flags: 1040}, want: []string{"dmflags 1040", "gamemap q2dm1"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(Commands(tc.m, 16), tc.want); diff != "" {
+				t.Errorf("Commands(%v) diff (-got +want):\n%s", tc.m, diff)
+			}
+		})
+	}
+}
+
+func TestLines(t *testing.T) {
+	in := []string{"q2dm1", "q2dm2 4", "q2dm3 0 8", "q2dm8 2 0 1040"}
+	if diff := cmp.Diff(NewMapRotation("", in).Lines(), in); diff != "" {
+		t.Errorf("Lines() diff (-got +want):\n%s", diff)
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	maps := []string{"q2dm1", "q2dm2 10", "q2dm3", "q2dm4 0 4", "q2dm5"}
+	names := func(maps []*pb.Map) []string {
+		var n []string
+		for _, m := range maps {
+			n = append(n, m.GetName())
+		}
+		return n
+	}
+	tests := []struct {
+		name    string
+		index   int32
+		reverse bool
+		players int
+		n       int
+		skip    string
+		want    []string
+	}{
+		{name: "fits", index: 0, players: 2, n: 3, want: []string{"q2dm3", "q2dm4", "q2dm5"}},
+		{name: "wraps", index: 3, players: 6, n: 3, want: []string{"q2dm5", "q2dm1", "q2dm3"}},
+		{name: "skip", index: 0, players: 6, n: 3, skip: "q2dm1", want: []string{"q2dm3", "q2dm5"}},
+		{name: "reverse", index: 0, reverse: true, players: 2, n: 2, want: []string{"q2dm5", "q2dm4"}},
+		{name: "none fit", index: 0, players: 2, n: 0, want: nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewMapRotation("", maps)
+			m.Index = tc.index
+			if tc.reverse {
+				m.Direction = pb.MapRotationDirection_MapRotationDirectionReverse
+			}
+			if diff := cmp.Diff(names(m.Candidates(tc.players, tc.n, tc.skip)), tc.want); diff != "" {
+				t.Errorf("Candidates() diff (-got +want):\n%s", diff)
+			}
+			if m.GetIndex() != tc.index {
+				t.Errorf("Candidates() moved the rotation to %d", m.GetIndex())
+			}
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	m := NewMapRotation("", []string{"q2dm1", "q2dm2", "q2dm3"})
+	m.Index = 1
+	if !m.Select(m.GetMaps()[2]) || m.GetIndex() != 2 || m.GetIterations() != 0 {
+		t.Errorf("Select() forward index %d, iterations %d, want 2, 0", m.GetIndex(), m.GetIterations())
+	}
+	if !m.Select(m.GetMaps()[0]) || m.GetIndex() != 0 || m.GetIterations() != 1 {
+		t.Errorf("Select() wrapping index %d, iterations %d, want 0, 1", m.GetIndex(), m.GetIterations())
+	}
+	if m.Select(&pb.Map{Name: "q2dm1"}) {
+		t.Error("Select() of a map not in the rotation = true")
+	}
+}
@@ -28,31 +28,39 @@ type Config struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Address         string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"` // ip addr
-	Port            uint32 `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
-	Database        string `protobuf:"bytes,3,opt,name=database,proto3" json:"database,omitempty"`                       // sqlite file
-	PrivateKey      string `protobuf:"bytes,4,opt,name=private_key,json=privateKey,proto3" json:"private_key,omitempty"` // path
-	ApiEnabled      bool   `protobuf:"varint,5,opt,name=api_enabled,json=apiEnabled,proto3" json:"api_enabled,omitempty"`
-	ApiAddress      string `protobuf:"bytes,6,opt,name=api_address,json=apiAddress,proto3" json:"api_address,omitempty"` // ip addr
-	ApiPort         uint32 `protobuf:"varint,7,opt,name=api_port,json=apiPort,proto3" json:"api_port,omitempty"`
-	ClientDirectory string `protobuf:"bytes,9,opt,name=client_directory,json=clientDirectory,proto3" json:"client_directory,omitempty"`
-	UserFile        string `protobuf:"bytes,10,opt,name=user_file,json=userFile,proto3" json:"user_file,omitempty"`
-	AccessFile      string `protobuf:"bytes,11,opt,name=access_file,json=accessFile,proto3" json:"access_file,omitempty"`
-	AuthFile        string `protobuf:"bytes,12,opt,name=auth_file,json=authFile,proto3" json:"auth_file,omitempty"`
-	RuleFile        string `protobuf:"bytes,20,opt,name=rule_file,json=ruleFile,proto3" json:"rule_file,omitempty"`
-	VpnFile         string `protobuf:"bytes,21,opt,name=vpn_file,json=vpnFile,proto3" json:"vpn_file,omitempty"`                          // VPN detection/action settings
-	MaintenanceTime uint32 `protobuf:"varint,13,opt,name=maintenance_time,json=maintenanceTime,proto3" json:"maintenance_time,omitempty"` // seconds
-	DebugMode       bool   `protobuf:"varint,14,opt,name=debug_mode,json=debugMode,proto3" json:"debug_mode,omitempty"`
-	WebRoot         string `protobuf:"bytes,15,opt,name=web_root,json=webRoot,proto3" json:"web_root,omitempty"` // where are website file?
-	LogFile         string `protobuf:"bytes,16,opt,name=log_file,json=logFile,proto3" json:"log_file,omitempty"`
-	Foreground      bool   `protobuf:"varint,17,opt,name=foreground,proto3" json:"foreground,omitempty"`
-	RpcAddress      string `protobuf:"bytes,18,opt,name=rpc_address,json=rpcAddress,proto3" json:"rpc_address,omitempty"`
-	RpcPort         uint32 `protobuf:"varint,19,opt,name=rpc_port,json=rpcPort,proto3" json:"rpc_port,omitempty"`
-	SshAddress      string `protobuf:"bytes,22,opt,name=ssh_address,json=sshAddress,proto3" json:"ssh_address,omitempty"`
-	SshPort         uint32 `protobuf:"varint,23,opt,name=ssh_port,json=sshPort,proto3" json:"ssh_port,omitempty"`
-	SshHostkey      string `protobuf:"bytes,24,opt,name=ssh_hostkey,json=sshHostkey,proto3" json:"ssh_hostkey,omitempty"`
-	VerboseLevel    int32  `protobuf:"varint,25,opt,name=verbose_level,json=verboseLevel,proto3" json:"verbose_level,omitempty"`
-	ApiSecret       string `protobuf:"bytes,26,opt,name=api_secret,json=apiSecret,proto3" json:"api_secret,omitempty"` // for signing JWTs, leave blank to autogenerate
+	Address         string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"` // ip addr
+	Port            uint32   `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	Database        string   `protobuf:"bytes,3,opt,name=database,proto3" json:"database,omitempty"`                       // sqlite file
+	PrivateKey      string   `protobuf:"bytes,4,opt,name=private_key,json=privateKey,proto3" json:"private_key,omitempty"` // path
+	ApiEnabled      bool     `protobuf:"varint,5,opt,name=api_enabled,json=apiEnabled,proto3" json:"api_enabled,omitempty"`
+	ApiAddress      string   `protobuf:"bytes,6,opt,name=api_address,json=apiAddress,proto3" json:"api_address,omitempty"` // ip addr
+	ApiPort         uint32   `protobuf:"varint,7,opt,name=api_port,json=apiPort,proto3" json:"api_port,omitempty"`
+	ClientDirectory string   `protobuf:"bytes,9,opt,name=client_directory,json=clientDirectory,proto3" json:"client_directory,omitempty"`
+	UserFile        string   `protobuf:"bytes,10,opt,name=user_file,json=userFile,proto3" json:"user_file,omitempty"`
+	AccessFile      string   `protobuf:"bytes,11,opt,name=access_file,json=accessFile,proto3" json:"access_file,omitempty"`
+	AuthFile        string   `protobuf:"bytes,12,opt,name=auth_file,json=authFile,proto3" json:"auth_file,omitempty"`
+	RuleFile        string   `protobuf:"bytes,20,opt,name=rule_file,json=ruleFile,proto3" json:"rule_file,omitempty"`
+	VpnFile         string   `protobuf:"bytes,21,opt,name=vpn_file,json=vpnFile,proto3" json:"vpn_file,omitempty"`                          // VPN detection/action settings
+	MaintenanceTime uint32   `protobuf:"varint,13,opt,name=maintenance_time,json=maintenanceTime,proto3" json:"maintenance_time,omitempty"` // seconds
+	DebugMode       bool     `protobuf:"varint,14,opt,name=debug_mode,json=debugMode,proto3" json:"debug_mode,omitempty"`
+	WebRoot         string   `protobuf:"bytes,15,opt,name=web_root,json=webRoot,proto3" json:"web_root,omitempty"` // where are website file?
+	LogFile         string   `protobuf:"bytes,16,opt,name=log_file,json=logFile,proto3" json:"log_file,omitempty"`
+	Foreground      bool     `protobuf:"varint,17,opt,name=foreground,proto3" json:"foreground,omitempty"`
+	RpcAddress      string   `protobuf:"bytes,18,opt,name=rpc_address,json=rpcAddress,proto3" json:"rpc_address,omitempty"`
+	RpcPort         uint32   `protobuf:"varint,19,opt,name=rpc_port,json=rpcPort,proto3" json:"rpc_port,omitempty"`
+	SshAddress      string   `protobuf:"bytes,22,opt,name=ssh_address,json=sshAddress,proto3" json:"ssh_address,omitempty"`
+	SshPort         uint32   `protobuf:"varint,23,opt,name=ssh_port,json=sshPort,proto3" json:"ssh_port,omitempty"`
+	SshHostkey      string   `protobuf:"bytes,24,opt,name=ssh_hostkey,json=sshHostkey,proto3" json:"ssh_hostkey,omitempty"`
+	VerboseLevel    int32    `protobuf:"varint,25,opt,name=verbose_level,json=verboseLevel,proto3" json:"verbose_level,omitempty"`
+	ApiSecret       string   `protobuf:"bytes,26,opt,name=api_secret,json=apiSecret,proto3" json:"api_secret,omitempty"`             // for signing JWTs, leave blank to autogenerate
+	GeoipDatabase   string   `protobuf:"bytes,27,opt,name=geoip_database,json=geoipDatabase,proto3" json:"geoip_database,omitempty"` // MaxMind-format (mmdb) country database
+	AsnDatabase     string   `protobuf:"bytes,28,opt,name=asn_database,json=asnDatabase,proto3" json:"asn_database,omitempty"`       // MaxMind-format (mmdb) ASN database
+	EvasionFile     string   `protobuf:"bytes,29,opt,name=evasion_file,json=evasionFile,proto3" json:"evasion_file,omitempty"`       // ban evasion detection settings
+	SmtpServer      string   `protobuf:"bytes,30,opt,name=smtp_server,json=smtpServer,proto3" json:"smtp_server,omitempty"`          // addr:port for sending notification emails
+	SmtpFrom        string   `protobuf:"bytes,31,opt,name=smtp_from,json=smtpFrom,proto3" json:"smtp_from,omitempty"`                // sender address for notification emails
+	ChatFile        string   `protobuf:"bytes,32,opt,name=chat_file,json=chatFile,proto3" json:"chat_file,omitempty"`                // cross-server chat bridge settings
+	DiscordFile     string   `protobuf:"bytes,33,opt,name=discord_file,json=discordFile,proto3" json:"discord_file,omitempty"`       // discord integration settings
+	Admins          []string `protobuf:"bytes,34,rep,name=admins,proto3" json:"admins,omitempty"`                                    // emails of users who run this backend
 }
 
 func (x *Config) Reset() {
@@ -262,11 +270,67 @@ func (x *Config) GetApiSecret() string {
 	return ""
 }
 
+func (x *Config) GetGeoipDatabase() string {
+	if x != nil {
+		return x.GeoipDatabase
+	}
+	return ""
+}
+
+func (x *Config) GetAsnDatabase() string {
+	if x != nil {
+		return x.AsnDatabase
+	}
+	return ""
+}
+
+func (x *Config) GetEvasionFile() string {
+	if x != nil {
+		return x.EvasionFile
+	}
+	return ""
+}
+
+func (x *Config) GetSmtpServer() string {
+	if x != nil {
+		return x.SmtpServer
+	}
+	return ""
+}
+
+func (x *Config) GetSmtpFrom() string {
+	if x != nil {
+		return x.SmtpFrom
+	}
+	return ""
+}
+
+func (x *Config) GetChatFile() string {
+	if x != nil {
+		return x.ChatFile
+	}
+	return ""
+}
+
+func (x *Config) GetDiscordFile() string {
+	if x != nil {
+		return x.DiscordFile
+	}
+	return ""
+}
+
+func (x *Config) GetAdmins() []string {
+	if x != nil {
+		return x.Admins
+	}
+	return nil
+}
+
 var File_config_proto protoreflect.FileDescriptor
 
 var file_config_proto_rawDesc = []byte{
 	0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x8b, 0x06, 0x0a, 0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x8e, 0x08, 0x0a, 0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
 	0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
 	0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f,
 	0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x1a,
@@ -315,10 +379,26 @@ var file_config_proto_rawDesc = []byte{
 	0x18, 0x19, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x76, 0x65, 0x72, 0x62, 0x6f, 0x73, 0x65, 0x4c,
 	0x65, 0x76, 0x65, 0x6c, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x70, 0x69, 0x5f, 0x73, 0x65, 0x63, 0x72,
 	0x65, 0x74, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x61, 0x70, 0x69, 0x53, 0x65, 0x63,
-	0x72, 0x65, 0x74, 0x42, 0x29, 0x5a, 0x27, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
-	0x6d, 0x2f, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x66, 0x6c, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x2f,
-	0x71, 0x32, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x72, 0x65, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x67, 0x65, 0x6f, 0x69, 0x70, 0x5f, 0x64, 0x61, 0x74,
+	0x61, 0x62, 0x61, 0x73, 0x65, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x67, 0x65, 0x6f,
+	0x69, 0x70, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x73,
+	0x6e, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x61, 0x73, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x12, 0x21, 0x0a,
+	0x0c, 0x65, 0x76, 0x61, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x1d, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x65, 0x76, 0x61, 0x73, 0x69, 0x6f, 0x6e, 0x46, 0x69, 0x6c, 0x65,
+	0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x6d, 0x74, 0x70, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x18,
+	0x1e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x6d, 0x74, 0x70, 0x53, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x6d, 0x74, 0x70, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x1f,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x6d, 0x74, 0x70, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x1b,
+	0x0a, 0x09, 0x63, 0x68, 0x61, 0x74, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x20, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x63, 0x68, 0x61, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x64,
+	0x69, 0x73, 0x63, 0x6f, 0x72, 0x64, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x21, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x72, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x73, 0x18, 0x22, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06,
+	0x61, 0x64, 0x6d, 0x69, 0x6e, 0x73, 0x42, 0x29, 0x5a, 0x27, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x66, 0x6c, 0x69, 0x6e, 0x67,
+	0x65, 0x72, 0x2f, 0x71, 0x32, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
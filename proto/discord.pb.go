@@ -0,0 +1,262 @@
+// compile with:
+// protoc --go_out=. --go_opt=paths=source_relative discord.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        v4.22.2
+// source: discord.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Settings for posting frontend events to Discord and relaying a Discord
+// channel back into the game. Each frontend picks its own webhook, channel and
+// events, these apply to all of them.
+type DiscordConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Disabled     bool   `protobuf:"varint,1,opt,name=disabled,proto3" json:"disabled,omitempty"`                             // turn the integration off everywhere
+	ApiUrl       string `protobuf:"bytes,2,opt,name=api_url,json=apiUrl,proto3" json:"api_url,omitempty"`                    // default "https://discord.com/api/v10"
+	BotToken     string `protobuf:"bytes,3,opt,name=bot_token,json=botToken,proto3" json:"bot_token,omitempty"`              // needed for relaying and posting without a webhook
+	PollInterval int32  `protobuf:"varint,4,opt,name=poll_interval,json=pollInterval,proto3" json:"poll_interval,omitempty"` // seconds between channel checks (default 5)
+	RateMessages int32  `protobuf:"varint,5,opt,name=rate_messages,json=rateMessages,proto3" json:"rate_messages,omitempty"` // most posts per frontend... (default 5)
+	RateWindow   int32  `protobuf:"varint,6,opt,name=rate_window,json=rateWindow,proto3" json:"rate_window,omitempty"`       // ...in this many seconds (default 5)
+	// text/template formats, each gets a DiscordEvent
+	ChatFormat    string `protobuf:"bytes,10,opt,name=chat_format,json=chatFormat,proto3" json:"chat_format,omitempty"`          // default "**{{.Name}}**: {{.Text}}"
+	ConnectFormat string `protobuf:"bytes,11,opt,name=connect_format,json=connectFormat,proto3" json:"connect_format,omitempty"` // default "{{.Name}} connected"
+	MapFormat     string `protobuf:"bytes,12,opt,name=map_format,json=mapFormat,proto3" json:"map_format,omitempty"`             // default "Map changed to {{.Text}}"
+	BanFormat     string `protobuf:"bytes,13,opt,name=ban_format,json=banFormat,proto3" json:"ban_format,omitempty"`             // default "{{.Name}} was banned: {{.Text}}"
+	ReportFormat  string `protobuf:"bytes,14,opt,name=report_format,json=reportFormat,proto3" json:"report_format,omitempty"`    // default "{{.Name}} was reported: {{.Text}}"
+	RelayFormat   string `protobuf:"bytes,15,opt,name=relay_format,json=relayFormat,proto3" json:"relay_format,omitempty"`       // in-game, default "[discord] {{.Name}}: {{.Text}}"
+}
+
+func (x *DiscordConfig) Reset() {
+	*x = DiscordConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_discord_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DiscordConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscordConfig) ProtoMessage() {}
+
+func (x *DiscordConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_discord_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscordConfig.ProtoReflect.Descriptor instead.
+func (*DiscordConfig) Descriptor() ([]byte, []int) {
+	return file_discord_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DiscordConfig) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+func (x *DiscordConfig) GetApiUrl() string {
+	if x != nil {
+		return x.ApiUrl
+	}
+	return ""
+}
+
+func (x *DiscordConfig) GetBotToken() string {
+	if x != nil {
+		return x.BotToken
+	}
+	return ""
+}
+
+func (x *DiscordConfig) GetPollInterval() int32 {
+	if x != nil {
+		return x.PollInterval
+	}
+	return 0
+}
+
+func (x *DiscordConfig) GetRateMessages() int32 {
+	if x != nil {
+		return x.RateMessages
+	}
+	return 0
+}
+
+func (x *DiscordConfig) GetRateWindow() int32 {
+	if x != nil {
+		return x.RateWindow
+	}
+	return 0
+}
+
+func (x *DiscordConfig) GetChatFormat() string {
+	if x != nil {
+		return x.ChatFormat
+	}
+	return ""
+}
+
+func (x *DiscordConfig) GetConnectFormat() string {
+	if x != nil {
+		return x.ConnectFormat
+	}
+	return ""
+}
+
+func (x *DiscordConfig) GetMapFormat() string {
+	if x != nil {
+		return x.MapFormat
+	}
+	return ""
+}
+
+func (x *DiscordConfig) GetBanFormat() string {
+	if x != nil {
+		return x.BanFormat
+	}
+	return ""
+}
+
+func (x *DiscordConfig) GetReportFormat() string {
+	if x != nil {
+		return x.ReportFormat
+	}
+	return ""
+}
+
+func (x *DiscordConfig) GetRelayFormat() string {
+	if x != nil {
+		return x.RelayFormat
+	}
+	return ""
+}
+
+var File_discord_proto protoreflect.FileDescriptor
+
+var file_discord_proto_rawDesc = []byte{
+	0x0a, 0x0d, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x72, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x9a, 0x03, 0x0a, 0x0d, 0x44, 0x69, 0x73, 0x63, 0x6f,
+	0x72, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x61,
+	0x62, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x64, 0x69, 0x73, 0x61,
+	0x62, 0x6c, 0x65, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x61, 0x70, 0x69, 0x5f, 0x75, 0x72, 0x6c, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x70, 0x69, 0x55, 0x72, 0x6c, 0x12, 0x1b, 0x0a,
+	0x09, 0x62, 0x6f, 0x74, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x62, 0x6f, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x70, 0x6f,
+	0x6c, 0x6c, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0c, 0x70, 0x6f, 0x6c, 0x6c, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12,
+	0x23, 0x0a, 0x0d, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x72, 0x61, 0x74, 0x65, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x77, 0x69, 0x6e,
+	0x64, 0x6f, 0x77, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x72, 0x61, 0x74, 0x65, 0x57,
+	0x69, 0x6e, 0x64, 0x6f, 0x77, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x68, 0x61, 0x74, 0x5f, 0x66, 0x6f,
+	0x72, 0x6d, 0x61, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x68, 0x61, 0x74,
+	0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
+	0x74, 0x5f, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d,
+	0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x1d, 0x0a,
+	0x0a, 0x6d, 0x61, 0x70, 0x5f, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x6d, 0x61, 0x70, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x1d, 0x0a, 0x0a,
+	0x62, 0x61, 0x6e, 0x5f, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x62, 0x61, 0x6e, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x72,
+	0x65, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x0e, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74,
+	0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x5f, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74,
+	0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x46, 0x6f, 0x72,
+	0x6d, 0x61, 0x74, 0x42, 0x29, 0x5a, 0x27, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x66, 0x6c, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x2f,
+	0x71, 0x32, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_discord_proto_rawDescOnce sync.Once
+	file_discord_proto_rawDescData = file_discord_proto_rawDesc
+)
+
+func file_discord_proto_rawDescGZIP() []byte {
+	file_discord_proto_rawDescOnce.Do(func() {
+		file_discord_proto_rawDescData = protoimpl.X.CompressGZIP(file_discord_proto_rawDescData)
+	})
+	return file_discord_proto_rawDescData
+}
+
+var file_discord_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_discord_proto_goTypes = []interface{}{
+	(*DiscordConfig)(nil), // 0: proto.DiscordConfig
+}
+var file_discord_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_discord_proto_init() }
+func file_discord_proto_init() {
+	if File_discord_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_discord_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DiscordConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_discord_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_discord_proto_goTypes,
+		DependencyIndexes: file_discord_proto_depIdxs,
+		MessageInfos:      file_discord_proto_msgTypes,
+	}.Build()
+	File_discord_proto = out.File
+	file_discord_proto_rawDesc = nil
+	file_discord_proto_goTypes = nil
+	file_discord_proto_depIdxs = nil
+}
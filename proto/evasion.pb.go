@@ -0,0 +1,275 @@
+// compile with:
+// protoc --go_out=. --go_opt=paths=source_relative evasion.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        v4.22.2
+// source: evasion.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// What to do with a player suspected of evading a ban or mute
+type EvasionAction int32
+
+const (
+	EvasionAction_EVASION_ACTION_NONE   EvasionAction = 0 // just alert
+	EvasionAction_EVASION_ACTION_KICK   EvasionAction = 1 // kick them, same as the original ban
+	EvasionAction_EVASION_ACTION_MUTE   EvasionAction = 2 // mute them, same as the original mute
+	EvasionAction_EVASION_ACTION_STIFLE EvasionAction = 3 // stifle them for stifle_length seconds
+)
+
+// Enum value maps for EvasionAction.
+var (
+	EvasionAction_name = map[int32]string{
+		0: "EVASION_ACTION_NONE",
+		1: "EVASION_ACTION_KICK",
+		2: "EVASION_ACTION_MUTE",
+		3: "EVASION_ACTION_STIFLE",
+	}
+	EvasionAction_value = map[string]int32{
+		"EVASION_ACTION_NONE":   0,
+		"EVASION_ACTION_KICK":   1,
+		"EVASION_ACTION_MUTE":   2,
+		"EVASION_ACTION_STIFLE": 3,
+	}
+)
+
+func (x EvasionAction) Enum() *EvasionAction {
+	p := new(EvasionAction)
+	*p = x
+	return p
+}
+
+func (x EvasionAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EvasionAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_evasion_proto_enumTypes[0].Descriptor()
+}
+
+func (EvasionAction) Type() protoreflect.EnumType {
+	return &file_evasion_proto_enumTypes[0]
+}
+
+func (x EvasionAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EvasionAction.Descriptor instead.
+func (EvasionAction) EnumDescriptor() ([]byte, []int) {
+	return file_evasion_proto_rawDescGZIP(), []int{0}
+}
+
+// Settings for detecting banned/muted players coming back as someone else.
+// Each new connection is compared to recently sanctioned players and given a
+// score (0.0-1.0) based on how much they have in common.
+type EvasionConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Enabled      bool          `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`                               // should we check at all?
+	Threshold    float32       `protobuf:"fixed32,2,opt,name=threshold,proto3" json:"threshold,omitempty"`                          // alert at or above this score (default 0.7)
+	Lookback     int64         `protobuf:"varint,3,opt,name=lookback,proto3" json:"lookback,omitempty"`                             // consider sanctions this recent, in seconds (default 30 days)
+	EmailOwner   bool          `protobuf:"varint,4,opt,name=email_owner,json=emailOwner,proto3" json:"email_owner,omitempty"`       // email the frontend owner (needs smtp_server in the main config)
+	Action       EvasionAction `protobuf:"varint,5,opt,name=action,proto3,enum=proto.EvasionAction" json:"action,omitempty"`        // automatically do this to suspected evaders
+	Message      []string      `protobuf:"bytes,6,rep,name=message,proto3" json:"message,omitempty"`                                // shown to the player with the action
+	StifleLength int32         `protobuf:"varint,7,opt,name=stifle_length,json=stifleLength,proto3" json:"stifle_length,omitempty"` // seconds, for EVASION_ACTION_STIFLE
+}
+
+func (x *EvasionConfig) Reset() {
+	*x = EvasionConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_evasion_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EvasionConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvasionConfig) ProtoMessage() {}
+
+func (x *EvasionConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_evasion_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvasionConfig.ProtoReflect.Descriptor instead.
+func (*EvasionConfig) Descriptor() ([]byte, []int) {
+	return file_evasion_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EvasionConfig) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *EvasionConfig) GetThreshold() float32 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *EvasionConfig) GetLookback() int64 {
+	if x != nil {
+		return x.Lookback
+	}
+	return 0
+}
+
+func (x *EvasionConfig) GetEmailOwner() bool {
+	if x != nil {
+		return x.EmailOwner
+	}
+	return false
+}
+
+func (x *EvasionConfig) GetAction() EvasionAction {
+	if x != nil {
+		return x.Action
+	}
+	return EvasionAction_EVASION_ACTION_NONE
+}
+
+func (x *EvasionConfig) GetMessage() []string {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *EvasionConfig) GetStifleLength() int32 {
+	if x != nil {
+		return x.StifleLength
+	}
+	return 0
+}
+
+var File_evasion_proto protoreflect.FileDescriptor
+
+var file_evasion_proto_rawDesc = []byte{
+	0x0a, 0x0d, 0x65, 0x76, 0x61, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xf1, 0x01, 0x0a, 0x0d, 0x45, 0x76, 0x61, 0x73, 0x69,
+	0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62,
+	0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c,
+	0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x09, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64,
+	0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x6f, 0x6b, 0x62, 0x61, 0x63, 0x6b, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x08, 0x6c, 0x6f, 0x6f, 0x6b, 0x62, 0x61, 0x63, 0x6b, 0x12, 0x1f, 0x0a, 0x0b,
+	0x65, 0x6d, 0x61, 0x69, 0x6c, 0x5f, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0a, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x2c, 0x0a,
+	0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x76, 0x61, 0x73, 0x69, 0x6f, 0x6e, 0x41, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x74, 0x69, 0x66, 0x6c, 0x65, 0x5f,
+	0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x73, 0x74,
+	0x69, 0x66, 0x6c, 0x65, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x2a, 0x75, 0x0a, 0x0d, 0x45, 0x76,
+	0x61, 0x73, 0x69, 0x6f, 0x6e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x17, 0x0a, 0x13, 0x45,
+	0x56, 0x41, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4e, 0x4f,
+	0x4e, 0x45, 0x10, 0x00, 0x12, 0x17, 0x0a, 0x13, 0x45, 0x56, 0x41, 0x53, 0x49, 0x4f, 0x4e, 0x5f,
+	0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4b, 0x49, 0x43, 0x4b, 0x10, 0x01, 0x12, 0x17, 0x0a,
+	0x13, 0x45, 0x56, 0x41, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f,
+	0x4d, 0x55, 0x54, 0x45, 0x10, 0x02, 0x12, 0x19, 0x0a, 0x15, 0x45, 0x56, 0x41, 0x53, 0x49, 0x4f,
+	0x4e, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x49, 0x46, 0x4c, 0x45, 0x10,
+	0x03, 0x42, 0x29, 0x5a, 0x27, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x66, 0x6c, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x2f, 0x71, 0x32,
+	0x61, 0x64, 0x6d, 0x69, 0x6e, 0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_evasion_proto_rawDescOnce sync.Once
+	file_evasion_proto_rawDescData = file_evasion_proto_rawDesc
+)
+
+func file_evasion_proto_rawDescGZIP() []byte {
+	file_evasion_proto_rawDescOnce.Do(func() {
+		file_evasion_proto_rawDescData = protoimpl.X.CompressGZIP(file_evasion_proto_rawDescData)
+	})
+	return file_evasion_proto_rawDescData
+}
+
+var file_evasion_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_evasion_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_evasion_proto_goTypes = []interface{}{
+	(EvasionAction)(0),    // 0: proto.EvasionAction
+	(*EvasionConfig)(nil), // 1: proto.EvasionConfig
+}
+var file_evasion_proto_depIdxs = []int32{
+	0, // 0: proto.EvasionConfig.action:type_name -> proto.EvasionAction
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_evasion_proto_init() }
+func file_evasion_proto_init() {
+	if File_evasion_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_evasion_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EvasionConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_evasion_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_evasion_proto_goTypes,
+		DependencyIndexes: file_evasion_proto_depIdxs,
+		EnumInfos:         file_evasion_proto_enumTypes,
+		MessageInfos:      file_evasion_proto_msgTypes,
+	}.Build()
+	File_evasion_proto = out.File
+	file_evasion_proto_rawDesc = nil
+	file_evasion_proto_goTypes = nil
+	file_evasion_proto_depIdxs = nil
+}
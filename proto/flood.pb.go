@@ -41,6 +41,14 @@ type FloodInfo struct {
 	FirstUserinfoTime int64 `protobuf:"varint,5,opt,name=first_userinfo_time,json=firstUserinfoTime,proto3" json:"first_userinfo_time,omitempty"`
 	// total UI changes
 	UserinfoTotal int32 `protobuf:"varint,6,opt,name=userinfo_total,json=userinfoTotal,proto3" json:"userinfo_total,omitempty"`
+	// Times (millis) of the prints and name/skin changes still inside the
+	// flood window
+	PrintTimes    []int64 `protobuf:"varint,7,rep,packed,name=print_times,json=printTimes,proto3" json:"print_times,omitempty"`
+	UserinfoTimes []int64 `protobuf:"varint,8,rep,packed,name=userinfo_times,json=userinfoTimes,proto3" json:"userinfo_times,omitempty"`
+	// How many times they've flooded recently, picks the next response
+	Level int32 `protobuf:"varint,9,opt,name=level,proto3" json:"level,omitempty"`
+	// The last time (millis) they flooded
+	LastFloodTime int64 `protobuf:"varint,10,opt,name=last_flood_time,json=lastFloodTime,proto3" json:"last_flood_time,omitempty"`
 }
 
 func (x *FloodInfo) Reset() {
@@ -117,11 +125,120 @@ func (x *FloodInfo) GetUserinfoTotal() int32 {
 	return 0
 }
 
+func (x *FloodInfo) GetPrintTimes() []int64 {
+	if x != nil {
+		return x.PrintTimes
+	}
+	return nil
+}
+
+func (x *FloodInfo) GetUserinfoTimes() []int64 {
+	if x != nil {
+		return x.UserinfoTimes
+	}
+	return nil
+}
+
+func (x *FloodInfo) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *FloodInfo) GetLastFloodTime() int64 {
+	if x != nil {
+		return x.LastFloodTime
+	}
+	return 0
+}
+
+// Flood limits for a frontend. Going over either limit is a flood, and each
+// flood gets the next action in the list (the last one repeats).
+type FloodConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChatMessages    int32    `protobuf:"varint,1,opt,name=chat_messages,json=chatMessages,proto3" json:"chat_messages,omitempty"`          // most chat lines... (default 5)
+	ChatWindow      int32    `protobuf:"varint,2,opt,name=chat_window,json=chatWindow,proto3" json:"chat_window,omitempty"`                // ...in this many seconds (default 3)
+	UserinfoChanges int32    `protobuf:"varint,3,opt,name=userinfo_changes,json=userinfoChanges,proto3" json:"userinfo_changes,omitempty"` // most name/skin changes... (default 4)
+	UserinfoWindow  int32    `protobuf:"varint,4,opt,name=userinfo_window,json=userinfoWindow,proto3" json:"userinfo_window,omitempty"`    // ...in this many seconds (default 10)
+	Actions         []string `protobuf:"bytes,5,rep,name=actions,proto3" json:"actions,omitempty"`                                         // "warn", "stifle", "mute" or "kick" (default all, in that order)
+}
+
+func (x *FloodConfig) Reset() {
+	*x = FloodConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_flood_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FloodConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FloodConfig) ProtoMessage() {}
+
+func (x *FloodConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_flood_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FloodConfig.ProtoReflect.Descriptor instead.
+func (*FloodConfig) Descriptor() ([]byte, []int) {
+	return file_flood_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FloodConfig) GetChatMessages() int32 {
+	if x != nil {
+		return x.ChatMessages
+	}
+	return 0
+}
+
+func (x *FloodConfig) GetChatWindow() int32 {
+	if x != nil {
+		return x.ChatWindow
+	}
+	return 0
+}
+
+func (x *FloodConfig) GetUserinfoChanges() int32 {
+	if x != nil {
+		return x.UserinfoChanges
+	}
+	return 0
+}
+
+func (x *FloodConfig) GetUserinfoWindow() int32 {
+	if x != nil {
+		return x.UserinfoWindow
+	}
+	return 0
+}
+
+func (x *FloodConfig) GetActions() []string {
+	if x != nil {
+		return x.Actions
+	}
+	return nil
+}
+
 var File_flood_proto protoreflect.FileDescriptor
 
 var file_flood_proto_rawDesc = []byte{
 	0x0a, 0x0b, 0x66, 0x6c, 0x6f, 0x6f, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x22, 0x83, 0x02, 0x0a, 0x09, 0x46, 0x6c, 0x6f, 0x6f, 0x64, 0x49, 0x6e,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0x89, 0x03, 0x0a, 0x09, 0x46, 0x6c, 0x6f, 0x6f, 0x64, 0x49, 0x6e,
 	0x66, 0x6f, 0x12, 0x26, 0x0a, 0x0f, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x70, 0x72, 0x69, 0x6e, 0x74,
 	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x6c, 0x61, 0x73,
 	0x74, 0x50, 0x72, 0x69, 0x6e, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x28, 0x0a, 0x10, 0x66, 0x69,
@@ -137,10 +254,31 @@ var file_flood_proto_rawDesc = []byte{
 	0x52, 0x11, 0x66, 0x69, 0x72, 0x73, 0x74, 0x55, 0x73, 0x65, 0x72, 0x69, 0x6e, 0x66, 0x6f, 0x54,
 	0x69, 0x6d, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x75, 0x73, 0x65, 0x72, 0x69, 0x6e, 0x66, 0x6f, 0x5f,
 	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x75, 0x73, 0x65,
-	0x72, 0x69, 0x6e, 0x66, 0x6f, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x29, 0x5a, 0x27, 0x67, 0x69,
-	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x66,
-	0x6c, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x2f, 0x71, 0x32, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x64, 0x2f,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x72, 0x69, 0x6e, 0x66, 0x6f, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x72,
+	0x69, 0x6e, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x03, 0x52,
+	0x0a, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x75,
+	0x73, 0x65, 0x72, 0x69, 0x6e, 0x66, 0x6f, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x18, 0x08, 0x20,
+	0x03, 0x28, 0x03, 0x52, 0x0d, 0x75, 0x73, 0x65, 0x72, 0x69, 0x6e, 0x66, 0x6f, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x26, 0x0a, 0x0f, 0x6c, 0x61, 0x73, 0x74,
+	0x5f, 0x66, 0x6c, 0x6f, 0x6f, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0d, 0x6c, 0x61, 0x73, 0x74, 0x46, 0x6c, 0x6f, 0x6f, 0x64, 0x54, 0x69, 0x6d, 0x65,
+	0x22, 0xc1, 0x01, 0x0a, 0x0b, 0x46, 0x6c, 0x6f, 0x6f, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x12, 0x23, 0x0a, 0x0d, 0x63, 0x68, 0x61, 0x74, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x63, 0x68, 0x61, 0x74, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x68, 0x61, 0x74, 0x5f, 0x77, 0x69,
+	0x6e, 0x64, 0x6f, 0x77, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x63, 0x68, 0x61, 0x74,
+	0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x12, 0x29, 0x0a, 0x10, 0x75, 0x73, 0x65, 0x72, 0x69, 0x6e,
+	0x66, 0x6f, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0f, 0x75, 0x73, 0x65, 0x72, 0x69, 0x6e, 0x66, 0x6f, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x73, 0x12, 0x27, 0x0a, 0x0f, 0x75, 0x73, 0x65, 0x72, 0x69, 0x6e, 0x66, 0x6f, 0x5f, 0x77, 0x69,
+	0x6e, 0x64, 0x6f, 0x77, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x75, 0x73, 0x65, 0x72,
+	0x69, 0x6e, 0x66, 0x6f, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x42, 0x29, 0x5a, 0x27, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x66, 0x6c, 0x69, 0x6e, 0x67, 0x65, 0x72,
+	0x2f, 0x71, 0x32, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -155,9 +293,10 @@ func file_flood_proto_rawDescGZIP() []byte {
 	return file_flood_proto_rawDescData
 }
 
-var file_flood_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_flood_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_flood_proto_goTypes = []interface{}{
-	(*FloodInfo)(nil), // 0: proto.FloodInfo
+	(*FloodInfo)(nil),   // 0: proto.FloodInfo
+	(*FloodConfig)(nil), // 1: proto.FloodConfig
 }
 var file_flood_proto_depIdxs = []int32{
 	0, // [0:0] is the sub-list for method output_type
@@ -185,6 +324,18 @@ func file_flood_proto_init() {
 				return nil
 			}
 		}
+		file_flood_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FloodConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -192,7 +343,7 @@ func file_flood_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_flood_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -22,7 +22,15 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	Q2Admin_FetchStatus_FullMethodName = "/proto.Q2Admin/FetchStatus"
+	Q2Admin_FetchStatus_FullMethodName      = "/proto.Q2Admin/FetchStatus"
+	Q2Admin_FetchLeaderboard_FullMethodName = "/proto.Q2Admin/FetchLeaderboard"
+	Q2Admin_ExportPlayerData_FullMethodName = "/proto.Q2Admin/ExportPlayerData"
+	Q2Admin_ErasePlayerData_FullMethodName  = "/proto.Q2Admin/ErasePlayerData"
+	Q2Admin_FetchMapStats_FullMethodName    = "/proto.Q2Admin/FetchMapStats"
+	Q2Admin_ListJobs_FullMethodName         = "/proto.Q2Admin/ListJobs"
+	Q2Admin_SaveJob_FullMethodName          = "/proto.Q2Admin/SaveJob"
+	Q2Admin_DeleteJob_FullMethodName        = "/proto.Q2Admin/DeleteJob"
+	Q2Admin_RunJob_FullMethodName           = "/proto.Q2Admin/RunJob"
 )
 
 // Q2AdminClient is the client API for Q2Admin service.
@@ -30,6 +38,14 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type Q2AdminClient interface {
 	FetchStatus(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	FetchLeaderboard(ctx context.Context, in *LeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardResponse, error)
+	ExportPlayerData(ctx context.Context, in *PlayerDataRequest, opts ...grpc.CallOption) (*PlayerDataExport, error)
+	ErasePlayerData(ctx context.Context, in *PlayerDataRequest, opts ...grpc.CallOption) (*PlayerDataErased, error)
+	FetchMapStats(ctx context.Context, in *MapStatsRequest, opts ...grpc.CallOption) (*MapStatsResponse, error)
+	ListJobs(ctx context.Context, in *JobsRequest, opts ...grpc.CallOption) (*JobsResponse, error)
+	SaveJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobEntry, error)
+	DeleteJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobEntry, error)
+	RunJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobEntry, error)
 }
 
 type q2AdminClient struct {
@@ -49,11 +65,91 @@ func (c *q2AdminClient) FetchStatus(ctx context.Context, in *StatusRequest, opts
 	return out, nil
 }
 
+func (c *q2AdminClient) FetchLeaderboard(ctx context.Context, in *LeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardResponse, error) {
+	out := new(LeaderboardResponse)
+	err := c.cc.Invoke(ctx, Q2Admin_FetchLeaderboard_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *q2AdminClient) ExportPlayerData(ctx context.Context, in *PlayerDataRequest, opts ...grpc.CallOption) (*PlayerDataExport, error) {
+	out := new(PlayerDataExport)
+	err := c.cc.Invoke(ctx, Q2Admin_ExportPlayerData_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *q2AdminClient) ErasePlayerData(ctx context.Context, in *PlayerDataRequest, opts ...grpc.CallOption) (*PlayerDataErased, error) {
+	out := new(PlayerDataErased)
+	err := c.cc.Invoke(ctx, Q2Admin_ErasePlayerData_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *q2AdminClient) FetchMapStats(ctx context.Context, in *MapStatsRequest, opts ...grpc.CallOption) (*MapStatsResponse, error) {
+	out := new(MapStatsResponse)
+	err := c.cc.Invoke(ctx, Q2Admin_FetchMapStats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *q2AdminClient) ListJobs(ctx context.Context, in *JobsRequest, opts ...grpc.CallOption) (*JobsResponse, error) {
+	out := new(JobsResponse)
+	err := c.cc.Invoke(ctx, Q2Admin_ListJobs_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *q2AdminClient) SaveJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobEntry, error) {
+	out := new(JobEntry)
+	err := c.cc.Invoke(ctx, Q2Admin_SaveJob_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *q2AdminClient) DeleteJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobEntry, error) {
+	out := new(JobEntry)
+	err := c.cc.Invoke(ctx, Q2Admin_DeleteJob_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *q2AdminClient) RunJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobEntry, error) {
+	out := new(JobEntry)
+	err := c.cc.Invoke(ctx, Q2Admin_RunJob_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Q2AdminServer is the server API for Q2Admin service.
 // All implementations must embed UnimplementedQ2AdminServer
 // for forward compatibility
 type Q2AdminServer interface {
 	FetchStatus(context.Context, *StatusRequest) (*StatusResponse, error)
+	FetchLeaderboard(context.Context, *LeaderboardRequest) (*LeaderboardResponse, error)
+	ExportPlayerData(context.Context, *PlayerDataRequest) (*PlayerDataExport, error)
+	ErasePlayerData(context.Context, *PlayerDataRequest) (*PlayerDataErased, error)
+	FetchMapStats(context.Context, *MapStatsRequest) (*MapStatsResponse, error)
+	ListJobs(context.Context, *JobsRequest) (*JobsResponse, error)
+	SaveJob(context.Context, *JobRequest) (*JobEntry, error)
+	DeleteJob(context.Context, *JobRequest) (*JobEntry, error)
+	RunJob(context.Context, *JobRequest) (*JobEntry, error)
 	mustEmbedUnimplementedQ2AdminServer()
 }
 
@@ -64,6 +160,30 @@ type UnimplementedQ2AdminServer struct {
 func (UnimplementedQ2AdminServer) FetchStatus(context.Context, *StatusRequest) (*StatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FetchStatus not implemented")
 }
+func (UnimplementedQ2AdminServer) FetchLeaderboard(context.Context, *LeaderboardRequest) (*LeaderboardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchLeaderboard not implemented")
+}
+func (UnimplementedQ2AdminServer) ExportPlayerData(context.Context, *PlayerDataRequest) (*PlayerDataExport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportPlayerData not implemented")
+}
+func (UnimplementedQ2AdminServer) ErasePlayerData(context.Context, *PlayerDataRequest) (*PlayerDataErased, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ErasePlayerData not implemented")
+}
+func (UnimplementedQ2AdminServer) FetchMapStats(context.Context, *MapStatsRequest) (*MapStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchMapStats not implemented")
+}
+func (UnimplementedQ2AdminServer) ListJobs(context.Context, *JobsRequest) (*JobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJobs not implemented")
+}
+func (UnimplementedQ2AdminServer) SaveJob(context.Context, *JobRequest) (*JobEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveJob not implemented")
+}
+func (UnimplementedQ2AdminServer) DeleteJob(context.Context, *JobRequest) (*JobEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteJob not implemented")
+}
+func (UnimplementedQ2AdminServer) RunJob(context.Context, *JobRequest) (*JobEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunJob not implemented")
+}
 func (UnimplementedQ2AdminServer) mustEmbedUnimplementedQ2AdminServer() {}
 
 // UnsafeQ2AdminServer may be embedded to opt out of forward compatibility for this service.
@@ -95,6 +215,150 @@ func _Q2Admin_FetchStatus_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Q2Admin_FetchLeaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaderboardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Q2AdminServer).FetchLeaderboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Q2Admin_FetchLeaderboard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Q2AdminServer).FetchLeaderboard(ctx, req.(*LeaderboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Q2Admin_ExportPlayerData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Q2AdminServer).ExportPlayerData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Q2Admin_ExportPlayerData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Q2AdminServer).ExportPlayerData(ctx, req.(*PlayerDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Q2Admin_ErasePlayerData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayerDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Q2AdminServer).ErasePlayerData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Q2Admin_ErasePlayerData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Q2AdminServer).ErasePlayerData(ctx, req.(*PlayerDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Q2Admin_FetchMapStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MapStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Q2AdminServer).FetchMapStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Q2Admin_FetchMapStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Q2AdminServer).FetchMapStats(ctx, req.(*MapStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Q2Admin_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Q2AdminServer).ListJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Q2Admin_ListJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Q2AdminServer).ListJobs(ctx, req.(*JobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Q2Admin_SaveJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Q2AdminServer).SaveJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Q2Admin_SaveJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Q2AdminServer).SaveJob(ctx, req.(*JobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Q2Admin_DeleteJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Q2AdminServer).DeleteJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Q2Admin_DeleteJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Q2AdminServer).DeleteJob(ctx, req.(*JobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Q2Admin_RunJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Q2AdminServer).RunJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Q2Admin_RunJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Q2AdminServer).RunJob(ctx, req.(*JobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Q2Admin_ServiceDesc is the grpc.ServiceDesc for Q2Admin service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -106,6 +370,38 @@ var Q2Admin_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FetchStatus",
 			Handler:    _Q2Admin_FetchStatus_Handler,
 		},
+		{
+			MethodName: "FetchLeaderboard",
+			Handler:    _Q2Admin_FetchLeaderboard_Handler,
+		},
+		{
+			MethodName: "ExportPlayerData",
+			Handler:    _Q2Admin_ExportPlayerData_Handler,
+		},
+		{
+			MethodName: "ErasePlayerData",
+			Handler:    _Q2Admin_ErasePlayerData_Handler,
+		},
+		{
+			MethodName: "FetchMapStats",
+			Handler:    _Q2Admin_FetchMapStats_Handler,
+		},
+		{
+			MethodName: "ListJobs",
+			Handler:    _Q2Admin_ListJobs_Handler,
+		},
+		{
+			MethodName: "SaveJob",
+			Handler:    _Q2Admin_SaveJob_Handler,
+		},
+		{
+			MethodName: "DeleteJob",
+			Handler:    _Q2Admin_DeleteJob_Handler,
+		},
+		{
+			MethodName: "RunJob",
+			Handler:    _Q2Admin_RunJob_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "q2admin_rpc.proto",
@@ -32,6 +32,9 @@ type VPNConfig struct {
 	LookupUrl    string `protobuf:"bytes,2,opt,name=lookup_url,json=lookupUrl,proto3" json:"lookup_url,omitempty"`             // URL pattern for the api lookup
 	ApiKey       string `protobuf:"bytes,3,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`                      // Auth auth key to use the service
 	KickVpnUsers bool   `protobuf:"varint,4,opt,name=kick_vpn_users,json=kickVpnUsers,proto3" json:"kick_vpn_users,omitempty"` // should we kick VPN users?
+	CidrFile     string `protobuf:"bytes,5,opt,name=cidr_file,json=cidrFile,proto3" json:"cidr_file,omitempty"`                // local list of VPN address ranges, one CIDR per line
+	Timeout      int32  `protobuf:"varint,6,opt,name=timeout,proto3" json:"timeout,omitempty"`                                 // milliseconds to wait for a lookup (default 2000)
+	CacheTtl     int64  `protobuf:"varint,7,opt,name=cache_ttl,json=cacheTtl,proto3" json:"cache_ttl,omitempty"`               // seconds to remember a lookup (default 30 days)
 }
 
 func (x *VPNConfig) Reset() {
@@ -94,11 +97,32 @@ func (x *VPNConfig) GetKickVpnUsers() bool {
 	return false
 }
 
+func (x *VPNConfig) GetCidrFile() string {
+	if x != nil {
+		return x.CidrFile
+	}
+	return ""
+}
+
+func (x *VPNConfig) GetTimeout() int32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+func (x *VPNConfig) GetCacheTtl() int64 {
+	if x != nil {
+		return x.CacheTtl
+	}
+	return 0
+}
+
 var File_vpn_proto protoreflect.FileDescriptor
 
 var file_vpn_proto_rawDesc = []byte{
 	0x0a, 0x09, 0x76, 0x70, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x22, 0x83, 0x01, 0x0a, 0x09, 0x56, 0x50, 0x4e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x74, 0x6f, 0x22, 0xd7, 0x01, 0x0a, 0x09, 0x56, 0x50, 0x4e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
 	0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
 	0x08, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x6f,
 	0x6f, 0x6b, 0x75, 0x70, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
@@ -106,10 +130,15 @@ var file_vpn_proto_rawDesc = []byte{
 	0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x70, 0x69, 0x4b,
 	0x65, 0x79, 0x12, 0x24, 0x0a, 0x0e, 0x6b, 0x69, 0x63, 0x6b, 0x5f, 0x76, 0x70, 0x6e, 0x5f, 0x75,
 	0x73, 0x65, 0x72, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x6b, 0x69, 0x63, 0x6b,
-	0x56, 0x70, 0x6e, 0x55, 0x73, 0x65, 0x72, 0x73, 0x42, 0x29, 0x5a, 0x27, 0x67, 0x69, 0x74, 0x68,
-	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x66, 0x6c, 0x69,
-	0x6e, 0x67, 0x65, 0x72, 0x2f, 0x71, 0x32, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x64, 0x2f, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x56, 0x70, 0x6e, 0x55, 0x73, 0x65, 0x72, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x69, 0x64, 0x72,
+	0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x69, 0x64,
+	0x72, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12,
+	0x1b, 0x0a, 0x09, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x74, 0x74, 0x6c, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x08, 0x63, 0x61, 0x63, 0x68, 0x65, 0x54, 0x74, 0x6c, 0x42, 0x29, 0x5a, 0x27,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x70, 0x61, 0x63, 0x6b, 0x65,
+	0x74, 0x66, 0x6c, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x2f, 0x71, 0x32, 0x61, 0x64, 0x6d, 0x69, 0x6e,
+	0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
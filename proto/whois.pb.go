@@ -0,0 +1,226 @@
+// compile with:
+// protoc --go_out=. --go_opt=paths=source_relative whois.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        v4.22.2
+// source: whois.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// All info needed to respond to an in-game whois command. Only what the
+// frontend chooses to expose is filled in, the rest is left blank.
+type WhoisReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ReplyDate int64    `protobuf:"varint,1,opt,name=reply_date,json=replyDate,proto3" json:"reply_date,omitempty"` // unix timestamp
+	ClientId  int32    `protobuf:"varint,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Name      string   `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`                            // current name
+	Aliases   []string `protobuf:"bytes,4,rep,name=aliases,proto3" json:"aliases,omitempty"`                      // other names used, most recent first
+	FirstSeen string   `protobuf:"bytes,5,opt,name=first_seen,json=firstSeen,proto3" json:"first_seen,omitempty"` // date, "2006-01-02"
+	PlayTime  string   `protobuf:"bytes,6,opt,name=play_time,json=playTime,proto3" json:"play_time,omitempty"`    // total across all servers, "12h30m"
+	Rating    int32    `protobuf:"varint,7,opt,name=rating,proto3" json:"rating,omitempty"`                       // skill rating in the frontend's pool
+	Pool      string   `protobuf:"bytes,8,opt,name=pool,proto3" json:"pool,omitempty"`                            // the rating pool
+	Verified  string   `protobuf:"bytes,9,opt,name=verified,proto3" json:"verified,omitempty"`                    // "yes" or "no"
+}
+
+func (x *WhoisReply) Reset() {
+	*x = WhoisReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_whois_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WhoisReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WhoisReply) ProtoMessage() {}
+
+func (x *WhoisReply) ProtoReflect() protoreflect.Message {
+	mi := &file_whois_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WhoisReply.ProtoReflect.Descriptor instead.
+func (*WhoisReply) Descriptor() ([]byte, []int) {
+	return file_whois_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *WhoisReply) GetReplyDate() int64 {
+	if x != nil {
+		return x.ReplyDate
+	}
+	return 0
+}
+
+func (x *WhoisReply) GetClientId() int32 {
+	if x != nil {
+		return x.ClientId
+	}
+	return 0
+}
+
+func (x *WhoisReply) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WhoisReply) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+func (x *WhoisReply) GetFirstSeen() string {
+	if x != nil {
+		return x.FirstSeen
+	}
+	return ""
+}
+
+func (x *WhoisReply) GetPlayTime() string {
+	if x != nil {
+		return x.PlayTime
+	}
+	return ""
+}
+
+func (x *WhoisReply) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *WhoisReply) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *WhoisReply) GetVerified() string {
+	if x != nil {
+		return x.Verified
+	}
+	return ""
+}
+
+var File_whois_proto protoreflect.FileDescriptor
+
+var file_whois_proto_rawDesc = []byte{
+	0x0a, 0x0b, 0x77, 0x68, 0x6f, 0x69, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0xfa, 0x01, 0x0a, 0x0a, 0x57, 0x68, 0x6f, 0x69, 0x73, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x70, 0x6c, 0x79, 0x5f, 0x64, 0x61, 0x74,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x72, 0x65, 0x70, 0x6c, 0x79, 0x44, 0x61,
+	0x74, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x18, 0x04,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x12, 0x1d, 0x0a,
+	0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x73, 0x65, 0x65, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x66, 0x69, 0x72, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x12, 0x1b, 0x0a, 0x09,
+	0x70, 0x6c, 0x61, 0x79, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x70, 0x6c, 0x61, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x61, 0x74,
+	0x69, 0x6e, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x72, 0x61, 0x74, 0x69, 0x6e,
+	0x67, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x6f, 0x6c, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x70, 0x6f, 0x6f, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65,
+	0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65,
+	0x64, 0x42, 0x29, 0x5a, 0x27, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x66, 0x6c, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x2f, 0x71, 0x32,
+	0x61, 0x64, 0x6d, 0x69, 0x6e, 0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_whois_proto_rawDescOnce sync.Once
+	file_whois_proto_rawDescData = file_whois_proto_rawDesc
+)
+
+func file_whois_proto_rawDescGZIP() []byte {
+	file_whois_proto_rawDescOnce.Do(func() {
+		file_whois_proto_rawDescData = protoimpl.X.CompressGZIP(file_whois_proto_rawDescData)
+	})
+	return file_whois_proto_rawDescData
+}
+
+var file_whois_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_whois_proto_goTypes = []interface{}{
+	(*WhoisReply)(nil), // 0: proto.WhoisReply
+}
+var file_whois_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_whois_proto_init() }
+func file_whois_proto_init() {
+	if File_whois_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_whois_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WhoisReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_whois_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_whois_proto_goTypes,
+		DependencyIndexes: file_whois_proto_depIdxs,
+		MessageInfos:      file_whois_proto_msgTypes,
+	}.Build()
+	File_whois_proto = out.File
+	file_whois_proto_rawDesc = nil
+	file_whois_proto_goTypes = nil
+	file_whois_proto_depIdxs = nil
+}